@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
@@ -24,11 +25,13 @@ import (
 	"github.com/projectdiscovery/gologger/levels"
 	"github.com/projectdiscovery/interactsh/pkg/client"
 	"github.com/projectdiscovery/nuclei/v3/internal/runner"
+	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/code"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
@@ -143,6 +146,13 @@ func main() {
 
 	runner.ParseOptions(options)
 
+	if options.Worker != "" {
+		if err := nuclei.RunWorker(context.Background(), options.Worker, options.DistributedToken); err != nil {
+			gologger.Fatal().Msgf("Could not run distributed scan worker: %s\n", err)
+		}
+		return
+	}
+
 	if options.ScanUploadFile != "" {
 		if err := runner.UploadResultsToCloud(options); err != nil {
 			gologger.Fatal().Msgf("could not upload scan results to cloud dashboard: %s\n", err)
@@ -204,6 +214,12 @@ func main() {
 	if err := nucleiRunner.RunEnumeration(); err != nil {
 		if options.Validate {
 			gologger.Fatal().Msgf("Could not validate templates: %s\n", err)
+		} else if options.TestTemplates {
+			gologger.Fatal().Msgf("Could not test templates: %s\n", err)
+		} else if options.TemplateGraph != "" || options.TemplateImpact != "" {
+			gologger.Fatal().Msgf("Could not analyze template dependencies: %s\n", err)
+		} else if options.ExportBundle != "" {
+			gologger.Fatal().Msgf("Could not export template bundle: %s\n", err)
 		} else {
 			gologger.Fatal().Msgf("Could not run nuclei: %s\n", err)
 		}
@@ -255,13 +271,26 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringSliceVarP(&options.Workflows, "workflows", "w", nil, "list of workflow or workflow directory to run (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.StringSliceVarP(&options.WorkflowURLs, "workflow-url", "wurl", nil, "workflow url or list containing workflow urls to run (comma-separated, file)", goflags.FileCommaSeparatedStringSliceOptions),
 		flagSet.BoolVar(&options.Validate, "validate", false, "validate the passed templates to nuclei"),
+		flagSet.BoolVarP(&options.ValidateReferences, "validate-references", "vref", false, "fail template validation (-validate) on dangling workflow, flow, secret and payload references"),
+		flagSet.BoolVarP(&options.TestTemplates, "test-templates", "tt", false, "run offline test cases (<template>.test.yml) of the passed templates against local stub servers"),
+		flagSet.StringVarP(&options.TemplateGraph, "template-graph", "tg", "", "export the dependency graph of the passed templates and workflows to file (json, dot) failing on dangling references"),
+		flagSet.StringVarP(&options.TemplateImpact, "template-impact", "ti", "", "list the workflows, templates and secrets depending on the given template"),
 		flagSet.BoolVarP(&options.NoStrictSyntax, "no-strict-syntax", "nss", false, "disable strict syntax check on templates"),
 		flagSet.BoolVarP(&options.TemplateDisplay, "template-display", "td", false, "displays the templates content"),
 		flagSet.BoolVar(&options.TemplateList, "tl", false, "list all available templates"),
 		flagSet.BoolVar(&options.TagList, "tgl", false, "list all available tags"),
+		flagSet.StringVarP(&options.TemplateQuery, "template-query", "tq", "", "search the template metadata index with field filters and full-text terms (e.g. 'vendor:apache epss>0.5 oast:false')"),
 		flagSet.StringSliceVarConfigOnly(&options.RemoteTemplateDomainList, "remote-template-domain", []string{"cloud.projectdiscovery.io"}, "allowed domain list to load remote templates from"),
 		flagSet.BoolVar(&options.SignTemplates, "sign", false, "signs the templates with the private key defined in NUCLEI_SIGNATURE_PRIVATE_KEY env variable"),
+		flagSet.StringVarP(&options.ExportBundle, "export-bundle", "eb", "", "export the resolved templates and their files to a signed bundle for air-gapped scans"),
+		flagSet.StringVar(&options.Bundle, "bundle", "", "run templates from a signed bundle verified with the nuclei and user certificates"),
+		flagSet.StringVarP(&options.Lockfile, "lockfile", "lf", "", "lockfile to write on bundle export or to verify loaded templates against"),
 		flagSet.BoolVar(&options.EnableCodeTemplates, "code", false, "enable loading code protocol-based templates"),
+		flagSet.StringVarP(&options.CodeSandbox, "code-sandbox", "cs", "", "run code templates in an isolated container using runtime (docker, podman)"),
+		flagSet.StringVarP(&options.CodeSandboxImage, "code-sandbox-image", "csi", "", "container image to run code templates in (default: per engine)"),
+		flagSet.StringVarP(&options.CodeSandboxNetwork, "code-sandbox-network", "csn", "none", "network to attach code template containers to (none, host, bridge, <name>)"),
+		flagSet.StringVarP(&options.CodeSandboxCPUs, "code-sandbox-cpus", "csc", code.DefaultSandboxCPUs, "number of cpus available to a code template container"),
+		flagSet.StringVarP(&options.CodeSandboxMemory, "code-sandbox-memory", "csm", code.DefaultSandboxMemory, "memory available to a code template container"),
 		flagSet.BoolVarP(&options.DisableUnsignedTemplates, "disable-unsigned-templates", "dut", false, "disable running unsigned templates or templates with mismatched signature"),
 		flagSet.BoolVarP(&options.EnableSelfContainedTemplates, "enable-self-contained", "esc", false, "enable loading self-contained templates"),
 		flagSet.BoolVarP(&options.EnableGlobalMatchersTemplates, "enable-global-matchers", "egm", false, "enable loading global matchers templates"),
@@ -304,6 +333,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.StringVarP(&options.JSONExport, "json-export", "je", "", "file to export results in JSON format"),
 		flagSet.StringVarP(&options.JSONLExport, "jsonl-export", "jle", "", "file to export results in JSONL(ine) format"),
 		flagSet.StringSliceVarP(&options.Redact, "redact", "rd", nil, "redact given list of keys from query parameter, request header and body", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.RedactPolicy, "redact-policy", "rdp", "", "redaction policy file (regex, entropy and built-in detectors) applied to output, exporters and trackers"),
 	)
 
 	flagSet.CreateGroup("configs", "Configurations",
@@ -315,13 +345,16 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.IntVarP(&options.MaxRedirects, "max-redirects", "mr", 10, "max number of redirects to follow for http templates"),
 		flagSet.BoolVarP(&options.DisableRedirects, "disable-redirects", "dr", false, "disable redirects for http templates"),
 		flagSet.StringVarP(&options.ReportingConfig, "report-config", "rc", "", "nuclei reporting module configuration file"), // TODO merge into the config file or rename to issue-tracking
+		flagSet.StringVarP(&options.AssetContext, "asset-context", "ac", "", "asset context file (hosts, cidrs, environment, exposure, criticality) used to compute risk scores of results"),
+		flagSet.StringVarP(&options.SuppressionFile, "suppression-file", "supf", "", "file of accepted risks and false positives (with justification, owner and expiry) to suppress from results"),
 		flagSet.StringSliceVarP(&options.CustomHeaders, "header", "H", nil, "custom header/cookie to include in all http request in header:value format (cli, file)", goflags.FileStringSliceOptions),
 		flagSet.RuntimeMapVarP(&options.Vars, "var", "V", nil, "custom vars in key=value format"),
 		flagSet.StringVarP(&options.ResolversFile, "resolvers", "r", "", "file containing resolver list for nuclei"),
 		flagSet.BoolVarP(&options.SystemResolvers, "system-resolvers", "sr", false, "use system DNS resolving as error fallback"),
 		flagSet.BoolVarP(&options.DisableClustering, "disable-clustering", "dc", false, "disable clustering of requests"),
-		flagSet.BoolVar(&options.OfflineHTTP, "passive", false, "enable passive HTTP response processing mode"),
+		flagSet.BoolVar(&options.OfflineHTTP, "passive", false, "enable passive HTTP response processing mode (raw responses, har, warc, pcap)"),
 		flagSet.BoolVarP(&options.ForceAttemptHTTP2, "force-http2", "fh2", false, "force http2 connection on requests"),
+		flagSet.BoolVarP(&options.ForceAttemptHTTP3, "force-http3", "fh3", false, "force http3 (quic) connection on https requests"),
 		flagSet.BoolVarP(&options.EnvironmentVariables, "env-vars", "ev", false, "enable environment variables to be used in template"),
 		flagSet.StringVarP(&options.ClientCertFile, "client-cert", "cc", "", "client certificate file (PEM-encoded) used for authenticating against scanned hosts"),
 		flagSet.StringVarP(&options.ClientKeyFile, "client-key", "ck", "", "client key file (PEM-encoded) used for authenticating against scanned hosts"),
@@ -375,6 +408,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.IntVarP(&options.RateLimit, "rate-limit", "rl", 150, "maximum number of requests to send per second"),
 		flagSet.DurationVarP(&options.RateLimitDuration, "rate-limit-duration", "rld", time.Second, "maximum number of requests to send per second"),
 		flagSet.IntVarP(&options.RateLimitMinute, "rate-limit-minute", "rlm", 0, "maximum number of requests to send per minute (DEPRECATED)"),
+		flagSet.StringSliceVarP(&options.HostRateLimits, "host-rate-limit", "hrl", nil, "per host rate limit of http requests in pattern=rate format, rate per second or per minute with /m (e.g. *.example.com=10,api.example.com=60/m)", goflags.FileCommaSeparatedStringSliceOptions),
+		flagSet.BoolVarP(&options.AdaptiveRateLimit, "adaptive-rate-limit", "arl", false, "slow down http hosts on 429/503, retry-after and latency spikes and recover gradually"),
 		flagSet.IntVarP(&options.BulkSize, "bulk-size", "bs", 25, "maximum number of hosts to be analyzed in parallel per template"),
 		flagSet.IntVarP(&options.TemplateThreads, "concurrency", "c", 25, "maximum number of templates to be executed in parallel"),
 		flagSet.IntVarP(&options.HeadlessBulkSize, "headless-bulk-size", "hbs", 10, "maximum number of headless hosts to be analyzed in parallel per template"),
@@ -399,6 +434,7 @@ on extensive configurability, massive extensibility and ease of use.`)
 			scanstrategy.HostSpray.String():     goflags.EnumVariable(1),
 			scanstrategy.TemplateSpray.String(): goflags.EnumVariable(2),
 		}),
+		flagSet.StringVar(&options.Shard, "shard", "", "scan only the i/n deterministic shard of the template x target pairs (e.g. 1/4)"),
 		flagSet.DurationVarP(&options.InputReadTimeout, "input-read-timeout", "irt", time.Duration(3*time.Minute), "timeout on input read"),
 		flagSet.BoolVarP(&options.DisableHTTPProbe, "no-httpx", "nh", false, "disable httpx probing for non-url input"),
 		flagSet.BoolVar(&options.DisableStdin, "no-stdin", false, "disable stdin processing"),
@@ -422,6 +458,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.ListDslSignatures, "list-dsl-function", "ldf", false, "list all supported DSL function signatures"),
 		flagSet.StringVarP(&options.TraceLogFile, "trace-log", "tlog", "", "file to write sent requests trace log"),
 		flagSet.StringVarP(&options.ErrorLogFile, "error-log", "elog", "", "file to write sent requests error log"),
+		flagSet.StringVarP(&options.RecordTraffic, "record-traffic", "rect", "", "file to record all protocol exchanges into for later replay"),
+		flagSet.StringVarP(&options.ReplayTraffic, "replay-traffic", "rpt", "", "replay protocol exchanges from a recorded traffic file without network access"),
 		flagSet.CallbackVar(printVersion, "version", "show nuclei version"),
 		flagSet.BoolVarP(&options.HangMonitor, "hang-monitor", "hm", false, "enable nuclei hang monitoring"),
 		flagSet.BoolVarP(&options.Verbose, "verbose", "v", false, "show verbose output"),
@@ -446,6 +484,8 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.StatsJSON, "stats-json", "sj", false, "display statistics in JSONL(ines) format"),
 		flagSet.IntVarP(&options.StatsInterval, "stats-interval", "si", 5, "number of seconds to wait between showing a statistics update"),
 		flagSet.IntVarP(&options.MetricsPort, "metrics-port", "mp", 9092, "port to expose nuclei metrics on"),
+		flagSet.StringVarP(&options.PrometheusAddr, "prometheus-addr", "pa", "", "address to expose prometheus metrics on (e.g. 127.0.0.1:9093)"),
+		flagSet.StringVarP(&options.OTelEndpoint, "otel-endpoint", "ote", "", "opentelemetry otlp/http endpoint to export traces to (e.g. localhost:4318)"),
 	)
 
 	flagSet.CreateGroup("cloud", "Cloud",
@@ -463,6 +503,14 @@ on extensive configurability, massive extensibility and ease of use.`)
 		flagSet.BoolVarP(&options.PreFetchSecrets, "prefetch-secrets", "ps", false, "prefetch secrets from the secrets file"),
 	)
 
+	flagSet.CreateGroup("distributed", "Distributed",
+		flagSet.StringVarP(&options.Coordinator, "coordinator", "coord", "", "run as coordinator of a distributed scan listening for workers on given address (e.g. 127.0.0.1:8822)"),
+		flagSet.StringVar(&options.Worker, "worker", "", "run as distributed scan worker of the coordinator at given url"),
+		flagSet.StringVarP(&options.DistributedToken, "distributed-token", "dtk", "", "token authenticating workers with the coordinator of a distributed scan (env NUCLEI_DISTRIBUTED_TOKEN, random on coordinator if empty)"),
+		flagSet.IntVarP(&options.DistributedUnitSize, "unit-size", "us", 25, "number of targets per work unit of a distributed scan"),
+		flagSet.IntVarP(&options.DistributedMaxAttempts, "unit-attempts", "ua", 3, "number of attempts of a work unit of a distributed scan"),
+	)
+
 	flagSet.SetCustomHelpText(`EXAMPLES:
 Run nuclei on single host:
 	$ nuclei -target example.com
@@ -479,6 +527,10 @@ Run nuclei with a JSON output:
 Run nuclei with sorted Markdown outputs (with environment variables):
 	$ MARKDOWN_EXPORT_SORT_MODE=template nuclei -target example.com -markdown-export nuclei_report/
 
+Run a distributed scan with a coordinator and workers (the rate limit applies to each worker):
+	$ NUCLEI_DISTRIBUTED_TOKEN=secret nuclei -list hosts.txt -coordinator 127.0.0.1:8822
+	$ NUCLEI_DISTRIBUTED_TOKEN=secret nuclei -worker http://127.0.0.1:8822
+
 Additional documentation is available at: https://docs.nuclei.sh/getting-started/running
 	`)
 
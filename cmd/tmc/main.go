@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -21,6 +22,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/lint"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/retryablehttp-go"
 	errorutil "github.com/projectdiscovery/utils/errors"
@@ -79,14 +81,19 @@ func init() {
 }
 
 type options struct {
-	input        string
-	errorLogFile string
-	lint         bool
-	validate     bool
-	format       bool
-	enhance      bool
-	maxRequest   bool
-	debug        bool
+	input            string
+	errorLogFile     string
+	lint             bool
+	lintRules        goflags.StringSlice
+	excludeLintRules goflags.StringSlice
+	lintFormat       string
+	lintOutput       string
+	listLintRules    bool
+	validate         bool
+	format           bool
+	enhance          bool
+	maxRequest       bool
+	debug            bool
 }
 
 func main() {
@@ -99,7 +106,7 @@ func main() {
 	)
 
 	flagSet.CreateGroup("Config", "config",
-		flagSet.BoolVarP(&opts.lint, "lint", "l", false, "lint given nuclei template"),
+		flagSet.BoolVarP(&opts.lint, "lint", "l", false, "lint given nuclei template with local lint rules"),
 		flagSet.BoolVarP(&opts.validate, "validate", "v", false, "validate given nuclei template"),
 		flagSet.BoolVarP(&opts.format, "format", "f", false, "format given nuclei template"),
 		flagSet.BoolVarP(&opts.enhance, "enhance", "e", false, "enhance given nuclei template"),
@@ -108,10 +115,24 @@ func main() {
 		flagSet.BoolVarP(&opts.debug, "debug", "d", false, "show debug message"),
 	)
 
+	flagSet.CreateGroup("Lint", "lint",
+		flagSet.StringSliceVarP(&opts.lintRules, "lint-rules", "lr", nil, "lint rules to run (comma separated, default all)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&opts.excludeLintRules, "exclude-lint-rules", "elr", nil, "lint rules to skip (comma separated)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&opts.lintFormat, "lint-format", "lf", lint.FormatText, "lint output format (text, json, sarif)"),
+		flagSet.StringVarP(&opts.lintOutput, "lint-output", "lo", "", "file to write lint findings to (default stdout)"),
+		flagSet.BoolVarP(&opts.listLintRules, "list-lint-rules", "llr", false, "list available lint rules"),
+	)
+
 	if err := flagSet.Parse(); err != nil {
 		gologger.Fatal().Msgf("Error parsing flags: %s\n", err)
 	}
 
+	if opts.listLintRules {
+		for _, rule := range lint.Rules() {
+			gologger.Silent().Msgf("%s [%s]: %s\n", rule.ID(), rule.Level(), rule.Description())
+		}
+		return
+	}
 	if opts.input == "" {
 		gologger.Fatal().Msg("input template path/directory is required")
 	}
@@ -128,9 +149,15 @@ func main() {
 	}
 	if err := process(opts); err != nil {
 		gologger.Error().Msgf("could not process: %s\n", err)
+		if errors.Is(err, errLintFailed) {
+			os.Exit(1)
+		}
 	}
 }
 
+// errLintFailed is returned when linting reports error level findings
+var errLintFailed = errors.New("lint failed")
+
 func process(opts options) error {
 	tempDir, err := os.MkdirTemp("", "nuclei-nvd-%s")
 	if err != nil {
@@ -152,6 +179,15 @@ func process(opts options) error {
 	if err != nil {
 		return err
 	}
+
+	var linter *lint.Linter
+	var findings []*lint.Finding
+	if opts.lint {
+		linter, err = lint.New(&lint.Options{Rules: opts.lintRules, ExcludeRules: opts.excludeLintRules})
+		if err != nil {
+			return err
+		}
+	}
 	for _, path := range paths {
 		data, err := os.ReadFile(path)
 		if err != nil {
@@ -173,13 +209,16 @@ func process(opts options) error {
 		}
 
 		if opts.lint {
-			lint, err := lintTemplate(dataString)
+			templateFindings, err := linter.Lint(path, []byte(dataString))
 			if err != nil {
-				gologger.Info().Label("lint").Msg(logErrMsg(path, err, opts.debug, errFile))
+				templateFindings = []*lint.Finding{{RuleID: "parse-error", Level: lint.LevelError, Message: err.Error(), Path: path}}
 			}
-			if lint {
+			if len(templateFindings) == 0 {
 				gologger.Info().Label("lint").Msgf("✅ lint template: %s\n", path)
+			} else {
+				gologger.Info().Label("lint").Msg(logErrMsg(path, errorutil.New("%d lint finding(s)", len(templateFindings)), opts.debug, errFile))
 			}
+			findings = append(findings, templateFindings...)
 		}
 
 		if opts.validate {
@@ -218,6 +257,29 @@ func process(opts options) error {
 			}
 		}
 	}
+	if opts.lint {
+		return writeLintFindings(opts, linter, findings)
+	}
+	return nil
+}
+
+// writeLintFindings writes the lint findings in the requested format
+func writeLintFindings(opts options, linter *lint.Linter, findings []*lint.Finding) error {
+	writer := os.Stdout
+	if opts.lintOutput != "" {
+		file, err := os.Create(opts.lintOutput)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	if err := lint.Write(writer, opts.lintFormat, findings, linter.Rules()); err != nil {
+		return err
+	}
+	if lint.HasErrors(findings) {
+		return errLintFailed
+	}
 	return nil
 }
 
@@ -301,28 +363,6 @@ func formatTemplate(data string) (string, bool, error) {
 	return data, false, errorutil.New("template format failed")
 }
 
-// lintTemplate lints template data using templateman lint api
-func lintTemplate(data string) (bool, error) {
-	resp, err := retryablehttp.DefaultClient().Post(fmt.Sprintf("%s/lint", tmBaseUrl), "application/x-yaml", strings.NewReader(data))
-	if err != nil {
-		return false, err
-	}
-	if resp.StatusCode != 200 {
-		return false, errorutil.New("unexpected status code: %v", resp.Status)
-	}
-	var lintResp TemplateLintResp
-	if err := json.NewDecoder(resp.Body).Decode(&lintResp); err != nil {
-		return false, err
-	}
-	if lintResp.Lint {
-		return true, nil
-	}
-	if lintResp.LintError.Reason != "" {
-		return false, errorutil.NewWithTag("lint", lintResp.LintError.Reason+" : at line %v", lintResp.LintError.Mark.Line)
-	}
-	return false, errorutil.NewWithTag("lint", "at line: %v", lintResp.LintError.Mark.Line)
-}
-
 // validateTemplate validates template data using templateman validate api
 func validateTemplate(data string) (bool, error) {
 	resp, err := retryablehttp.DefaultClient().Post(fmt.Sprintf("%s/validate", tmBaseUrl), "application/x-yaml", strings.NewReader(data))
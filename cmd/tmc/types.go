@@ -19,12 +19,6 @@ type LintError struct {
 	Mark   Mark   `json:"mark,omitempty"`
 }
 
-type TemplateLintResp struct {
-	Input     string    `json:"template_input,omitempty"`
-	Lint      bool      `json:"template_lint,omitempty"`
-	LintError LintError `json:"lint_error,omitempty"`
-}
-
 type ValidateError struct {
 	Location string      `json:"location,omitempty"`
 	Message  string      `json:"message,omitempty"`
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader"
+	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/graph"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
+)
+
+// importBundle extracts the -bundle to a temporary directory after verifying
+// its signature and uses it as the templates directory without updates.
+//
+// The directory is removed if the import fails or once the runner is closed.
+func (r *Runner) importBundle() (err error) {
+	dir, err := os.MkdirTemp("", "nuclei-bundle-*")
+	if err != nil {
+		return errors.Wrap(err, "could not create bundle directory")
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+
+	lockfile, err := installer.ImportBundle(r.options.Bundle, dir, signer.DefaultTemplateVerifiers)
+	if err != nil {
+		return errors.Wrap(err, "could not import template bundle")
+	}
+	r.bundleDir = dir
+	config.DefaultConfig.SetTemplatesDir(dir)
+	config.DefaultConfig.DisableUpdateCheck()
+	gologger.Info().Msgf("Imported %d files from bundle %s (nuclei %s, templates %s)\n", len(lockfile.Templates), r.options.Bundle, lockfile.NucleiVersion, lockfile.TemplatesVersion)
+	return nil
+}
+
+// resolvedTemplateFiles returns the paths of the loaded templates and
+// workflows along with the templates and files they reference.
+func (r *Runner) resolvedTemplateFiles(store *loader.Store) ([]string, error) {
+	var paths []string
+	for _, template := range append(store.Templates(), store.Workflows()...) {
+		paths = append(paths, template.Path)
+	}
+	templateGraph, err := graph.Build(r.options, r.catalog, paths, r.options.SecretsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build template dependency graph")
+	}
+	for _, edge := range templateGraph.Dangling() {
+		gologger.Warning().Msgf("Dangling template reference %s\n", edge)
+	}
+
+	files := make([]string, 0, len(templateGraph.Nodes))
+	for _, node := range templateGraph.Nodes {
+		// secret files contain credentials and are never bundled
+		if node.Kind == graph.NodeSecret {
+			continue
+		}
+		files = append(files, node.ID)
+	}
+	return files, nil
+}
+
+// exportBundle exports the resolved templates to the -export-bundle file
+// signed with the user key and writes the -lockfile if requested.
+func (r *Runner) exportBundle(store *loader.Store) error {
+	files, err := r.resolvedTemplateFiles(store)
+	if err != nil {
+		return err
+	}
+	lockfile, err := installer.NewLockfile(files)
+	if err != nil {
+		return errors.Wrap(err, "could not create lockfile")
+	}
+	templateSigner, err := signer.NewTemplateSigner(nil, nil) // will read from env, config or generate new keys
+	if err != nil {
+		return errors.Wrap(err, "could not initialize template signer")
+	}
+	if err := installer.ExportBundle(r.options.ExportBundle, lockfile, templateSigner); err != nil {
+		return err
+	}
+	gologger.Info().Msgf("Exported %d files to bundle %s\n", len(lockfile.Templates), r.options.ExportBundle)
+
+	if r.options.Lockfile != "" {
+		if err := lockfile.Write(r.options.Lockfile, templateSigner); err != nil {
+			return errors.Wrap(err, "could not write lockfile")
+		}
+		gologger.Info().Msgf("Lockfile written to %s\n", r.options.Lockfile)
+	}
+	return nil
+}
+
+// verifyLockfile verifies the resolved templates against the -lockfile.
+func (r *Runner) verifyLockfile(store *loader.Store) error {
+	data, err := os.ReadFile(r.options.Lockfile)
+	if err != nil {
+		return errors.Wrap(err, "could not read lockfile")
+	}
+	// signed lockfiles must be signed by a trusted certificate
+	var verifiers []*signer.TemplateSigner
+	if signature, _ := signer.ExtractSignatureAndContent(data); len(signature) > 0 {
+		verifiers = signer.DefaultTemplateVerifiers
+	}
+	lockfile, err := installer.ParseLockfile(data, verifiers...)
+	if err != nil {
+		return errors.Wrap(err, "could not parse lockfile")
+	}
+	files, err := r.resolvedTemplateFiles(store)
+	if err != nil {
+		return err
+	}
+	return lockfile.Verify(files)
+}
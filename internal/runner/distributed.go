@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader"
+	"github.com/projectdiscovery/nuclei/v3/pkg/distributed"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+// executeDistributed coordinates the execution of the loaded templates
+// on the input by distributed scan workers
+func (r *Runner) executeDistributed(store *loader.Store) (*atomic.Bool, error) {
+	if len(store.Workflows()) > 0 {
+		gologger.Warning().Msgf("Skipping %d workflows not supported by distributed scans\n", len(store.Workflows()))
+	}
+	if r.inputProvider == nil {
+		return nil, errors.New("no input provider found")
+	}
+
+	var targets []string
+	var unsupported bool
+	r.inputProvider.Iterate(func(value *contextargs.MetaInput) bool {
+		if value.ReqResp != nil {
+			unsupported = true
+			return false
+		}
+		targets = append(targets, value.Input)
+		return true
+	})
+	if unsupported {
+		return nil, errors.New("distributed scans only support list inputs")
+	}
+
+	var templatePaths []string
+	for _, template := range store.Templates() {
+		templatePaths = append(templatePaths, template.Path)
+	}
+	if len(templatePaths) == 0 {
+		return nil, errors.New("no templates provided for scan")
+	}
+
+	token := r.options.DistributedToken
+	if token == "" {
+		generated, err := distributed.NewToken()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not generate coordinator token")
+		}
+		token = generated
+		gologger.Info().Msgf("Workers must authenticate with token %s (-distributed-token or %s)", token, distributed.TokenEnv)
+	}
+
+	units := distributed.Shard(templatePaths, targets, r.options.DistributedUnitSize)
+	coordinator := distributed.NewCoordinator(&distributed.Options{
+		Address:      r.options.Coordinator,
+		Token:        token,
+		Units:        units,
+		MaxAttempts:  r.options.DistributedMaxAttempts,
+		Config:       distributed.NewWorkerConfig(r.options),
+		Output:       r.output,
+		IssuesClient: r.issuesClient,
+	})
+	if err := coordinator.Start(); err != nil {
+		return nil, errors.Wrap(err, "could not start coordinator")
+	}
+	defer coordinator.Close()
+
+	gologger.Info().Msgf("Coordinating %d work units on %s", len(units), coordinator.Address())
+	if r.options.RateLimit > 0 {
+		gologger.Info().Msgf("Rate limit of %d requests applies to each worker", r.options.RateLimit)
+	}
+	if err := coordinator.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+	if failed := coordinator.Failed(); failed > 0 {
+		gologger.Warning().Msgf("%d work units failed after all attempts\n", failed)
+	}
+
+	results := &atomic.Bool{}
+	results.Store(coordinator.Results() > 0)
+	return results, nil
+}
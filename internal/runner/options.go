@@ -18,6 +18,10 @@ import (
 	"github.com/projectdiscovery/gologger/formatter"
 	"github.com/projectdiscovery/gologger/levels"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/index"
+	"github.com/projectdiscovery/nuclei/v3/pkg/distributed"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/code"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
@@ -28,6 +32,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/exporters/sarif"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/extensions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types/shard"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
 	fileutil "github.com/projectdiscovery/utils/file"
 	"github.com/projectdiscovery/utils/generic"
@@ -135,6 +140,12 @@ func ParseOptions(options *types.Options) {
 	if options.OfflineHTTP {
 		options.DisableHTTPProbe = true
 	}
+
+	// no network access is available while replaying recorded traffic
+	if options.ReplayTraffic != "" {
+		options.DisableHTTPProbe = true
+		options.NoInteractsh = true
+	}
 }
 
 // validateOptions validates the configuration options passed
@@ -158,6 +169,38 @@ func ValidateOptions(options *types.Options) error {
 		return errors.New("headless mode (-headless) is required if -ho, -sb, -sc or -lha are set")
 	}
 
+	if options.ValidateReferences && !options.Validate {
+		return errors.New("template validation (-validate) is required if -vref is set")
+	}
+
+	if options.RecordTraffic != "" && options.ReplayTraffic != "" {
+		return errors.New("both record traffic and replay traffic specified")
+	}
+	if options.ReplayTraffic != "" && options.Project {
+		return errors.New("project file cannot be used while replaying traffic")
+	}
+	if options.Coordinator != "" && options.Worker != "" {
+		return errors.New("both coordinator and worker mode specified")
+	}
+	if options.Worker != "" && options.DistributedToken == "" {
+		return errors.Errorf("no coordinator token specified, use -distributed-token or %s", distributed.TokenEnv)
+	}
+	if _, err := shard.Parse(options.Shard); err != nil {
+		return err
+	}
+	if options.TemplateQuery != "" {
+		if _, err := index.ParseQuery(options.TemplateQuery); err != nil {
+			return errors.Wrap(err, "invalid template query")
+		}
+	}
+	for _, value := range options.HostRateLimits {
+		if _, err := hostratelimit.ParseRule(value); err != nil {
+			return err
+		}
+	}
+	if options.CodeSandbox != "" && !stringsutil.EqualFoldAny(options.CodeSandbox, code.SandboxRuntimes...) {
+		return fmt.Errorf("unsupported code sandbox runtime %s (%s)", options.CodeSandbox, strings.Join(code.SandboxRuntimes, ", "))
+	}
 	if options.FollowHostRedirects && options.FollowRedirects {
 		return errors.New("both follow host redirects and follow redirects specified")
 	}
@@ -168,6 +211,9 @@ func ValidateOptions(options *types.Options) error {
 	if err := loadProxyServers(options); err != nil {
 		return err
 	}
+	if options.ForceAttemptHTTP3 && (options.AliveHttpProxy != "" || options.AliveSocksProxy != "") {
+		return errors.New("http3 (-fh3) can't be used with a proxy")
+	}
 	if options.Validate {
 		validateTemplatePaths(config.DefaultConfig.TemplatesDirectory, options.Templates, options.Workflows)
 	}
@@ -448,6 +494,11 @@ func readEnvInputVars(options *types.Options) {
 	options.CodeTemplateSignaturePublicKey = os.Getenv("NUCLEI_SIGNATURE_PUBLIC_KEY")
 	options.CodeTemplateSignatureAlgorithm = os.Getenv("NUCLEI_SIGNATURE_ALGORITHM")
 
+	// Token authenticating workers with the coordinator of a distributed scan
+	if options.DistributedToken == "" {
+		options.DistributedToken = os.Getenv(distributed.TokenEnv)
+	}
+
 	// General options to disable the template download locations from being used.
 	// This will override the default behavior of downloading templates from the default locations as well as the
 	// custom locations.
@@ -37,12 +37,14 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/index"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader"
 	"github.com/projectdiscovery/nuclei/v3/pkg/core"
 	"github.com/projectdiscovery/nuclei/v3/pkg/external/customtemplates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/input"
 	parsers "github.com/projectdiscovery/nuclei/v3/pkg/loader/workflow"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/pipeline"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/projectfile"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
@@ -50,6 +52,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/globalmatchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/uncover"
@@ -57,8 +60,13 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
 	httpProtocol "github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/redact"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/risk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/suppress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/utils/stats"
@@ -80,12 +88,15 @@ type Runner struct {
 	interactsh         *interactsh.Client
 	options            *types.Options
 	projectFile        *projectfile.ProjectFile
+	trafficArchive     *traffic.Archive
 	catalog            catalog.Catalog
 	progress           progress.Progress
 	colorizer          aurora.Aurora
 	issuesClient       reporting.Client
+	suppressor         *suppress.Suppressor
 	browser            *engine.Browser
 	rateLimiter        *ratelimit.Limiter
+	hostRateLimiter    *hostratelimit.Limiter
 	hostErrors         hosterrorscache.CacheInterface
 	resumeCfg          *types.ResumeCfg
 	pprofServer        *http.Server
@@ -93,7 +104,9 @@ type Runner struct {
 	inputProvider      provider.InputProvider
 	fuzzFrequencyCache *frequency.Tracker
 	//general purpose temporary directory
-	tmpDir          string
+	tmpDir string
+	// bundleDir is the directory the template bundle is extracted to
+	bundleDir       string
 	parser          parser.Parser
 	httpApiEndpoint *httpapi.Server
 }
@@ -111,6 +124,12 @@ func New(options *types.Options) (*Runner, error) {
 		os.Exit(0)
 	}
 
+	if options.Bundle != "" {
+		if err := runner.importBundle(); err != nil {
+			return nil, err
+		}
+	}
+
 	//  Version check by default
 	if config.DefaultConfig.CanCheckForUpdates() {
 		if err := installer.NucleiVersionCheck(); err != nil {
@@ -209,6 +228,23 @@ func New(options *types.Options) (*Runner, error) {
 		runner.issuesClient = client
 	}
 
+	// redact the evidence of results with the redaction policy if given
+	redactor, err := redact.New(options.RedactPolicy, options.Redact)
+	if err != nil {
+		return nil, err
+	}
+
+	// compute risk scores of results from the asset context if given
+	riskScorer, err := risk.New(options.AssetContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// drop accepted risks and false positives from output and trackers
+	if runner.suppressor, err = suppress.New(options.SuppressionFile); err != nil {
+		return nil, err
+	}
+
 	// output coloring
 	useColor := !options.NoColor
 	runner.colorizer = aurora.NewAurora(useColor)
@@ -254,9 +290,21 @@ func New(options *types.Options) (*Runner, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create output file")
 	}
+	if redactor != nil {
+		// keys to redact are part of the redaction policy
+		outputWriter.KeysToRedact = nil
+	}
 	// setup a proxy writer to automatically upload results to PDCP
 	runner.output = runner.setupPDCPUpload(outputWriter)
 
+	// serve prometheus metrics and export traces if requested
+	if err := telemetry.Init(options); err != nil {
+		return nil, err
+	}
+	runner.output = telemetry.NewWriter(runner.output)
+	// results are processed the same way for the output and the trackers
+	runner.output, runner.issuesClient = pipeline.New(redactor, runner.suppressor, riskScorer).Apply(runner.output, runner.issuesClient)
+
 	if options.JSONL && options.EnableProgressBar {
 		options.StatsJSON = true
 	}
@@ -280,6 +328,19 @@ func New(options *types.Options) (*Runner, error) {
 		}
 	}
 
+	// create the traffic archive to record exchanges into or replay them from
+	if options.RecordTraffic != "" {
+		var archiveErr error
+		if runner.trafficArchive, archiveErr = traffic.Create(options.RecordTraffic); archiveErr != nil {
+			return nil, archiveErr
+		}
+	} else if options.ReplayTraffic != "" {
+		var archiveErr error
+		if runner.trafficArchive, archiveErr = traffic.Open(options.ReplayTraffic); archiveErr != nil {
+			return nil, archiveErr
+		}
+	}
+
 	// create the resume configuration structure
 	resumeCfg := types.NewResumeCfg()
 	if runner.options.ShouldLoadResume() {
@@ -344,6 +405,9 @@ func New(options *types.Options) (*Runner, error) {
 	} else {
 		runner.rateLimiter = ratelimit.New(context.Background(), uint(options.RateLimit), options.RateLimitDuration)
 	}
+	if runner.hostRateLimiter, err = hostratelimit.NewFromOptions(options); err != nil {
+		return nil, err
+	}
 
 	if tmpDir, err := os.MkdirTemp("", "nuclei-tmp-*"); err == nil {
 		runner.tmpDir = tmpDir
@@ -357,6 +421,9 @@ func (r *Runner) runStandardEnumeration(executerOpts protocols.ExecutorOptions,
 	if r.options.AutomaticScan {
 		return r.executeSmartWorkflowInput(executerOpts, store, engine)
 	}
+	if r.options.Coordinator != "" {
+		return r.executeDistributed(store)
+	}
 	return r.executeTemplatesInput(store, engine)
 }
 
@@ -375,13 +442,22 @@ func (r *Runner) Close() {
 	if r.projectFile != nil {
 		r.projectFile.Close()
 	}
+	if r.trafficArchive != nil {
+		if err := r.trafficArchive.Close(); err != nil {
+			gologger.Error().Msgf("Could not close traffic archive: %s\n", err)
+		}
+	}
 	if r.inputProvider != nil {
 		r.inputProvider.Close()
 	}
 	protocolinit.Close()
+	telemetry.Close()
 	if r.pprofServer != nil {
 		_ = r.pprofServer.Shutdown(context.Background())
 	}
+	for _, stats := range r.hostRateLimiter.Stats() {
+		gologger.Info().Msgf("Host %s was slowed down %d times (rate %.2f/s)", stats.Host, stats.Throttled, stats.Rate)
+	}
 	if r.rateLimiter != nil {
 		r.rateLimiter.Stop()
 	}
@@ -392,6 +468,9 @@ func (r *Runner) Close() {
 	if r.tmpDir != "" {
 		_ = os.RemoveAll(r.tmpDir)
 	}
+	if r.bundleDir != "" {
+		_ = os.RemoveAll(r.bundleDir)
+	}
 
 	//this is no-op unless nuclei is built with stats build tag
 	events.Close()
@@ -469,8 +548,10 @@ func (r *Runner) RunEnumeration() error {
 		Catalog:             r.catalog,
 		IssuesClient:        r.issuesClient,
 		RateLimiter:         r.rateLimiter,
+		HostRateLimiter:     r.hostRateLimiter,
 		Interactsh:          r.interactsh,
 		ProjectFile:         r.projectFile,
+		TrafficArchive:      r.trafficArchive,
 		Browser:             r.browser,
 		Colorizer:           r.colorizer,
 		ResumeCfg:           r.resumeCfg,
@@ -480,6 +561,7 @@ func (r *Runner) RunEnumeration() error {
 		Parser:              r.parser,
 		FuzzParamsFrequency: fuzzFreqCache,
 		GlobalMatchers:      globalmatchers.New(),
+		AltSvcCache:         httpclientpool.NewAltSvcCache(),
 	}
 
 	if config.DefaultConfig.IsDebugArgEnabled(config.DebugExportURLPattern) {
@@ -536,6 +618,9 @@ func (r *Runner) RunEnumeration() error {
 		// if input type is not list (implicitly enable fuzzing)
 		r.options.DAST = true
 	}
+	if r.options.TemplateQuery != "" {
+		loaderConfig.MetadataIndex = index.New(templateIndexFingerprint(loaderConfig))
+	}
 	store, err := loader.New(loaderConfig)
 	if err != nil {
 		return errors.Wrap(err, "Could not create loader.")
@@ -545,6 +630,9 @@ func (r *Runner) RunEnumeration() error {
 	// This uses a separate parser to reduce time taken as
 	// normally nuclei does a lot of compilation and stuff
 	// for templates, which we don't want for these simp
+	if r.options.TemplateQuery != "" {
+		return r.queryTemplateIndex(store, loaderConfig.MetadataIndex)
+	}
 	if r.options.TemplateList || r.options.TemplateDisplay || r.options.TagList {
 		if err := store.LoadTemplatesOnlyMetadata(); err != nil {
 			return err
@@ -562,6 +650,11 @@ func (r *Runner) RunEnumeration() error {
 		if err := store.ValidateTemplates(); err != nil {
 			return err
 		}
+		if r.options.ValidateReferences {
+			if err := r.validateTemplateReferences(); err != nil {
+				return err
+			}
+		}
 		if stats.GetValue(templates.SyntaxErrorStats) == 0 && stats.GetValue(templates.SyntaxWarningStats) == 0 && stats.GetValue(templates.RuntimeWarningsStats) == 0 {
 			gologger.Info().Msgf("All templates validated successfully\n")
 		} else {
@@ -569,7 +662,21 @@ func (r *Runner) RunEnumeration() error {
 		}
 		return nil // exit
 	}
+	if r.options.TestTemplates {
+		return r.runTemplateTests()
+	}
+	if r.options.TemplateGraph != "" || r.options.TemplateImpact != "" {
+		return r.runTemplateGraph()
+	}
 	store.Load()
+	if r.options.ExportBundle != "" {
+		return r.exportBundle(store)
+	}
+	if r.options.Lockfile != "" {
+		if err := r.verifyLockfile(store); err != nil {
+			return err
+		}
+	}
 	// TODO: remove below functions after v3 or update warning messages
 	disk.PrintDeprecatedPathsMsgIfApplicable(r.options.Silent)
 	templates.PrintDeprecatedProtocolNameMsgIfApplicable(r.options.Silent, r.options.Verbose)
@@ -649,6 +756,7 @@ func (r *Runner) RunEnumeration() error {
 	if v := ptrutil.Safe(results); !v.Load() {
 		gologger.Info().Msgf("No results found. Better luck next time!")
 	}
+	r.suppressor.Summary()
 	// check if a passive scan was requested but no target was provided
 	if r.options.OfflineHTTP && len(r.options.Targets) == 0 && r.options.TargetsFilePath == "" {
 		return errors.Wrap(err, "missing required input (http response) to run passive templates")
@@ -10,6 +10,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/logrusorgru/aurora"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/index"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader"
 
 	"github.com/projectdiscovery/gologger"
@@ -107,6 +108,65 @@ func (r *Runner) listAvailableStoreTags(store *loader.Store) {
 	}
 }
 
+// templateIndexFingerprint returns the fingerprint of the loader filters
+// deciding which templates are part of the metadata index.
+func templateIndexFingerprint(loaderConfig *loader.Config) string {
+	return index.Fingerprint(map[string]interface{}{
+		"templates-directory": config.DefaultConfig.TemplatesDirectory,
+		"templates":           loaderConfig.Templates,
+		"template-urls":       loaderConfig.TemplateURLs,
+		"exclude-templates":   loaderConfig.ExcludeTemplates,
+		"include-templates":   loaderConfig.IncludeTemplates,
+		"tags":                loaderConfig.Tags,
+		"exclude-tags":        loaderConfig.ExcludeTags,
+		"include-tags":        loaderConfig.IncludeTags,
+		"authors":             loaderConfig.Authors,
+		"severities":          loaderConfig.Severities.String(),
+		"exclude-severities":  loaderConfig.ExcludeSeverities.String(),
+		"protocols":           loaderConfig.Protocols.String(),
+		"exclude-protocols":   loaderConfig.ExcludeProtocols.String(),
+		"include-ids":         loaderConfig.IncludeIds,
+		"exclude-ids":         loaderConfig.ExcludeIds,
+		"include-conditions":  loaderConfig.IncludeConditions,
+	})
+}
+
+// queryTemplateIndex searches the persisted template metadata index, rebuilding
+// it from the store when templates or filters changed since it was saved.
+func (r *Runner) queryTemplateIndex(store *loader.Store, templateIndex *index.Index) error {
+	query, err := index.ParseQuery(r.options.TemplateQuery)
+	if err != nil {
+		return err
+	}
+	indexPath := index.DefaultPath()
+	templatePaths := store.TemplatePaths()
+	if cached, err := index.Load(indexPath); err == nil && cached.Fresh(templateIndex.Fingerprint, templatePaths) {
+		templateIndex = cached
+	} else {
+		gologger.Info().Msgf("Building template metadata index for %d templates", len(templatePaths))
+		if err := store.LoadTemplatesOnlyMetadata(); err != nil {
+			return err
+		}
+		templateIndex.AddFiles(templatePaths)
+		if err := templateIndex.Save(indexPath); err != nil {
+			gologger.Warning().Msgf("Could not save template metadata index: %s\n", err)
+		}
+	}
+
+	entries := templateIndex.Search(query)
+	gologger.Info().Msgf("Found %d of %d indexed templates matching query", len(entries), len(templateIndex.Entries))
+	for _, entry := range entries {
+		if r.options.JSONL {
+			marshalled, _ := jsoniter.Marshal(entry)
+			gologger.Silent().Msgf("%s\n", string(marshalled))
+		} else {
+			path := strings.TrimPrefix(entry.Path, config.DefaultConfig.TemplatesDirectory+string(filepath.Separator))
+			gologger.Silent().Msgf("%s %s\n", templates.TemplateLogMessage(entry.ID, entry.Name, entry.Authors, entry.Severity.Severity), path)
+		}
+	}
+	return nil
+}
+
 func (r *Runner) highlightTemplate(body *[]byte) ([]byte, error) {
 	var buf bytes.Buffer
 	// YAML lexer, true color terminal formatter and monokai style
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/graph"
+)
+
+// buildTemplateGraph builds the dependency graph of the templates and
+// workflows passed with -t and -w, or of the whole templates directory
+// if none were passed.
+func (r *Runner) buildTemplateGraph() (*graph.Graph, error) {
+	definitions := append(append([]string{}, r.options.Templates...), r.options.Workflows...)
+	if len(definitions) == 0 {
+		definitions = []string{config.DefaultConfig.TemplatesDirectory}
+	}
+	templatePaths, errs := r.catalog.GetTemplatesPath(definitions)
+	for template, err := range errs {
+		gologger.Error().Msgf("Could not find template '%s': %s", template, err)
+	}
+	return graph.Build(r.options, r.catalog, templatePaths, r.options.SecretsFile)
+}
+
+// reportDanglingReferences logs the dangling references of the graph
+// and returns an error if there are any.
+func reportDanglingReferences(templateGraph *graph.Graph) error {
+	dangling := templateGraph.Dangling()
+	for _, edge := range dangling {
+		gologger.Error().Msgf("Dangling template reference %s\n", edge)
+	}
+	if len(dangling) > 0 {
+		return errors.Errorf("%d dangling template reference(s) found", len(dangling))
+	}
+	return nil
+}
+
+// validateTemplateReferences fails template validation on references
+// of workflows, flows, secrets and payloads that cannot be resolved.
+func (r *Runner) validateTemplateReferences() error {
+	templateGraph, err := r.buildTemplateGraph()
+	if err != nil {
+		return errors.Wrap(err, "could not build template dependency graph")
+	}
+	return reportDanglingReferences(templateGraph)
+}
+
+// runTemplateGraph exports the dependency graph of the templates to the
+// -template-graph file and lists the dependents of the -template-impact template.
+func (r *Runner) runTemplateGraph() error {
+	templateGraph, err := r.buildTemplateGraph()
+	if err != nil {
+		return errors.Wrap(err, "could not build template dependency graph")
+	}
+
+	if r.options.TemplateGraph != "" {
+		format := graph.FormatJSON
+		if strings.EqualFold(filepath.Ext(r.options.TemplateGraph), ".dot") {
+			format = graph.FormatDOT
+		}
+		file, err := os.Create(r.options.TemplateGraph)
+		if err != nil {
+			return errors.Wrap(err, "could not create template graph file")
+		}
+		err = templateGraph.Write(file, format)
+		_ = file.Close()
+		if err != nil {
+			return errors.Wrap(err, "could not write template graph")
+		}
+		gologger.Info().Msgf("Template graph with %d nodes and %d edges written to %s\n", len(templateGraph.Nodes), len(templateGraph.Edges), r.options.TemplateGraph)
+	}
+
+	if r.options.TemplateImpact != "" {
+		paths, err := r.catalog.GetTemplatePath(r.options.TemplateImpact)
+		if err != nil {
+			return errors.Wrapf(err, "could not find template %s", r.options.TemplateImpact)
+		}
+		for _, path := range paths {
+			dependents := templateGraph.Dependents(path)
+			gologger.Info().Msgf("%d file(s) depend on %s\n", len(dependents), path)
+			for _, node := range dependents {
+				gologger.Silent().Msgf("%s [%s]\n", node.ID, node.Kind)
+			}
+		}
+	}
+	return reportDanglingReferences(templateGraph)
+}
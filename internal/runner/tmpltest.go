@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/tmpltest"
+)
+
+// runTemplateTests runs the offline test cases of the templates passed
+// with -t and reports pass/fail for every test case.
+func (r *Runner) runTemplateTests() error {
+	if len(r.options.Templates) == 0 {
+		return errors.New("no templates provided for testing")
+	}
+	templatePaths, errs := r.catalog.GetTemplatesPath(r.options.Templates)
+	for template, err := range errs {
+		gologger.Error().Msgf("Could not find template '%s': %s", template, err)
+	}
+	sort.Strings(templatePaths)
+
+	testRunner := tmpltest.New(r.options, r.catalog)
+
+	var passed, failed, skipped int
+	for _, templatePath := range templatePaths {
+		results, err := testRunner.RunTemplate(templatePath)
+		if err != nil {
+			if errors.Is(err, tmpltest.ErrNoTestCases) {
+				skipped++
+				gologger.Verbose().Msgf("No test cases found for %s\n", templatePath)
+				continue
+			}
+			failed++
+			gologger.Error().Msgf("Could not run test cases for %s: %s\n", templatePath, err)
+			continue
+		}
+		for _, result := range results {
+			if result.Passed {
+				passed++
+			} else {
+				failed++
+			}
+			if r.options.JSONL {
+				marshalled, _ := jsoniter.Marshal(result)
+				gologger.Silent().Msgf("%s\n", string(marshalled))
+				continue
+			}
+			if result.Passed {
+				gologger.Info().Msgf("%s\n", result)
+			} else {
+				gologger.Error().Msgf("%s\n", result)
+			}
+		}
+	}
+	gologger.Info().Msgf("Template tests completed passed=%d failed=%d skipped=%d\n", passed, failed, skipped)
+	if failed > 0 {
+		return errors.Errorf("%d template test case(s) failed", failed)
+	}
+	return nil
+}
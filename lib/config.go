@@ -3,6 +3,7 @@ package nuclei
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/projectdiscovery/goflags"
@@ -14,11 +15,15 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/code"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types/shard"
+	stringsutil "github.com/projectdiscovery/utils/strings"
 )
 
 // TemplateSources contains template sources
@@ -44,6 +49,19 @@ func WithTemplatesOrWorkflows(sources TemplateSources) NucleiSDKOptions {
 	}
 }
 
+// WithTemplateSet executes templates of a set compiled using ThreadSafeNucleiEngine.CompileTemplates
+// instead of loading and compiling templates on every execution
+// Note: this option is only supported in thread safe mode
+func WithTemplateSet(set *TemplateSet) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if e.mode != threadSafe {
+			return errors.New("option WithTemplateSet only supported in thread safe mode")
+		}
+		e.templateSet = set
+		return nil
+	}
+}
+
 // config contains all SDK configuration options
 type TemplateFilters struct {
 	Severity             string   // filter by severities (accepts CSV values of info, low, medium, high, critical)
@@ -169,6 +187,22 @@ func WithGlobalRateLimit(maxTokens int, duration time.Duration) NucleiSDKOptions
 	return WithGlobalRateLimitCtx(context.Background(), maxTokens, duration)
 }
 
+// WithHostRateLimits sets per host rate limits of http requests in pattern=rate
+// format (e.g. *.example.com=10) and enables adapting the rate of every host
+// to throttling responses if adaptive is true.
+func WithHostRateLimits(adaptive bool, rules ...string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		for _, rule := range rules {
+			if _, err := hostratelimit.ParseRule(rule); err != nil {
+				return err
+			}
+		}
+		e.opts.HostRateLimits = rules
+		e.opts.AdaptiveRateLimit = adaptive
+		return nil
+	}
+}
+
 // WithGlobalRateLimitCtx allows setting a global rate limit for the entire engine
 func WithGlobalRateLimitCtx(ctx context.Context, maxTokens int, duration time.Duration) NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -318,6 +352,19 @@ func WithScanStrategy(strategy string) NucleiSDKOptions {
 	}
 }
 
+// WithShard allows scanning only the index/count deterministic shard (index starting from 1)
+// of the template and target pairs so a scan can be split across independent engines
+func WithShard(index, count int) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		value := fmt.Sprintf("%d/%d", index, count)
+		if _, err := shard.Parse(value); err != nil {
+			return err
+		}
+		e.opts.Shard = value
+		return nil
+	}
+}
+
 // OutputWriter
 type OutputWriter output.Writer
 
@@ -385,6 +432,42 @@ func EnableCodeTemplates() NucleiSDKOptions {
 	}
 }
 
+// CodeSandboxConfig contains the isolation settings for code protocol templates
+type CodeSandboxConfig struct {
+	// Runtime is the container runtime to use (docker, podman)
+	Runtime string
+	// Image is the container image to run code in (default: per engine)
+	Image string
+	// Network is the network to attach containers to (default: none)
+	Network string
+	// CPUs is the number of cpus available to a container (default: 1)
+	CPUs string
+	// Memory is the memory available to a container (default: 256m)
+	Memory string
+}
+
+// WithCodeSandbox runs code protocol templates in isolated containers
+// instead of directly on the host
+func WithCodeSandbox(config CodeSandboxConfig) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if !stringsutil.EqualFoldAny(config.Runtime, code.SandboxRuntimes...) {
+			return fmt.Errorf("unsupported code sandbox runtime %s", config.Runtime)
+		}
+		e.opts.CodeSandbox = config.Runtime
+		e.opts.CodeSandboxImage = config.Image
+		e.opts.CodeSandboxNetwork = config.Network
+		e.opts.CodeSandboxCPUs = config.CPUs
+		if e.opts.CodeSandboxCPUs == "" {
+			e.opts.CodeSandboxCPUs = code.DefaultSandboxCPUs
+		}
+		e.opts.CodeSandboxMemory = config.Memory
+		if e.opts.CodeSandboxMemory == "" {
+			e.opts.CodeSandboxMemory = code.DefaultSandboxMemory
+		}
+		return nil
+	}
+}
+
 // EnableSelfContainedTemplates allows loading/executing self-contained templates
 func EnableSelfContainedTemplates() NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
@@ -478,6 +561,43 @@ func SignedTemplatesOnly() NucleiSDKOptions {
 	}
 }
 
+// WithTelemetry allows serving prometheus metrics on prometheusAddr and exporting
+// opentelemetry traces to the otlp/http otelEndpoint. Empty values disable either.
+func WithTelemetry(prometheusAddr, otelEndpoint string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.PrometheusAddr = prometheusAddr
+		e.opts.OTelEndpoint = otelEndpoint
+		return nil
+	}
+}
+
+// WithAssetContext computes the risk score and priority of results
+// from the asset context file at path
+func WithAssetContext(path string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.AssetContext = path
+		return nil
+	}
+}
+
+// WithRedactPolicy redacts the evidence of results written to the output,
+// exporters and trackers with the redaction policy file at path
+func WithRedactPolicy(path string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.RedactPolicy = path
+		return nil
+	}
+}
+
+// WithSuppressionFile drops results matching the active suppressions
+// of the suppression file at path
+func WithSuppressionFile(path string) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.opts.SuppressionFile = path
+		return nil
+	}
+}
+
 // WithCatalog uses a supplied catalog
 func WithCatalog(cat catalog.Catalog) NucleiSDKOptions {
 	return func(e *NucleiEngine) error {
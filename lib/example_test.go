@@ -4,11 +4,14 @@
 package nuclei_test
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/kitabisa/go-ci"
 	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/remeh/sizedwaitgroup"
 )
 
@@ -76,6 +79,37 @@ func ExampleThreadSafeNucleiEngine() {
 	// [caa-fingerprint] honey.scanme.sh
 }
 
+func ExampleThreadSafeNucleiEngine_ExecuteWithCallbackCtx() {
+	ne, err := nuclei.NewThreadSafeNucleiEngineCtx(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	defer ne.Close()
+
+	// compile dns templates once and reuse them in every scan
+	set, err := ne.CompileTemplates(nuclei.WithTemplateFilters(nuclei.TemplateFilters{ProtocolTypes: "dns"}))
+	if err != nil {
+		panic(err)
+	}
+
+	sg := sizedwaitgroup.New(2)
+	for _, target := range []string{"scanme.sh", "honey.scanme.sh"} {
+		sg.Add()
+		go func(target string) {
+			defer sg.Done()
+			// results of this scan only are sent to the callback
+			stats, err := ne.ExecuteWithCallbackCtx(context.Background(), []string{target}, func(event *output.ResultEvent) {
+				fmt.Printf("[%s] %s\n", event.TemplateID, event.Host)
+			}, nuclei.WithTemplateSet(set))
+			if err != nil {
+				panic(err)
+			}
+			fmt.Printf("%s: %d results in %s\n", target, stats.Results, stats.Duration)
+		}(target)
+	}
+	sg.Wait()
+}
+
 func TestMain(m *testing.M) {
 	// this file only contains testtables examples https://go.dev/blog/examples
 	// and actual functionality test are in sdk_test.go
@@ -2,6 +2,7 @@ package nuclei
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/logrusorgru/aurora"
@@ -11,6 +12,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/loader/workflow"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/ratelimit"
 	errorutil "github.com/projectdiscovery/utils/errors"
@@ -28,20 +32,7 @@ type unsafeOptions struct {
 // createEphemeralObjects creates ephemeral nuclei objects/instances/types
 func createEphemeralObjects(ctx context.Context, base *NucleiEngine, opts *types.Options) (*unsafeOptions, error) {
 	u := &unsafeOptions{}
-	u.executerOpts = protocols.ExecutorOptions{
-		Output:          base.customWriter,
-		Options:         opts,
-		Progress:        base.customProgress,
-		Catalog:         base.catalog,
-		IssuesClient:    base.rc,
-		RateLimiter:     base.rateLimiter,
-		Interactsh:      base.interactshClient,
-		HostErrorsCache: base.hostErrCache,
-		Colorizer:       aurora.NewAurora(true),
-		ResumeCfg:       types.NewResumeCfg(),
-		Parser:          base.parser,
-		Browser:         base.browserInstance,
-	}
+	u.executerOpts = newExecuterOptions(base, opts)
 	if opts.RateLimitMinute > 0 {
 		opts.RateLimit = opts.RateLimitMinute
 		opts.RateLimitDuration = time.Minute
@@ -59,6 +50,27 @@ func createEphemeralObjects(ctx context.Context, base *NucleiEngine, opts *types
 	return u, nil
 }
 
+// newExecuterOptions returns executor options sharing the global resources of the base
+// nuclei engine with their own options and per execution state (ex: resume config)
+func newExecuterOptions(base *NucleiEngine, opts *types.Options) protocols.ExecutorOptions {
+	executerOpts := base.executerOpts
+	executerOpts.Output = base.customWriter
+	executerOpts.Options = opts
+	executerOpts.Progress = base.customProgress
+	executerOpts.Catalog = base.catalog
+	executerOpts.IssuesClient = base.rc
+	executerOpts.RateLimiter = base.rateLimiter
+	executerOpts.Interactsh = base.interactshClient
+	executerOpts.HostErrorsCache = base.hostErrCache
+	executerOpts.Colorizer = aurora.NewAurora(true)
+	executerOpts.ResumeCfg = types.NewResumeCfg()
+	executerOpts.Parser = base.parser
+	executerOpts.Browser = base.browserInstance
+	executerOpts.AltSvcCache = httpclientpool.NewAltSvcCache()
+	executerOpts.WorkflowLoader = nil
+	return executerOpts
+}
+
 // closeEphemeralObjects closes all resources used by ephemeral nuclei objects/instances/types
 func closeEphemeralObjects(u *unsafeOptions) {
 	if u.executerOpts.RateLimiter != nil {
@@ -117,56 +129,170 @@ func (e *ThreadSafeNucleiEngine) GlobalResultCallback(callback func(event *outpu
 	e.eng.resultCallbacks = []func(*output.ResultEvent){callback}
 }
 
+// TemplateSet is a set of templates compiled once which can be
+// shared across concurrent executions of ThreadSafeNucleiEngine
+type TemplateSet struct {
+	templates []*templates.Template
+}
+
+// Templates returns the compiled templates of the set
+func (s *TemplateSet) Templates() []*templates.Template {
+	return s.templates
+}
+
+// ExecutionStats contains the summary of a single execution
+type ExecutionStats struct {
+	// Templates is the number of templates executed
+	Templates int
+	// Targets is the number of targets scanned
+	Targets int64
+	// Results is the number of results found
+	Results int64
+	// Duration is the time taken by the execution
+	Duration time.Duration
+}
+
+// CompileTemplates compiles templates based on given template options (ex: WithTemplatesOrWorkflows, WithTemplateFilters)
+// into a template set which can be executed any number of times using WithTemplateSet without being recompiled
+// Note: templates of a set use global resources (ex: rate limit) of the engine and not the ones of an execution
+func (e *ThreadSafeNucleiEngine) CompileTemplates(opts ...NucleiSDKOptions) (*TemplateSet, error) {
+	baseOpts := *e.eng.opts
+	tmpEngine := &NucleiEngine{opts: &baseOpts, mode: threadSafe}
+	for _, option := range opts {
+		if err := option(tmpEngine); err != nil {
+			return nil, err
+		}
+	}
+
+	// templates of the set are compiled with their own options and parser
+	// so that concurrent compilations with different options do not interfere
+	executerOpts := newExecuterOptions(e.eng, tmpEngine.opts)
+	executerOpts.Parser = templates.NewParser()
+	executerOpts.DoNotCache = true
+	workflowLoader, err := workflow.NewLoader(&executerOpts)
+	if err != nil {
+		return nil, errorutil.New("Could not create workflow loader: %s\n", err)
+	}
+	executerOpts.WorkflowLoader = workflowLoader
+
+	store, err := loader.New(loader.NewConfig(tmpEngine.opts, e.eng.catalog, executerOpts))
+	if err != nil {
+		return nil, errorutil.New("Could not create loader client: %s\n", err)
+	}
+	store.Load()
+	if len(store.Templates()) == 0 {
+		return nil, ErrNoTemplatesAvailable
+	}
+	return &TemplateSet{templates: store.Templates()}, nil
+}
+
 // ExecuteNucleiWithOptsCtx executes templates on targets and calls callback on each result(only if results are found)
 // This method can be called concurrently and it will use some global resources but can be runned parallelly
 // by invoking this method with different options and targets
 // Note: Not all options are thread-safe. this method will throw error if you try to use non-thread-safe options
 func (e *ThreadSafeNucleiEngine) ExecuteNucleiWithOptsCtx(ctx context.Context, targets []string, opts ...NucleiSDKOptions) error {
+	_, err := e.execute(ctx, targets, nil, opts...)
+	return err
+}
+
+// ExecuteWithCallbackCtx executes templates on targets and calls callback on each result of this execution only
+// instead of the global result callback, returning the stats of the execution once it is completed
+// Callback is never called concurrently and can be used with WithTemplateSet to execute precompiled templates
+// Results of interactsh interactions are sent to the callback too, the execution waits for the interactsh
+// cooldown period if requests are waiting for interactions and later interactions are written to the output
+func (e *ThreadSafeNucleiEngine) ExecuteWithCallbackCtx(ctx context.Context, targets []string, callback func(event *output.ResultEvent), opts ...NucleiSDKOptions) (*ExecutionStats, error) {
+	if callback == nil {
+		return nil, errorutil.New("callback is required")
+	}
+	return e.execute(ctx, targets, callback, opts...)
+}
+
+// execute executes templates on targets writing results to the callback of the execution
+// if given or to the global result callback otherwise
+func (e *ThreadSafeNucleiEngine) execute(ctx context.Context, targets []string, callback func(event *output.ResultEvent), opts ...NucleiSDKOptions) (*ExecutionStats, error) {
+	started := time.Now()
 	baseOpts := *e.eng.opts
 	tmpEngine := &NucleiEngine{opts: &baseOpts, mode: threadSafe}
 	for _, option := range opts {
 		if err := option(tmpEngine); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	// create ephemeral nuclei objects/instances/types using base nuclei engine
 	unsafeOpts, err := createEphemeralObjects(ctx, e.eng, tmpEngine.opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// cleanup and stop all resources
 	defer closeEphemeralObjects(unsafeOpts)
 
-	// load templates
-	workflowLoader, err := workflow.NewLoader(&unsafeOpts.executerOpts)
-	if err != nil {
-		return errorutil.New("Could not create workflow loader: %s\n", err)
-	}
-	unsafeOpts.executerOpts.WorkflowLoader = workflowLoader
+	var templatesList []*templates.Template
+	if tmpEngine.templateSet != nil {
+		templatesList = tmpEngine.templateSet.Templates()
+	} else {
+		// load templates
+		workflowLoader, err := workflow.NewLoader(&unsafeOpts.executerOpts)
+		if err != nil {
+			return nil, errorutil.New("Could not create workflow loader: %s\n", err)
+		}
+		unsafeOpts.executerOpts.WorkflowLoader = workflowLoader
 
-	store, err := loader.New(loader.NewConfig(tmpEngine.opts, e.eng.catalog, unsafeOpts.executerOpts))
-	if err != nil {
-		return errorutil.New("Could not create loader client: %s\n", err)
+		store, err := loader.New(loader.NewConfig(tmpEngine.opts, e.eng.catalog, unsafeOpts.executerOpts))
+		if err != nil {
+			return nil, errorutil.New("Could not create loader client: %s\n", err)
+		}
+		store.Load()
+		if len(store.Templates()) == 0 && len(store.Workflows()) == 0 {
+			return nil, ErrNoTemplatesAvailable
+		}
+		templatesList = store.Templates()
 	}
-	store.Load()
 
 	inputProvider := provider.NewSimpleInputProviderWithUrls(targets...)
-
-	if len(store.Templates()) == 0 && len(store.Workflows()) == 0 {
-		return ErrNoTemplatesAvailable
-	}
 	if inputProvider.Count() == 0 {
-		return ErrNoTargetsAvailable
+		return nil, ErrNoTargetsAvailable
 	}
 
 	engine := core.New(tmpEngine.opts)
 	engine.SetExecuterOptions(unsafeOpts.executerOpts)
 
-	_ = engine.ExecuteScanWithOpts(ctx, store.Templates(), inputProvider, false)
+	stats := &ExecutionStats{Templates: len(templatesList), Targets: inputProvider.Count()}
+	if callback != nil {
+		mu := &sync.Mutex{}
+		resultCallback := func(event *output.ResultEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			stats.Results++
+			callback(event)
+		}
+		// results of interactions are delivered to the callback of this execution as well
+		interactshCallback := interactsh.NewResultCallback(resultCallback)
+		ctx = interactsh.WithResultCallback(ctx, interactshCallback)
+		_ = engine.ExecuteWithResults(ctx, templatesList, inputProvider, resultCallback)
+		engine.WorkPool().Wait()
+		e.waitInteractions(ctx, interactshCallback)
+	} else {
+		_ = engine.ExecuteScanWithOpts(ctx, templatesList, inputProvider, false)
+		engine.WorkPool().Wait()
+	}
+	stats.Duration = time.Since(started)
+	return stats, nil
+}
+
+// waitInteractions waits for the cooldown period of interactsh if requests of an execution
+// are waiting for interactions and closes its result callback
+func (e *ThreadSafeNucleiEngine) waitInteractions(ctx context.Context, callback *interactsh.ResultCallback) {
+	defer callback.Close()
 
-	engine.WorkPool().Wait()
-	return nil
+	if !callback.Registered() || e.eng.interactshOpts == nil || e.eng.interactshOpts.CooldownPeriod <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(e.eng.interactshOpts.CooldownPeriod):
+	}
 }
 
 // ExecuteNucleiWithOpts is same as ExecuteNucleiWithOptsCtx but with default context
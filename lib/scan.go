@@ -0,0 +1,173 @@
+package nuclei
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types/scanstrategy"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// ScanHandle is a handle to a scan started with NucleiEngine.StartScan
+// to which targets are pushed while it is running
+type ScanHandle struct {
+	input   *provider.StreamInputProvider
+	cancel  context.CancelFunc
+	done    chan struct{}
+	results atomic.Int64
+}
+
+// ScanProgress contains the progress of a scan
+type ScanProgress struct {
+	// Targets is the number of unique targets pushed to the scan
+	Targets int64
+	// Started is the number of targets whose scan has started
+	Started int64
+	// Results is the number of results found
+	Results int64
+	// Paused is true if the scan is paused
+	Paused bool
+	// InputClosed is true if no more targets are accepted
+	InputClosed bool
+	// Done is true if the scan is completed
+	Done bool
+}
+
+// StartScan starts a scan of loaded templates which scans targets received from the targets channel
+// as soon as they are pushed. targets loaded with LoadTargets are scanned first.
+// Closing the targets channel lets the scan drain remaining targets and complete.
+// Note: the host-spray scan strategy is always used and only one scan of the engine can run at a time,
+// ErrScanRunning is returned if a scan is already running
+func (e *NucleiEngine) StartScan(ctx context.Context, targets <-chan string, callback ...func(event *output.ResultEvent)) (*ScanHandle, error) {
+	if !e.templatesLoaded {
+		_ = e.LoadAllTemplates()
+	}
+	templatesAndWorkflows := append(e.store.Templates(), e.store.Workflows()...)
+	if len(templatesAndWorkflows) == 0 {
+		return nil, ErrNoTemplatesAvailable
+	}
+	if e.inputProvider.InputType() != provider.SimpleListInputProvider {
+		return nil, errorutil.New("StartScan does not support targets loaded with http data")
+	}
+	if !e.scanRunning.CompareAndSwap(false, true) {
+		return nil, ErrScanRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	handle := &ScanHandle{
+		input:  provider.NewStreamInputProvider(),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	e.inputProvider.Iterate(func(value *contextargs.MetaInput) bool {
+		handle.input.Set(value.Input)
+		return true
+	})
+
+	filtered := []func(event *output.ResultEvent){}
+	for _, callback := range callback {
+		if callback != nil {
+			filtered = append(filtered, callback)
+		}
+	}
+	e.callbacksMutex.Lock()
+	resultCallbacks, scanStrategy := e.resultCallbacks, e.opts.ScanStrategy
+	callbacks := append(append([]func(*output.ResultEvent){}, resultCallbacks...), filtered...)
+	e.resultCallbacks = []func(*output.ResultEvent){func(event *output.ResultEvent) {
+		handle.results.Add(1)
+		if len(callbacks) == 0 {
+			printResult(event)
+			return
+		}
+		for _, callback := range callbacks {
+			callback(event)
+		}
+	}}
+	e.callbacksMutex.Unlock()
+	// targets are iterated once and scanned with all templates as they are received,
+	// options are only modified by the running scan
+	e.opts.ScanStrategy = scanstrategy.HostSpray.String()
+
+	go func() {
+		defer handle.input.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case target, ok := <-targets:
+				if !ok {
+					return
+				}
+				handle.input.Set(target)
+			}
+		}
+	}()
+	go func() {
+		// let paused iteration observe the cancellation
+		<-ctx.Done()
+		handle.input.Resume()
+	}()
+	go func() {
+		defer close(handle.done)
+		defer cancel()
+
+		_ = e.engine.ExecuteScanWithOpts(ctx, templatesAndWorkflows, handle.input, false)
+		e.engine.WorkPool().Wait()
+
+		e.callbacksMutex.Lock()
+		e.resultCallbacks = resultCallbacks
+		e.callbacksMutex.Unlock()
+		e.opts.ScanStrategy = scanStrategy
+		e.scanRunning.Store(false)
+	}()
+	return handle, nil
+}
+
+// Progress returns the progress of the scan
+func (h *ScanHandle) Progress() ScanProgress {
+	return ScanProgress{
+		Targets:     h.input.Count(),
+		Started:     h.input.Iterated(),
+		Results:     h.results.Load(),
+		Paused:      h.input.Paused(),
+		InputClosed: h.input.Closed(),
+		Done:        h.isDone(),
+	}
+}
+
+// Pause stops starting the scan of new targets, targets being scanned are not interrupted
+func (h *ScanHandle) Pause() {
+	h.input.Pause()
+}
+
+// Resume resumes a paused scan
+func (h *ScanHandle) Resume() {
+	h.input.Resume()
+}
+
+// Cancel stops the scan without waiting for remaining targets
+func (h *ScanHandle) Cancel() {
+	h.cancel()
+}
+
+// Done returns a channel which is closed once the scan is completed
+func (h *ScanHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait waits for the scan to complete
+func (h *ScanHandle) Wait() {
+	<-h.done
+}
+
+func (h *ScanHandle) isDone() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sync"
+	"sync/atomic"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/authprovider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
@@ -21,6 +23,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
@@ -41,6 +44,8 @@ var (
 	ErrNoTargetsAvailable = errorutil.New("No targets available")
 	// ErrOptionsNotSupported is returned when an option is not supported in thread safe mode
 	ErrOptionsNotSupported = errorutil.NewWithFmt("Option %v not supported in thread safe mode")
+	// ErrScanRunning is returned when a scan is started while another scan of the engine is running
+	ErrScanRunning = errorutil.New("A scan is already running")
 )
 
 type engineMode uint
@@ -54,14 +59,17 @@ const (
 // runs scans using templates and returns results
 type NucleiEngine struct {
 	// user options
+	callbacksMutex              sync.RWMutex
 	resultCallbacks             []func(event *output.ResultEvent)
 	onFailureCallback           func(event *output.InternalEvent)
 	disableTemplatesAutoUpgrade bool
 	enableStats                 bool
 	onUpdateAvailableCallback   func(newVersion string)
+	templateSet                 *TemplateSet
 
 	// ready-status fields
 	templatesLoaded bool
+	scanRunning     atomic.Bool
 
 	// unexported core fields
 	interactshClient *interactsh.Client
@@ -230,6 +238,7 @@ func (e *NucleiEngine) closeInternal() {
 func (e *NucleiEngine) Close() {
 	e.closeInternal()
 	protocolinit.Close()
+	telemetry.Close()
 }
 
 // ExecuteCallbackWithCtx executes templates on targets and calls callback on each result(only if results are found)
@@ -244,6 +253,10 @@ func (e *NucleiEngine) ExecuteCallbackWithCtx(ctx context.Context, callback ...f
 	if e.inputProvider.Count() == 0 {
 		return ErrNoTargetsAvailable
 	}
+	if !e.scanRunning.CompareAndSwap(false, true) {
+		return ErrScanRunning
+	}
+	defer e.scanRunning.Store(false)
 
 	filtered := []func(event *output.ResultEvent){}
 	for _, callback := range callback {
@@ -251,7 +264,9 @@ func (e *NucleiEngine) ExecuteCallbackWithCtx(ctx context.Context, callback ...f
 			filtered = append(filtered, callback)
 		}
 	}
+	e.callbacksMutex.Lock()
 	e.resultCallbacks = append(e.resultCallbacks, filtered...)
+	e.callbacksMutex.Unlock()
 
 	templatesAndWorkflows := append(e.store.Templates(), e.store.Workflows()...)
 	if len(templatesAndWorkflows) == 0 {
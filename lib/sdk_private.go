@@ -22,14 +22,20 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/installer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/pipeline"
 	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolinit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/redact"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/risk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/suppress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
@@ -43,22 +49,19 @@ var sharedInit *sync.Once
 func (e *NucleiEngine) applyRequiredDefaults(ctx context.Context) {
 	mockoutput := testutils.NewMockOutputWriter(e.opts.OmitTemplate)
 	mockoutput.WriteCallback = func(event *output.ResultEvent) {
-		if len(e.resultCallbacks) > 0 {
-			for _, callback := range e.resultCallbacks {
+		e.callbacksMutex.RLock()
+		resultCallbacks := e.resultCallbacks
+		e.callbacksMutex.RUnlock()
+
+		if len(resultCallbacks) > 0 {
+			for _, callback := range resultCallbacks {
 				if callback != nil {
 					callback(event)
 				}
 			}
 			return
 		}
-		sb := strings.Builder{}
-		sb.WriteString(fmt.Sprintf("[%v] ", event.TemplateID))
-		if event.Matched != "" {
-			sb.WriteString(event.Matched)
-		} else {
-			sb.WriteString(event.Host)
-		}
-		fmt.Println(sb.String())
+		printResult(event)
 	}
 	if e.onFailureCallback != nil {
 		mockoutput.FailureCallback = e.onFailureCallback
@@ -69,6 +72,7 @@ func (e *NucleiEngine) applyRequiredDefaults(ctx context.Context) {
 	} else {
 		e.customWriter = mockoutput
 	}
+	e.customWriter = telemetry.NewWriter(e.customWriter)
 
 	if e.customProgress == nil {
 		e.customProgress = &testutils.MockProgressClient{}
@@ -96,6 +100,18 @@ func (e *NucleiEngine) applyRequiredDefaults(ctx context.Context) {
 	e.inputProvider = provider.NewSimpleInputProvider()
 }
 
+// printResult prints a result to stdout when no result callback is set
+func printResult(event *output.ResultEvent) {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("[%v] ", event.TemplateID))
+	if event.Matched != "" {
+		sb.WriteString(event.Matched)
+	} else {
+		sb.WriteString(event.Host)
+	}
+	fmt.Println(sb.String())
+}
+
 // init
 func (e *NucleiEngine) init(ctx context.Context) error {
 	if e.opts.Verbose {
@@ -128,8 +144,25 @@ func (e *NucleiEngine) init(ctx context.Context) error {
 		e.httpClient = httpclient
 	}
 
+	// serve prometheus metrics and export traces if requested
+	if err := telemetry.Init(e.opts); err != nil {
+		return err
+	}
+
+	redactor, err := redact.New(e.opts.RedactPolicy, e.opts.Redact)
+	if err != nil {
+		return err
+	}
+	riskScorer, err := risk.New(e.opts.AssetContext)
+	if err != nil {
+		return err
+	}
+	suppressor, err := suppress.New(e.opts.SuppressionFile)
+	if err != nil {
+		return err
+	}
+
 	e.applyRequiredDefaults(ctx)
-	var err error
 
 	// setup progressbar
 	if e.enableStats {
@@ -148,6 +181,9 @@ func (e *NucleiEngine) init(ctx context.Context) error {
 	if e.rc, err = reporting.New(&reporting.Options{}, "", false); err != nil {
 		return err
 	}
+	// results are processed the same way for the output and the trackers
+	e.customWriter, e.rc = pipeline.New(redactor, suppressor, riskScorer).Apply(e.customWriter, e.rc)
+	e.interactshOpts.Output = e.customWriter
 	e.interactshOpts.IssuesClient = e.rc
 	if e.httpClient != nil {
 		e.interactshOpts.HTTPClient = e.httpClient
@@ -174,6 +210,7 @@ func (e *NucleiEngine) init(ctx context.Context) error {
 		Browser:         e.browserInstance,
 		Parser:          e.parser,
 		InputHelper:     input.NewHelper(),
+		AltSvcCache:     httpclientpool.NewAltSvcCache(),
 	}
 	if len(e.opts.SecretsFile) > 0 {
 		authTmplStore, err := runner.GetAuthTmplStore(*e.opts, e.catalog, e.executerOpts)
@@ -218,6 +255,12 @@ func (e *NucleiEngine) init(ctx context.Context) error {
 		}
 	}
 
+	if e.executerOpts.HostRateLimiter == nil {
+		if e.executerOpts.HostRateLimiter, err = hostratelimit.NewFromOptions(e.opts); err != nil {
+			return errors.Wrap(err, "could not create host rate limiter")
+		}
+	}
+
 	e.engine = core.New(e.opts)
 	e.engine.SetExecuterOptions(e.executerOpts)
 
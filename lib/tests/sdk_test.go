@@ -2,12 +2,17 @@ package sdk_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/utils/env"
 	"github.com/stretchr/testify/require"
 	"github.com/tarunKoyalwar/goleak"
@@ -167,3 +172,162 @@ func TestWithVarsNuclei(t *testing.T) {
 		fn()
 	}
 }
+
+func TestThreadSafeNucleiExecuteWithCallback(t *testing.T) {
+	template := `id: sdk-callback-test
+
+info:
+  name: SDK Callback Test
+  author: pdteam
+  severity: info
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}"
+    matchers:
+      - type: word
+        words:
+          - "nuclei-sdk-callback"
+`
+	templatePath := filepath.Join(t.TempDir(), "sdk-callback-test.yaml")
+	require.Nil(t, os.WriteFile(templatePath, []byte(template), 0644))
+
+	newServer := func(delay time.Duration) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			_, _ = w.Write([]byte("nuclei-sdk-callback"))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	ne, err := nuclei.NewThreadSafeNucleiEngineCtx(context.Background(), nuclei.DisableUpdateCheck())
+	require.Nil(t, err)
+	defer ne.Close()
+
+	set, err := ne.CompileTemplates(nuclei.WithTemplatesOrWorkflows(nuclei.TemplateSources{Templates: []string{templatePath}}))
+	require.Nil(t, err, "could not compile templates")
+	require.Len(t, set.Templates(), 1)
+
+	servers := []*httptest.Server{newServer(0), newServer(0), newServer(0)}
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+
+			var events []*output.ResultEvent
+			stats, err := ne.ExecuteWithCallbackCtx(context.Background(), []string{target}, func(event *output.ResultEvent) {
+				events = append(events, event)
+			}, nuclei.WithTemplateSet(set))
+			require.Nil(t, err, "could not execute templates on %s", target)
+			require.Len(t, events, 1, "could not get results of %s only", target)
+			require.Equal(t, target, events[0].Matched)
+			require.Equal(t, int64(1), stats.Results)
+		}(server.URL)
+	}
+	wg.Wait()
+
+	// a cancelled execution returns without waiting for slow targets
+	slow := newServer(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	var results int
+	_, err = ne.ExecuteWithCallbackCtx(ctx, []string{slow.URL}, func(event *output.ResultEvent) {
+		results++
+	}, nuclei.WithTemplateSet(set))
+	require.Nil(t, err)
+	require.Zero(t, results, "got results of cancelled execution")
+	require.Less(t, time.Since(started), 5*time.Second, "cancelled execution did not return")
+}
+
+func TestStartScan(t *testing.T) {
+	template := `id: sdk-scan-test
+
+info:
+  name: SDK Scan Test
+  author: pdteam
+  severity: info
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}"
+    matchers:
+      - type: word
+        words:
+          - "nuclei-sdk-scan"
+`
+	templatePath := filepath.Join(t.TempDir(), "sdk-scan-test.yaml")
+	require.Nil(t, os.WriteFile(templatePath, []byte(template), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("nuclei-sdk-scan"))
+	}))
+	defer server.Close()
+
+	ne, err := nuclei.NewNucleiEngineCtx(context.Background(),
+		nuclei.WithTemplatesOrWorkflows(nuclei.TemplateSources{Templates: []string{templatePath}}),
+		nuclei.DisableUpdateCheck(),
+	)
+	require.Nil(t, err)
+	defer ne.Close()
+	ne.LoadTargets([]string{server.URL + "/loaded"}, false)
+
+	var mu sync.Mutex
+	var matched []string
+	received := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, matched...)
+	}
+	targets := make(chan string)
+	handle, err := ne.StartScan(context.Background(), targets, func(event *output.ResultEvent) {
+		mu.Lock()
+		matched = append(matched, event.Matched)
+		mu.Unlock()
+	})
+	require.Nil(t, err, "could not start scan")
+
+	_, err = ne.StartScan(context.Background(), make(chan string))
+	require.ErrorIs(t, err, nuclei.ErrScanRunning, "could start a second scan")
+	require.ErrorIs(t, ne.ExecuteWithCallback(nil), nuclei.ErrScanRunning, "could execute while scanning")
+
+	require.Eventually(t, func() bool { return len(received()) == 1 }, 10*time.Second, 10*time.Millisecond, "could not scan loaded target")
+
+	handle.Pause()
+	targets <- server.URL + "/streamed"
+	time.Sleep(200 * time.Millisecond)
+	progress := handle.Progress()
+	require.True(t, progress.Paused)
+	require.Equal(t, int64(2), progress.Targets)
+	require.Equal(t, int64(1), progress.Started, "could start target while paused")
+	require.Len(t, received(), 1, "could scan target while paused")
+
+	handle.Resume()
+	require.Eventually(t, func() bool { return len(received()) == 2 }, 10*time.Second, 10*time.Millisecond, "could not scan streamed target after resume")
+
+	close(targets)
+	select {
+	case <-handle.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("scan did not complete after closing targets")
+	}
+	progress = handle.Progress()
+	require.True(t, progress.Done)
+	require.True(t, progress.InputClosed)
+	require.Equal(t, int64(2), progress.Results)
+	require.ElementsMatch(t, []string{server.URL + "/loaded", server.URL + "/streamed"}, received())
+
+	// the engine accepts a new scan once the previous one is completed
+	handle, err = ne.StartScan(context.Background(), make(chan string))
+	require.Nil(t, err, "could not start scan after completion")
+	handle.Cancel()
+	handle.Wait()
+}
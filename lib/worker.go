@@ -0,0 +1,229 @@
+package nuclei
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/goflags"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/distributed"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+)
+
+const (
+	// workerPollInterval is the interval at which idle workers lease work units
+	workerPollInterval = time.Second
+	// workerMaxConnectionErrors is the number of consecutive failed requests
+	// to the coordinator after which a worker stops
+	workerMaxConnectionErrors = 5
+)
+
+// RunWorker runs a stateless distributed scan worker which executes work units leased
+// from the coordinator at coordinatorURL authenticating with token until the scan is completed
+// or ctx is cancelled. Scan options are fetched from the coordinator, opts can be used to override
+// them (ex: WithVerbosity). Results of interactsh interactions received after the completion of
+// their work unit are sent to the coordinator as well.
+func RunWorker(ctx context.Context, coordinatorURL, token string, opts ...NucleiSDKOptions) error {
+	if token == "" {
+		return errors.New("no coordinator token specified")
+	}
+	hostname, _ := os.Hostname()
+	client := distributed.NewClient(coordinatorURL, fmt.Sprintf("%s-%d", hostname, os.Getpid()), token)
+
+	config, err := client.Config(ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch config from coordinator: %w", err)
+	}
+	options := append([]NucleiSDKOptions{DisableUpdateCheck(), withWorkerConfig(config), withWorkerOutput(client)}, opts...)
+	engine, err := NewThreadSafeNucleiEngineCtx(ctx, options...)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	worker := &worker{
+		client:       client,
+		engine:       engine,
+		leaseTimeout: config.LeaseTimeout,
+		templateSets: make(map[string]*TemplateSet),
+	}
+	gologger.Info().Msgf("Worker %s connected to coordinator %s\n", client.Worker(), coordinatorURL)
+	return worker.run(ctx)
+}
+
+// worker executes work units leased from a coordinator
+type worker struct {
+	client       *distributed.Client
+	engine       *ThreadSafeNucleiEngine
+	leaseTimeout time.Duration
+	// templateSets caches the compiled templates of work units by template paths
+	templateSets map[string]*TemplateSet
+}
+
+// run leases and executes work units until the scan is completed
+func (w *worker) run(ctx context.Context) error {
+	var connectionErrors int
+	for {
+		unit, err := w.client.Lease(ctx)
+		if errors.Is(err, distributed.ErrScanCompleted) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			connectionErrors++
+			if connectionErrors >= workerMaxConnectionErrors {
+				return fmt.Errorf("could not lease work unit: %w", err)
+			}
+			gologger.Warning().Msgf("Could not lease work unit: %s\n", err)
+		} else {
+			connectionErrors = 0
+		}
+		if unit == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(workerPollInterval):
+			}
+			continue
+		}
+
+		results, err := w.execute(ctx, unit)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			gologger.Warning().Msgf("Could not execute work unit %s: %s\n", unit.ID, err)
+		}
+		if err := w.client.Complete(ctx, unit, results, err); err != nil {
+			gologger.Warning().Msgf("Could not complete work unit %s: %s\n", unit.ID, err)
+		}
+	}
+}
+
+// execute executes a work unit extending its lease until the execution is completed
+func (w *worker) execute(ctx context.Context, unit *distributed.WorkUnit) ([]*output.ResultEvent, error) {
+	gologger.Verbose().Msgf("Executing work unit %s (attempt %d) with %d targets\n", unit.ID, unit.Attempt, len(unit.Targets))
+
+	heartbeatCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.heartbeat(heartbeatCtx, unit)
+
+	set, err := w.templateSet(unit.Templates)
+	if err != nil {
+		return nil, err
+	}
+	var results []*output.ResultEvent
+	_, err = w.engine.ExecuteWithCallbackCtx(ctx, unit.Targets, func(event *output.ResultEvent) {
+		results = append(results, event)
+	}, WithTemplateSet(set))
+	return results, err
+}
+
+// heartbeat extends the lease of a work unit until ctx is cancelled
+func (w *worker) heartbeat(ctx context.Context, unit *distributed.WorkUnit) {
+	interval := w.leaseTimeout / 3
+	if interval <= 0 {
+		interval = distributed.DefaultLeaseTimeout / 3
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.client.Heartbeat(ctx, unit); err != nil && ctx.Err() == nil {
+				gologger.Warning().Msgf("Could not extend lease of work unit %s: %s\n", unit.ID, err)
+			}
+		}
+	}
+}
+
+// templateSet returns the compiled templates of a work unit
+func (w *worker) templateSet(templates []string) (*TemplateSet, error) {
+	key := strings.Join(templates, ",")
+	if set, ok := w.templateSets[key]; ok {
+		return set, nil
+	}
+	set, err := w.engine.CompileTemplates(WithTemplatesOrWorkflows(TemplateSources{Templates: templates}))
+	if err != nil {
+		return nil, err
+	}
+	w.templateSets[key] = set
+	return set, nil
+}
+
+// withWorkerOutput sends the results written to the output of a worker engine to the coordinator
+func withWorkerOutput(client *distributed.Client) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.customWriter = distributed.NewResultWriter(client)
+		return nil
+	}
+}
+
+// withWorkerConfig applies the scan options of the coordinator to a worker engine
+func withWorkerConfig(config *distributed.WorkerConfig) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		if config.TemplateThreads > 0 {
+			e.opts.TemplateThreads = config.TemplateThreads
+		}
+		if config.BulkSize > 0 {
+			e.opts.BulkSize = config.BulkSize
+		}
+		if config.HeadlessTemplateThreads > 0 {
+			e.opts.HeadlessTemplateThreads = config.HeadlessTemplateThreads
+		}
+		if config.HeadlessBulkSize > 0 {
+			e.opts.HeadlessBulkSize = config.HeadlessBulkSize
+		}
+		if config.JsConcurrency > 0 {
+			e.opts.JsConcurrency = config.JsConcurrency
+		}
+		if config.PayloadConcurrency > 0 {
+			e.opts.PayloadConcurrency = config.PayloadConcurrency
+		}
+		if config.Timeout > 0 {
+			e.opts.Timeout = config.Timeout
+		}
+		e.opts.RateLimit = config.RateLimit
+		e.opts.RateLimitDuration = config.RateLimitDuration
+		e.opts.Retries = config.Retries
+		e.opts.EnableCodeTemplates = config.EnableCodeTemplates
+		e.opts.EnableSelfContainedTemplates = config.EnableSelfContainedTemplates
+		e.opts.EnableFileTemplates = config.EnableFileTemplates
+		e.opts.EnableGlobalMatchersTemplates = config.EnableGlobalMatchersTemplates
+		e.opts.DisableUnsignedTemplates = config.DisableUnsignedTemplates
+		e.opts.CustomHeaders = config.CustomHeaders
+		e.opts.AliveHttpProxy = config.HttpProxy
+		e.opts.AliveSocksProxy = config.SocksProxy
+
+		vars := goflags.RuntimeMap{}
+		for key, value := range config.Vars {
+			if err := vars.Set(fmt.Sprintf("%s=%v", key, value)); err != nil {
+				return err
+			}
+		}
+		e.opts.Vars = vars
+
+		e.opts.NoInteractsh = config.NoInteractsh
+		e.interactshOpts = interactsh.DefaultOptions(nil, nil, nil)
+		e.interactshOpts.NoInteractsh = config.NoInteractsh
+		if config.InteractshURL != "" {
+			e.interactshOpts.ServerURL = config.InteractshURL
+		}
+		e.interactshOpts.Authorization = config.InteractshToken
+
+		if config.Headless {
+			return EnableHeadlessWithOpts(nil)(e)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,255 @@
+// Package index implements a persistent metadata index of templates
+// which can be searched with field filters and full-text terms.
+package index
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+const (
+	// Version is the version of the index format
+	Version = 1
+	// FileName is the name of the index file in the nuclei cache directory
+	FileName = "templates-metadata-index.json"
+)
+
+// Entry is the indexed metadata of a template
+type Entry struct {
+	ID             string                 `json:"id"`
+	Path           string                 `json:"path"`
+	Name           string                 `json:"name,omitempty"`
+	Description    string                 `json:"description,omitempty"`
+	Severity       severity.Holder        `json:"severity"`
+	Authors        []string               `json:"authors,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`
+	References     []string               `json:"references,omitempty"`
+	CVEID          []string               `json:"cve-id,omitempty"`
+	CWEID          []string               `json:"cwe-id,omitempty"`
+	CPE            string                 `json:"cpe,omitempty"`
+	CVSSScore      float64                `json:"cvss-score,omitempty"`
+	EPSSScore      float64                `json:"epss-score,omitempty"`
+	EPSSPercentile float64                `json:"epss-percentile,omitempty"`
+	Vendor         string                 `json:"vendor,omitempty"`
+	Product        string                 `json:"product,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Protocols      []string               `json:"protocols,omitempty"`
+	Ports          []string               `json:"ports,omitempty"`
+	Requests       int                    `json:"requests"`
+	Matchers       []string               `json:"matchers,omitempty"`
+	Extractors     []string               `json:"extractors,omitempty"`
+	OAST           bool                   `json:"oast"`
+}
+
+// File is the state of an indexed file when the index was built
+type File struct {
+	ModTime int64 `json:"mod-time"`
+	Size    int64 `json:"size"`
+}
+
+// Index is the metadata index of templates keyed by path
+type Index struct {
+	Version int `json:"version"`
+	// Fingerprint identifies the filters the index was built with
+	Fingerprint string `json:"fingerprint"`
+	// Files are all the candidate files of the index including filtered ones
+	Files   map[string]File   `json:"files"`
+	Entries map[string]*Entry `json:"entries"`
+}
+
+// New returns an empty index for the given fingerprint
+func New(fingerprint string) *Index {
+	return &Index{
+		Version:     Version,
+		Fingerprint: fingerprint,
+		Files:       make(map[string]File),
+		Entries:     make(map[string]*Entry),
+	}
+}
+
+// DefaultPath returns the path of the index in the nuclei cache directory
+func DefaultPath() string {
+	return filepath.Join(config.DefaultConfig.GetCacheDir(), FileName)
+}
+
+// Fingerprint returns a fingerprint of the filters used to build an index
+func Fingerprint(filters interface{}) string {
+	data, _ := json.Marshal(filters)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// Load loads the index at path
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	index := &Index{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse template index %s", path)
+	}
+	if index.Version != Version {
+		return nil, errorutil.New("unsupported template index version %d", index.Version)
+	}
+	return index, nil
+}
+
+// Save writes the index to path
+func (i *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Fresh returns true if the index was built with the fingerprint from
+// exactly the given files and none of them changed since
+func (i *Index) Fresh(fingerprint string, paths []string) bool {
+	if i.Fingerprint != fingerprint || len(i.Files) != len(sliceutil.Dedupe(paths)) {
+		return false
+	}
+	for _, path := range paths {
+		indexed, ok := i.Files[path]
+		if !ok {
+			return false
+		}
+		if current, err := stat(path); err != nil || current != indexed {
+			return false
+		}
+	}
+	return true
+}
+
+// AddFiles records the state of the candidate files of the index
+func (i *Index) AddFiles(paths []string) {
+	for _, path := range paths {
+		if file, err := stat(path); err == nil {
+			i.Files[path] = file
+		}
+	}
+}
+
+// Add indexes the metadata of a template with its raw content
+func (i *Index) Add(template *templates.Template, raw []byte) {
+	i.Entries[template.Path] = NewEntry(template, raw)
+}
+
+// Search returns the entries matching the query sorted by path
+func (i *Index) Search(query *Query) []*Entry {
+	var entries []*Entry
+	for _, entry := range i.Entries {
+		if query.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].Path < entries[b].Path
+	})
+	return entries
+}
+
+// NewEntry returns the indexed metadata of a template
+func NewEntry(template *templates.Template, raw []byte) *Entry {
+	info := template.Info
+	entry := &Entry{
+		ID:          template.ID,
+		Path:        template.Path,
+		Name:        info.Name,
+		Description: info.Description,
+		Severity:    info.SeverityHolder,
+		Authors:     info.Authors.ToSlice(),
+		Tags:        info.Tags.ToSlice(),
+		Metadata:    info.Metadata,
+		Requests:    template.Requests(),
+		Matchers:    template.MatcherTypes(),
+		Extractors:  template.ExtractorTypes(),
+		Ports:       templatePorts(template),
+		OAST:        interactsh.HasMarkers(string(raw)) || strings.Contains(string(raw), "interactsh_"),
+	}
+	if info.Reference != nil {
+		entry.References = info.Reference.ToSlice()
+	}
+	if classification := info.Classification; classification != nil {
+		entry.CVEID = classification.CVEID.ToSlice()
+		entry.CWEID = classification.CWEID.ToSlice()
+		entry.CPE = classification.CPE
+		entry.CVSSScore = classification.CVSSScore
+		entry.EPSSScore = classification.EPSSScore
+		entry.EPSSPercentile = classification.EPSSPercentile
+	}
+	if vendor, ok := info.Metadata["vendor"]; ok {
+		entry.Vendor = types.ToString(vendor)
+	}
+	if product, ok := info.Metadata["product"]; ok {
+		entry.Product = types.ToString(product)
+	}
+	for _, protocol := range template.Protocols() {
+		entry.Protocols = append(entry.Protocols, protocol.String())
+	}
+	return entry
+}
+
+// templatePorts returns the ports the network, ssl and javascript requests of a template connect to
+func templatePorts(template *templates.Template) []string {
+	var ports []string
+	for _, request := range template.RequestsNetwork {
+		ports = append(ports, strings.Split(request.Port, ",")...)
+		for _, address := range request.Address {
+			ports = append(ports, addressPort(address))
+		}
+	}
+	for _, request := range template.RequestsSSL {
+		ports = append(ports, addressPort(request.Address))
+	}
+	for _, request := range template.RequestsJavascript {
+		for key, value := range request.Args {
+			if strings.EqualFold(key, "Port") {
+				ports = append(ports, strings.Split(types.ToString(value), ",")...)
+			}
+		}
+	}
+	var valid []string
+	for _, port := range ports {
+		port = strings.TrimSpace(port)
+		if _, err := strconv.Atoi(port); err == nil {
+			valid = append(valid, port)
+		}
+	}
+	return sliceutil.Dedupe(valid)
+}
+
+// addressPort returns the literal port of a host:port address
+func addressPort(address string) string {
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(address, "tls://"))
+	if err != nil {
+		return ""
+	}
+	return port
+}
+
+func stat(path string) (File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return File{}, err
+	}
+	return File{ModTime: info.ModTime().UnixNano(), Size: info.Size()}, nil
+}
@@ -0,0 +1,125 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+)
+
+func newTestEntries() []*Entry {
+	tomcat := &templates.Template{
+		ID:   "CVE-2024-1234",
+		Path: "http/cves/2024/CVE-2024-1234.yaml",
+		Info: model.Info{
+			Name:           "Apache Tomcat - Remote Code Execution",
+			Authors:        stringslice.StringSlice{Value: "pdteam"},
+			Tags:           stringslice.StringSlice{Value: []string{"cve", "rce", "tomcat"}},
+			SeverityHolder: severity.Holder{Severity: severity.Critical},
+			Metadata:       map[string]interface{}{"vendor": "apache", "product": "tomcat"},
+			Classification: &model.Classification{
+				CVEID:     stringslice.StringSlice{Value: "CVE-2024-1234"},
+				CVSSScore: 9.8,
+				EPSSScore: 0.72,
+			},
+		},
+		RequestsHTTP: []*http.Request{{Operators: operators.Operators{Matchers: []*matchers.Matcher{{Type: matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher}}}}}},
+	}
+	redis := &templates.Template{
+		ID:   "redis-unauth",
+		Path: "network/misconfig/redis-unauth.yaml",
+		Info: model.Info{
+			Name:           "Redis Unauthenticated Access",
+			Authors:        stringslice.StringSlice{Value: "pdteam"},
+			Tags:           stringslice.StringSlice{Value: []string{"network", "redis"}},
+			SeverityHolder: severity.Holder{Severity: severity.High},
+		},
+		RequestsNetwork: []*network.Request{{Port: "6379,8443", Address: []string{"{{Hostname}}"}}},
+	}
+	return []*Entry{
+		NewEntry(tomcat, []byte("path: '{{BaseURL}}/?x={{interactsh-url}}'")),
+		NewEntry(redis, nil),
+	}
+}
+
+func TestNewEntry(t *testing.T) {
+	entries := newTestEntries()
+	require.Equal(t, "apache", entries[0].Vendor)
+	require.Equal(t, []string{"CVE-2024-1234"}, entries[0].CVEID)
+	require.Equal(t, []string{"http"}, entries[0].Protocols)
+	require.Equal(t, []string{"word"}, entries[0].Matchers)
+	require.True(t, entries[0].OAST, "could not detect oast template")
+
+	require.Equal(t, []string{"6379", "8443"}, entries[1].Ports)
+	require.Equal(t, []string{"tcp"}, entries[1].Protocols)
+	require.False(t, entries[1].OAST, "detected oast in template without interactsh")
+}
+
+func TestQuery(t *testing.T) {
+	index := New("")
+	for _, entry := range newTestEntries() {
+		index.Entries[entry.Path] = entry
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"cve:cve-2024-1234", []string{"CVE-2024-1234"}},
+		{"vendor:apache", []string{"CVE-2024-1234"}},
+		{"port:8443", []string{"redis-unauth"}},
+		{"epss>0.5 oast:true", []string{"CVE-2024-1234"}},
+		{"epss>0.5 oast:false", nil},
+		{"cvss>=9.8 severity:critical", []string{"CVE-2024-1234"}},
+		{"-tag:rce", []string{"redis-unauth"}},
+		{"author:pdteam", []string{"CVE-2024-1234", "redis-unauth"}},
+		{`"remote code"`, []string{"CVE-2024-1234"}},
+		{`name:"unauthenticated access"`, []string{"redis-unauth"}},
+		{"redis", []string{"redis-unauth"}},
+		{"protocol:tcp matcher:word", nil},
+	}
+	for _, test := range tests {
+		query, err := ParseQuery(test.query)
+		require.Nil(t, err, "could not parse query %s", test.query)
+		var ids []string
+		for _, entry := range index.Search(query) {
+			ids = append(ids, entry.ID)
+		}
+		require.Equal(t, test.expected, ids, "could not get correct results for query %s", test.query)
+	}
+
+	for _, invalid := range []string{"unknown:value", "epss>high", "tag>1", "oast:maybe", `"unterminated`} {
+		_, err := ParseQuery(invalid)
+		require.NotNil(t, err, "could parse invalid query %s", invalid)
+	}
+}
+
+func TestIndexFresh(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "template.yaml")
+	require.Nil(t, os.WriteFile(path, []byte("id: test"), 0644))
+
+	index := New("fingerprint")
+	index.AddFiles([]string{path})
+	indexPath := filepath.Join(dir, "cache", FileName)
+	require.Nil(t, index.Save(indexPath), "could not save index")
+
+	loaded, err := Load(indexPath)
+	require.Nil(t, err, "could not load index")
+	require.True(t, loaded.Fresh("fingerprint", []string{path}), "unchanged index is not fresh")
+	require.False(t, loaded.Fresh("other", []string{path}), "index with other filters is fresh")
+	require.False(t, loaded.Fresh("fingerprint", []string{path, filepath.Join(dir, "new.yaml")}), "index with new template is fresh")
+
+	require.Nil(t, os.WriteFile(path, []byte("id: modified"), 0644))
+	require.False(t, loaded.Fresh("fingerprint", []string{path}), "index with modified template is fresh")
+}
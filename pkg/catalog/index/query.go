@@ -0,0 +1,247 @@
+package index
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// Query is a search of the index made of field filters and full-text terms
+// which all have to match, for example:
+//
+//	vendor:apache port:8443 epss>0.5 oast:false -tag:dos "remote code"
+//
+// Filters prefixed with - are negated and quoted terms are always matched as full-text.
+type Query struct {
+	filters []*queryFilter
+}
+
+type queryFilter struct {
+	field    string
+	operator string
+	value    string
+	negate   bool
+}
+
+// textFields are the fields matched by substring
+var textFields = map[string]func(*Entry) []string{
+	"id":          func(e *Entry) []string { return []string{e.ID} },
+	"name":        func(e *Entry) []string { return []string{e.Name} },
+	"description": func(e *Entry) []string { return []string{e.Description} },
+	"path":        func(e *Entry) []string { return []string{e.Path} },
+	"cpe":         func(e *Entry) []string { return []string{e.CPE} },
+	"vendor":      func(e *Entry) []string { return []string{e.Vendor} },
+	"product":     func(e *Entry) []string { return []string{e.Product} },
+	"reference":   func(e *Entry) []string { return e.References },
+}
+
+// exactFields are the fields matched by case-insensitive equality with any of their values
+var exactFields = map[string]func(*Entry) []string{
+	"severity":  func(e *Entry) []string { return []string{e.Severity.Severity.String()} },
+	"author":    func(e *Entry) []string { return e.Authors },
+	"tag":       func(e *Entry) []string { return e.Tags },
+	"cve":       func(e *Entry) []string { return e.CVEID },
+	"cwe":       func(e *Entry) []string { return e.CWEID },
+	"protocol":  func(e *Entry) []string { return e.Protocols },
+	"port":      func(e *Entry) []string { return e.Ports },
+	"matcher":   func(e *Entry) []string { return e.Matchers },
+	"extractor": func(e *Entry) []string { return e.Extractors },
+}
+
+// numberFields are the fields compared numerically
+var numberFields = map[string]func(*Entry) float64{
+	"cvss":            func(e *Entry) float64 { return e.CVSSScore },
+	"epss":            func(e *Entry) float64 { return e.EPSSScore },
+	"epss-percentile": func(e *Entry) float64 { return e.EPSSPercentile },
+	"requests":        func(e *Entry) float64 { return float64(e.Requests) },
+}
+
+// boolFields are the fields matched against true or false
+var boolFields = map[string]func(*Entry) bool{
+	"oast": func(e *Entry) bool { return e.OAST },
+}
+
+// queryOperators are the supported operators ordered so that longer ones are matched first
+var queryOperators = []string{">=", "<=", ">", "<", ":", "="}
+
+// ParseQuery parses a query of field filters and full-text terms
+func ParseQuery(value string) (*Query, error) {
+	tokens, err := tokenize(value)
+	if err != nil {
+		return nil, err
+	}
+	query := &Query{}
+	for _, token := range tokens {
+		filter := &queryFilter{value: strings.ToLower(token.value)}
+		if !token.quoted {
+			if strings.HasPrefix(filter.value, "-") && len(filter.value) > 1 {
+				filter.negate = true
+				filter.value = filter.value[1:]
+			}
+			if index, operator := findOperator(filter.value); index > 0 {
+				filter.field, filter.operator, filter.value = filter.value[:index], operator, filter.value[index+len(operator):]
+				if err := filter.validate(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		query.filters = append(query.filters, filter)
+	}
+	return query, nil
+}
+
+// Match returns true if all the filters and terms of the query match the entry
+func (q *Query) Match(entry *Entry) bool {
+	for _, filter := range q.filters {
+		if filter.match(entry) == filter.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *queryFilter) validate() error {
+	if _, ok := numberFields[f.field]; ok {
+		if _, err := strconv.ParseFloat(f.value, 64); err != nil {
+			return errorutil.New("invalid number %q for field %s", f.value, f.field)
+		}
+		return nil
+	}
+	if f.operator != ":" && f.operator != "=" {
+		return errorutil.New("operator %s is only supported for numeric fields", f.operator)
+	}
+	if _, ok := boolFields[f.field]; ok {
+		if _, err := strconv.ParseBool(f.value); err != nil {
+			return errorutil.New("invalid boolean %q for field %s", f.value, f.field)
+		}
+		return nil
+	}
+	_, text := textFields[f.field]
+	_, exact := exactFields[f.field]
+	if !text && !exact {
+		return errorutil.New("unknown field %s, available fields: %s", f.field, strings.Join(Fields(), ", "))
+	}
+	return nil
+}
+
+func (f *queryFilter) match(entry *Entry) bool {
+	switch {
+	case f.field == "":
+		return strings.Contains(fullText(entry), f.value)
+	case textFields[f.field] != nil:
+		for _, value := range textFields[f.field](entry) {
+			if strings.Contains(strings.ToLower(value), f.value) {
+				return true
+			}
+		}
+	case exactFields[f.field] != nil:
+		for _, value := range exactFields[f.field](entry) {
+			if strings.EqualFold(value, f.value) {
+				return true
+			}
+		}
+	case numberFields[f.field] != nil:
+		value := numberFields[f.field](entry)
+		expected, _ := strconv.ParseFloat(f.value, 64)
+		switch f.operator {
+		case ">=":
+			return value >= expected
+		case "<=":
+			return value <= expected
+		case ">":
+			return value > expected
+		case "<":
+			return value < expected
+		default:
+			return value == expected
+		}
+	case boolFields[f.field] != nil:
+		expected, _ := strconv.ParseBool(f.value)
+		return boolFields[f.field](entry) == expected
+	}
+	return false
+}
+
+// Fields returns the sorted names of the fields supported in queries
+func Fields() []string {
+	var fields []string
+	for field := range textFields {
+		fields = append(fields, field)
+	}
+	for field := range exactFields {
+		fields = append(fields, field)
+	}
+	for field := range numberFields {
+		fields = append(fields, field)
+	}
+	for field := range boolFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// fullText returns the lowercase searchable text of an entry
+func fullText(entry *Entry) string {
+	parts := []string{entry.ID, entry.Name, entry.Description, entry.Vendor, entry.Product, entry.CPE}
+	for _, values := range [][]string{entry.Tags, entry.Authors, entry.CVEID, entry.CWEID, entry.References} {
+		parts = append(parts, values...)
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+// findOperator returns the position and the operator following a field name in a token
+func findOperator(token string) (int, string) {
+	index := strings.IndexAny(token, "<>:=")
+	if index <= 0 {
+		return -1, ""
+	}
+	for _, operator := range queryOperators {
+		if strings.HasPrefix(token[index:], operator) {
+			return index, operator
+		}
+	}
+	return -1, ""
+}
+
+type token struct {
+	value  string
+	quoted bool
+}
+
+// tokenize splits a query on whitespace keeping double quoted terms together
+func tokenize(value string) ([]token, error) {
+	var tokens []token
+	var current strings.Builder
+	var quoted, inQuotes bool
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, token{value: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		quoted = false
+	}
+	for _, char := range value {
+		switch {
+		case char == '"':
+			if inQuotes {
+				flush()
+			} else {
+				// only terms starting with a quote are full-text, field values can be quoted too
+				quoted = current.Len() == 0
+			}
+			inQuotes = !inQuotes
+		case !inQuotes && (char == ' ' || char == '\t'):
+			flush()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	if inQuotes {
+		return nil, errorutil.New("unterminated quote in query %q", value)
+	}
+	flush()
+	return tokens, nil
+}
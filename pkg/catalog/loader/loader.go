@@ -14,6 +14,7 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/index"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/loader/filter"
 	"github.com/projectdiscovery/nuclei/v3/pkg/keys"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
@@ -65,6 +66,9 @@ type Config struct {
 
 	Catalog         catalog.Catalog
 	ExecutorOptions protocols.ExecutorOptions
+
+	// MetadataIndex is filled with the metadata of templates loaded with LoadTemplatesOnlyMetadata (optional)
+	MetadataIndex *index.Index
 }
 
 // Store is a storage for loaded nuclei templates
@@ -266,6 +270,16 @@ func init() {
 	templateIDPathMap = make(map[string]string)
 }
 
+// TemplatePaths returns the paths of the templates of the store before they are loaded
+func (store *Store) TemplatePaths() []string {
+	templatePaths, _ := store.config.Catalog.GetTemplatesPath(store.finalTemplates)
+	var paths []string
+	for templatePath := range store.pathFilter.Match(templatePaths) {
+		paths = append(paths, templatePath)
+	}
+	return paths
+}
+
 // LoadTemplatesOnlyMetadata loads only the metadata of the templates
 func (store *Store) LoadTemplatesOnlyMetadata() error {
 	templatePaths, errs := store.config.Catalog.GetTemplatesPath(store.finalTemplates)
@@ -297,7 +311,13 @@ func (store *Store) LoadTemplatesOnlyMetadata() error {
 	templatesCache := parserItem.Cache()
 
 	for templatePath := range validPaths {
-		template, _, _ := templatesCache.Has(templatePath)
+		template, raw, _ := templatesCache.Has(templatePath)
+
+		// templates are indexed regardless of the protocols enabled for execution
+		if template != nil && store.config.MetadataIndex != nil {
+			template.Path = templatePath
+			store.config.MetadataIndex.Add(template, raw)
+		}
 
 		if len(template.RequestsHeadless) > 0 && !store.config.ExecutorOptions.Options.Headless {
 			continue
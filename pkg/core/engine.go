@@ -1,9 +1,13 @@
 package core
 
 import (
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types/shard"
 )
 
 // Engine is an executer for running Nuclei Templates/Workflows.
@@ -19,6 +23,14 @@ type Engine struct {
 	options      *types.Options
 	executerOpts protocols.ExecutorOptions
 	Callback     func(*output.ResultEvent) // Executed on results
+
+	// inputProvider is the input provider of the scan pivot targets are deduped with
+	inputProvider provider.InputProvider
+	// pivotInputs contains the targets scanned by pivots of providers not storing them
+	pivotInputs map[string]struct{}
+	pivotMutex  sync.Mutex
+	// shard is the shard of template and target pairs to execute
+	shard *shard.Shard
 }
 
 // New returns a new Engine instance
@@ -26,6 +38,8 @@ func New(options *types.Options) *Engine {
 	engine := &Engine{
 		options: options,
 	}
+	// invalid shards are reported by options validation
+	engine.shard, _ = shard.Parse(options.Shard)
 	engine.workPool = engine.GetWorkPool()
 	return engine
 }
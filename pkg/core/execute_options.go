@@ -35,6 +35,7 @@ func (e *Engine) ExecuteWithResults(ctx context.Context, templatesList []*templa
 func (e *Engine) ExecuteScanWithOpts(ctx context.Context, templatesList []*templates.Template, target provider.InputProvider, noCluster bool) *atomic.Bool {
 	results := &atomic.Bool{}
 	selfcontainedWg := &sync.WaitGroup{}
+	e.inputProvider = target
 
 	totalReqBeforeCluster := getRequestCount(templatesList) * int(target.Count())
 
@@ -63,7 +64,17 @@ func (e *Engine) ExecuteScanWithOpts(ctx context.Context, templatesList []*templ
 		// workflow requests are not counted as they can be conditional
 		// templateList count is user requested templates count (before clustering)
 		// totalReqAfterClustering is total requests count after clustering
-		e.executerOpts.Progress.Init(target.Count(), len(templatesList), int64(totalReqAfterClustering))
+		if observable, ok := target.(provider.ObservableInput); ok {
+			// inputs added after the start of the scan are added to the totals as they are received
+			requestsPerInput := int64(getRequestCount(finalTemplates))
+			observable.Observe(func(count int64) {
+				e.executerOpts.Progress.Init(count, len(templatesList), requestsPerInput*count)
+			}, func() {
+				e.executerOpts.Progress.AddToTotal(requestsPerInput)
+			})
+		} else {
+			e.executerOpts.Progress.Init(target.Count(), len(templatesList), int64(totalReqAfterClustering))
+		}
 	}
 
 	if stringsutil.EqualFoldAny(e.options.ScanStrategy, scanstrategy.Auto.String(), "") {
@@ -20,6 +20,10 @@ import (
 // executeAllSelfContained executes all self contained templates that do not use `target`
 func (e *Engine) executeAllSelfContained(ctx context.Context, alltemplates []*templates.Template, results *atomic.Bool, sg *sync.WaitGroup) {
 	for _, v := range alltemplates {
+		// self contained templates are not bound to a target
+		if !e.shard.Includes(v.ID, "") {
+			continue
+		}
 		sg.Add(1)
 		go func(template *templates.Template) {
 			defer sg.Done()
@@ -101,6 +105,10 @@ func (e *Engine) executeTemplateWithTargets(ctx context.Context, template *templ
 			// skip is already false - but leaving it here for clarity
 			skip = false
 		}
+		if !skip && !e.shard.Includes(template.ID, scannedValue.ID()) {
+			gologger.Debug().Msgf("[%s] Skipping \"%s\": Target not in shard %s\n", template.ID, scannedValue.Input, e.shard)
+			skip = true
+		}
 
 		currentInfo.Lock()
 		currentInfo.InFlight[index] = struct{}{}
@@ -138,6 +146,9 @@ func (e *Engine) executeTemplateWithTargets(ctx context.Context, template *templ
 				} else {
 					match, err = template.Executer.Execute(ctx)
 				}
+				if template.Pivot != nil && e.executePivot(ctx, template.Pivot, ctx.PivotTargets(), 0) {
+					match = true
+				}
 			}
 			if err != nil {
 				gologger.Warning().Msgf("[%s] Could not execute step: %s\n", e.executerOpts.Colorizer.BrightBlue(template.ID), err)
@@ -170,6 +181,9 @@ func (e *Engine) executeTemplatesOnTarget(ctx context.Context, alltemplates []*t
 			return
 		default:
 		}
+		if !e.shard.Includes(tpl.ID, target.ID()) {
+			continue
+		}
 
 		// resize check point - nop if there are no changes
 		wp.RefreshWithConfig(e.GetWorkPoolConfig())
@@ -203,6 +217,9 @@ func (e *Engine) executeTemplatesOnTarget(ctx context.Context, alltemplates []*t
 				} else {
 					match, err = template.Executer.Execute(ctx)
 				}
+				if template.Pivot != nil && e.executePivot(ctx, template.Pivot, ctx.PivotTargets(), 0) {
+					match = true
+				}
 			}
 			if err != nil {
 				gologger.Warning().Msgf("[%s] Could not execute step: %s\n", e.executerOpts.Colorizer.BrightBlue(template.ID), err)
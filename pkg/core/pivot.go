@@ -0,0 +1,139 @@
+package core
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
+	"github.com/projectdiscovery/nuclei/v3/pkg/workflows"
+	syncutil "github.com/projectdiscovery/utils/sync"
+)
+
+const pivotExecutionError = "[%s] Could not execute pivot on %s: %s\n"
+
+// executePivot scans the targets spawned from an input with the templates of the pivot.
+// Targets passed by the pivot templates are pivoted again until the max depth is reached.
+func (e *Engine) executePivot(ctx *scan.ScanContext, pivot *workflows.Pivot, targets []string, depth int) bool {
+	if depth >= pivot.MaxDepth || len(targets) == 0 {
+		return false
+	}
+	results := &atomic.Bool{}
+	input := ctx.Input.MetaInput.Input
+
+	threads := 1
+	if e.options != nil && e.options.TemplateThreads > 0 {
+		threads = e.options.TemplateThreads
+	}
+	swg, _ := syncutil.New(syncutil.WithSize(threads))
+
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" || target == input || !pivot.InScope(input, target) {
+			continue
+		}
+		if !e.markPivotTarget(target) {
+			continue
+		}
+
+		swg.Add()
+		go func(target string) {
+			defer swg.Done()
+
+			metaInput := contextargs.NewMetaInput()
+			metaInput.Input = target
+			for _, executer := range pivot.Executers {
+				select {
+				case <-ctx.Context().Done():
+					return
+				default:
+				}
+				executer.Options.Progress.AddToTotal(int64(executer.Executer.Requests()))
+
+				ctxArgs := contextargs.New(ctx.Context())
+				ctxArgs.MetaInput = metaInput
+				subCtx := scan.NewScanContext(ctx.Context(), ctxArgs)
+
+				var matched bool
+				var err error
+				if e.Callback != nil {
+					var events []*output.ResultEvent
+					if events, err = executer.Executer.ExecuteWithResults(subCtx); err == nil {
+						for _, event := range events {
+							e.Callback(event)
+						}
+						matched = len(events) > 0
+					}
+				} else {
+					matched, err = executer.Executer.Execute(subCtx)
+				}
+				if err != nil {
+					gologger.Warning().Msgf(pivotExecutionError, executer.Options.TemplateID, target, err)
+				}
+				results.CompareAndSwap(false, matched)
+
+				if e.executePivot(subCtx, pivot, subCtx.PivotTargets(), depth+1) {
+					results.Store(true)
+				}
+			}
+		}(target)
+	}
+	swg.Wait()
+	return results.Load()
+}
+
+// markPivotTarget marks a target as scanned by pivots returning false if it is
+// an input of the scan or was already scanned by any pivot
+func (e *Engine) markPivotTarget(target string) bool {
+	if store, ok := e.inputProvider.(provider.SpawnedInputStore); ok {
+		return store.AddSpawned(target)
+	}
+	// targets of providers not storing spawned targets are only deduped between pivots
+	e.pivotMutex.Lock()
+	defer e.pivotMutex.Unlock()
+
+	if e.pivotInputs == nil {
+		e.pivotInputs = make(map[string]struct{})
+	}
+	if _, ok := e.pivotInputs[target]; ok {
+		return false
+	}
+	e.pivotInputs[target] = struct{}{}
+	return true
+}
+
+// pivotBuffer contains the pivot targets of a single workflow step
+type pivotBuffer struct {
+	mu     sync.Mutex
+	pivots []string
+}
+
+// add adds targets to the buffer
+func (b *pivotBuffer) add(targets []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pivots = append(b.pivots, targets...)
+}
+
+// addExtracted adds the values of the pivot extractors of an event to the buffer
+func (b *pivotBuffer) addExtracted(pivot *workflows.Pivot, event *output.InternalWrappedEvent) {
+	if pivot == nil || event.OperatorsResult == nil {
+		return
+	}
+	for _, name := range pivot.Extractors.ToSlice() {
+		b.add(event.OperatorsResult.Extracts[name])
+	}
+}
+
+// targets returns the targets of the buffer
+func (b *pivotBuffer) targets() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pivots
+}
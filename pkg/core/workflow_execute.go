@@ -8,6 +8,7 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/writer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
 	"github.com/projectdiscovery/nuclei/v3/pkg/workflows"
 	syncutil "github.com/projectdiscovery/utils/sync"
@@ -55,19 +56,33 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, ctx *scan
 	var err error
 	var mainErr error
 
+	// pivot targets of this step are kept apart from the ones of
+	// the other steps and subtemplates sharing the scan context
+	pivots := &pivotBuffer{}
+	if template.Pivot != nil {
+		ctx.OnPivot = pivots.add
+		defer func() { ctx.OnPivot = nil }()
+	}
+
 	if len(template.Matchers) == 0 {
 		for _, executer := range template.Executers {
 			executer.Options.Progress.AddToTotal(int64(executer.Executer.Requests()))
 
 			// Don't print results with subtemplates, only print results on template.
-			if len(template.Subtemplates) > 0 {
+			if len(template.Subtemplates) > 0 || template.Pivot != nil {
+				options := executer.Options
 				ctx.OnResult = func(result *output.InternalWrappedEvent) {
+					// results of steps without subtemplates are printed
+					if len(template.Subtemplates) == 0 {
+						writer.WriteResult(result, options.Output, options.Progress, options.IssuesClient)
+					}
 					if result.OperatorsResult == nil {
 						return
 					}
 					if len(result.Results) > 0 {
 						firstMatched = true
 					}
+					pivots.addExtracted(template.Pivot, result)
 
 					if result.OperatorsResult != nil && result.OperatorsResult.Extracts != nil {
 						for k, v := range result.OperatorsResult.Extracts {
@@ -126,6 +141,7 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, ctx *scan
 						ctx.Input.Set(k, v)
 					}
 				}
+				pivots.addExtracted(template.Pivot, event)
 
 				for _, matcher := range template.Matchers {
 					if !matcher.Match(event.OperatorsResult) {
@@ -158,8 +174,10 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, ctx *scan
 				continue
 			}
 		}
+		e.runWorkflowPivot(template, ctx, pivots.targets(), results)
 		return mainErr
 	}
+	e.runWorkflowPivot(template, ctx, pivots.targets(), results)
 	if len(template.Subtemplates) > 0 && firstMatched {
 		for _, subtemplate := range template.Subtemplates {
 			swg.Add()
@@ -176,3 +194,13 @@ func (e *Engine) runWorkflowStep(template *workflows.WorkflowTemplate, ctx *scan
 	}
 	return mainErr
 }
+
+// runWorkflowPivot scans the targets extracted by a workflow step with the templates of its pivot
+func (e *Engine) runWorkflowPivot(template *workflows.WorkflowTemplate, ctx *scan.ScanContext, targets []string, results *atomic.Bool) {
+	if template.Pivot == nil {
+		return
+	}
+	if e.executePivot(ctx, template.Pivot, targets, 0) {
+		results.Store(true)
+	}
+}
@@ -2,8 +2,10 @@ package core
 
 import (
 	"context"
+	"sync"
 	"testing"
 
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/provider"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
@@ -175,6 +177,108 @@ func TestWorkflowsSubtemplatesWithMatcherNoMatch(t *testing.T) {
 	require.Equal(t, "", secondInput, "could not get correct second input")
 }
 
+func TestWorkflowsPivot(t *testing.T) {
+	progressBar, _ := progress.NewStatsTicker(0, false, false, false, 0)
+
+	var mutex sync.Mutex
+	var pivoted []string
+	pivot := &workflows.Pivot{Extractors: stringslice.StringSlice{Value: "subdomains"}, Template: "tech-detect.yaml", Executers: []*workflows.ProtocolExecuterPair{{
+		Executer: &mockExecuter{result: true, executeHook: func(input *contextargs.MetaInput) {
+			mutex.Lock()
+			pivoted = append(pivoted, input.Input)
+			mutex.Unlock()
+		}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+	}}
+	require.Nil(t, pivot.Compile(), "could not compile pivot")
+
+	workflow := &workflows.Workflow{Options: &protocols.ExecutorOptions{Options: &types.Options{TemplateThreads: 10}}, Workflows: []*workflows.WorkflowTemplate{
+		{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, outputs: []*output.InternalWrappedEvent{
+				{OperatorsResult: &operators.Result{Extracts: map[string][]string{"subdomains": {"a.test.com", "b.test.com", "a.test.com", "test.org"}}}},
+				{OperatorsResult: &operators.Result{Extracts: map[string][]string{"subdomains": {"b.test.com"}}}},
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}, Pivot: pivot},
+	}}
+
+	engine := &Engine{}
+	input := contextargs.NewWithInput(context.Background(), "https://test.com")
+	ctx := scan.NewScanContext(context.Background(), input)
+	matched := engine.executeWorkflow(ctx, workflow)
+	require.True(t, matched, "could not get correct match value")
+
+	require.ElementsMatch(t, []string{"a.test.com", "b.test.com"}, pivoted, "could not get correct pivoted inputs")
+}
+
+func TestWorkflowsPivotSteps(t *testing.T) {
+	progressBar, _ := progress.NewStatsTicker(0, false, false, false, 0)
+
+	var mutex sync.Mutex
+	pivoted := make(map[string][]string)
+	newPivot := func(name, extractor string) *workflows.Pivot {
+		pivot := &workflows.Pivot{Extractors: stringslice.StringSlice{Value: extractor}, Template: name, Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, executeHook: func(input *contextargs.MetaInput) {
+				mutex.Lock()
+				pivoted[name] = append(pivoted[name], input.Input)
+				mutex.Unlock()
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}}
+		require.Nil(t, pivot.Compile(), "could not compile pivot")
+		return pivot
+	}
+
+	workflow := &workflows.Workflow{Options: &protocols.ExecutorOptions{Options: &types.Options{TemplateThreads: 10}}, Workflows: []*workflows.WorkflowTemplate{
+		{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, outputs: []*output.InternalWrappedEvent{
+				{OperatorsResult: &operators.Result{Extracts: map[string][]string{"subdomains": {"a.test.com", "other.test.com"}}}},
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}, Pivot: newPivot("first", "subdomains")},
+		{Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{result: true, outputs: []*output.InternalWrappedEvent{
+				{OperatorsResult: &operators.Result{Extracts: map[string][]string{"subdomains": {"b.test.com", "a.test.com"}}}},
+			}}, Options: &protocols.ExecutorOptions{Progress: progressBar}},
+		}, Matchers: []*workflows.Matcher{{Name: stringslice.StringSlice{Value: "missing"}}}, Pivot: newPivot("second", "subdomains")},
+	}}
+
+	engine := &Engine{inputProvider: provider.NewSimpleInputProviderWithUrls("https://test.com", "other.test.com")}
+	input := contextargs.NewWithInput(context.Background(), "https://test.com")
+	ctx := scan.NewScanContext(context.Background(), input)
+	require.True(t, engine.executeWorkflow(ctx, workflow), "could not get correct match value")
+
+	// inputs of the scan and targets scanned by another pivot are not scanned again,
+	// steps with matchers run their pivot once on their own targets only
+	require.Equal(t, []string{"a.test.com"}, pivoted["first"], "could not get correct pivoted inputs of first step")
+	require.Equal(t, []string{"b.test.com"}, pivoted["second"], "could not get correct pivoted inputs of second step")
+}
+
+func TestPivotCallbackMatch(t *testing.T) {
+	progressBar, _ := progress.NewStatsTicker(0, false, false, false, 0)
+
+	executePivot := func(outputs []*output.InternalWrappedEvent) (bool, int) {
+		pivot := &workflows.Pivot{Extractors: stringslice.StringSlice{Value: "subdomains"}, Template: "tech-detect.yaml", Executers: []*workflows.ProtocolExecuterPair{{
+			Executer: &mockExecuter{outputs: outputs}, Options: &protocols.ExecutorOptions{Progress: progressBar},
+		}}}
+		require.Nil(t, pivot.Compile(), "could not compile pivot")
+
+		var mutex sync.Mutex
+		var events int
+		engine := &Engine{Callback: func(*output.ResultEvent) {
+			mutex.Lock()
+			events++
+			mutex.Unlock()
+		}}
+		ctx := scan.NewScanContext(context.Background(), contextargs.NewWithInput(context.Background(), "https://test.com"))
+		return engine.executePivot(ctx, pivot, []string{"a.test.com"}, 0), events
+	}
+
+	matched, events := executePivot(nil)
+	require.False(t, matched, "pivot without results was reported as a match")
+	require.Zero(t, events)
+
+	matched, events = executePivot([]*output.InternalWrappedEvent{{Results: []*output.ResultEvent{{TemplateID: "tech-detect"}}}})
+	require.True(t, matched, "could not get pivot match")
+	require.Equal(t, 1, events)
+}
+
 type mockExecuter struct {
 	result      bool
 	executeHook func(input *contextargs.MetaInput)
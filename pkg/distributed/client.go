@@ -0,0 +1,158 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// Client is the client workers use to communicate with a coordinator
+type Client struct {
+	url        string
+	worker     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a new client for the coordinator at url identifying
+// as worker and authenticating with token
+func NewClient(url, worker, token string) *Client {
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	return &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		worker:     worker,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Worker returns the id of the worker of the client
+func (c *Client) Worker() string {
+	return c.worker
+}
+
+// Config returns the scan options of the coordinator
+func (c *Client) Config(ctx context.Context) (*WorkerConfig, error) {
+	resp, err := c.do(ctx, http.MethodGet, configEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+	config := &WorkerConfig{}
+	if err := json.NewDecoder(resp.Body).Decode(config); err != nil {
+		return nil, fmt.Errorf("could not decode worker config: %w", err)
+	}
+	return config, nil
+}
+
+// Lease leases a work unit from the coordinator. A nil unit is returned
+// if no unit is available yet and ErrScanCompleted once the scan is completed.
+func (c *Client) Lease(ctx context.Context) (*WorkUnit, error) {
+	resp, err := c.do(ctx, http.MethodPost, leaseEndpoint, &leaseRequest{Worker: c.worker})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return nil, nil
+	case http.StatusGone:
+		return nil, ErrScanCompleted
+	default:
+		return nil, responseError(resp)
+	}
+	unit := &WorkUnit{}
+	if err := json.NewDecoder(resp.Body).Decode(unit); err != nil {
+		return nil, fmt.Errorf("could not decode work unit: %w", err)
+	}
+	return unit, nil
+}
+
+// Heartbeat extends the lease of a work unit
+func (c *Client) Heartbeat(ctx context.Context, unit *WorkUnit) error {
+	resp, err := c.do(ctx, http.MethodPost, heartbeatEndpoint, &heartbeatRequest{Worker: c.worker, Unit: unit.ID})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// Complete sends the results of a work unit or the error it failed with to the coordinator
+func (c *Client) Complete(ctx context.Context, unit *WorkUnit, results []*output.ResultEvent, executeErr error) error {
+	request := &completeRequest{Worker: c.worker, Unit: unit.ID, Results: results}
+	if executeErr != nil {
+		request.Results = nil
+		request.Error = executeErr.Error()
+	}
+	resp, err := c.do(ctx, http.MethodPost, completeEndpoint, request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// Results sends results of interactions received after the completion
+// of the work unit they belong to to the coordinator
+func (c *Client) Results(ctx context.Context, results []*output.ResultEvent) error {
+	resp, err := c.do(ctx, http.MethodPost, resultsEndpoint, &resultsRequest{Worker: c.worker, Results: results})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	return c.httpClient.Do(req)
+}
+
+// responseError returns an error from an unexpected coordinator response
+func responseError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected coordinator response %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,410 @@
+package distributed
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+)
+
+// drainTimeout is the maximum time waited for workers to be
+// notified of the completion of the scan
+const drainTimeout = 5 * time.Second
+
+// Options contains the configuration of a coordinator
+type Options struct {
+	// Address is the address to listen on for workers
+	Address string
+	// Token is the shared secret workers authenticate with as bearer token
+	Token string
+	// Units are the work units to assign to workers
+	Units []*WorkUnit
+	// MaxAttempts is the maximum number of attempts of a work unit
+	MaxAttempts int
+	// LeaseTimeout is the time after which a leased unit without
+	// heartbeats from its worker is retried
+	LeaseTimeout time.Duration
+	// Config contains the scan options sent to workers
+	Config WorkerConfig
+	// Output is the writer results of workers are written to
+	Output output.Writer
+	// IssuesClient is the optional client issues are created with for results of workers
+	IssuesClient reporting.Client
+}
+
+type unitStatus int
+
+const (
+	unitPending unitStatus = iota
+	unitLeased
+	unitCompleted
+	unitFailed
+)
+
+// unitState is the state of a work unit in the coordinator
+type unitState struct {
+	unit     *WorkUnit
+	status   unitStatus
+	worker   string
+	deadline time.Time
+	attempts int
+}
+
+// Coordinator assigns work units to workers, retries units of failed
+// workers and writes the results of completed units to the output
+type Coordinator struct {
+	options *Options
+
+	mu        sync.Mutex
+	units     map[string]*unitState
+	pending   []*unitState
+	remaining int
+	failed    int
+	workers   map[string]struct{}
+	done      chan struct{}
+	results   atomic.Int64
+
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewCoordinator creates a new coordinator for the work units
+func NewCoordinator(options *Options) *Coordinator {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.LeaseTimeout <= 0 {
+		options.LeaseTimeout = DefaultLeaseTimeout
+	}
+	options.Config.LeaseTimeout = options.LeaseTimeout
+
+	coordinator := &Coordinator{
+		options: options,
+		units:   make(map[string]*unitState),
+		workers: make(map[string]struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, unit := range options.Units {
+		state := &unitState{unit: unit}
+		coordinator.units[unit.ID] = state
+		coordinator.pending = append(coordinator.pending, state)
+	}
+	coordinator.remaining = len(options.Units)
+	if coordinator.remaining == 0 {
+		close(coordinator.done)
+	}
+	return coordinator
+}
+
+// Start starts listening for workers on the address of the coordinator
+func (c *Coordinator) Start() error {
+	if c.options.Token == "" {
+		return errors.New("no token specified for workers")
+	}
+	listener, err := net.Listen("tcp", c.options.Address)
+	if err != nil {
+		return err
+	}
+	c.listener = listener
+	c.server = &http.Server{Handler: c.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := c.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			gologger.Error().Msgf("Could not serve distributed workers: %s\n", err)
+		}
+	}()
+	return nil
+}
+
+// Address returns the address the coordinator is listening on
+func (c *Coordinator) Address() string {
+	if c.listener == nil {
+		return c.options.Address
+	}
+	return c.listener.Addr().String()
+}
+
+// Handler returns the http handler of the coordinator
+func (c *Coordinator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(configEndpoint, c.handleConfig)
+	mux.HandleFunc(leaseEndpoint, c.handleLease)
+	mux.HandleFunc(heartbeatEndpoint, c.handleHeartbeat)
+	mux.HandleFunc(completeEndpoint, c.handleComplete)
+	mux.HandleFunc(resultsEndpoint, c.handleResults)
+	return c.authenticate(mux)
+}
+
+// authenticate rejects requests without the bearer token of the coordinator.
+// All the requests are rejected if the coordinator has no token.
+func (c *Coordinator) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || c.options.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.options.Token)) != 1 {
+			http.Error(w, "invalid worker token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Wait waits for all the work units to be completed or failed and
+// for the workers to be notified of the completion of the scan
+func (c *Coordinator) Wait(ctx context.Context) error {
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// give polling workers a chance to exit before the coordinator is closed
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(drainTimeout)
+	for {
+		c.mu.Lock()
+		workers := len(c.workers)
+		c.mu.Unlock()
+		if workers == 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Results returns the number of results written by the coordinator
+func (c *Coordinator) Results() int64 {
+	return c.results.Load()
+}
+
+// Failed returns the number of work units which failed after all attempts
+func (c *Coordinator) Failed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.failed
+}
+
+// Close stops the coordinator
+func (c *Coordinator) Close() error {
+	if c.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	return c.server.Shutdown(ctx)
+}
+
+func (c *Coordinator) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Unsupported HTTP method", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, c.options.Config)
+}
+
+func (c *Coordinator) handleLease(w http.ResponseWriter, r *http.Request) {
+	var request leaseRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	c.mu.Lock()
+	c.requeueExpired(time.Now())
+	if c.remaining == 0 {
+		delete(c.workers, request.Worker)
+		c.mu.Unlock()
+		http.Error(w, ErrScanCompleted.Error(), http.StatusGone)
+		return
+	}
+	c.workers[request.Worker] = struct{}{}
+	state := c.nextPending()
+	if state == nil {
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	state.status = unitLeased
+	state.worker = request.Worker
+	state.deadline = time.Now().Add(c.options.LeaseTimeout)
+	state.attempts++
+	unit := *state.unit
+	unit.Attempt = state.attempts
+	c.mu.Unlock()
+
+	gologger.Verbose().Msgf("Assigned work unit %s (attempt %d) to worker %s\n", unit.ID, unit.Attempt, request.Worker)
+	writeJSON(w, unit)
+}
+
+func (c *Coordinator) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var request heartbeatRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.units[request.Unit]
+	if !ok || state.status != unitLeased || state.worker != request.Worker {
+		http.Error(w, "work unit is not leased by worker", http.StatusConflict)
+		return
+	}
+	state.deadline = time.Now().Add(c.options.LeaseTimeout)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *Coordinator) handleComplete(w http.ResponseWriter, r *http.Request) {
+	var request completeRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	c.mu.Lock()
+	state, ok := c.units[request.Unit]
+	if !ok {
+		c.mu.Unlock()
+		http.Error(w, "unknown work unit", http.StatusNotFound)
+		return
+	}
+	// results and errors of workers whose lease expired or was reassigned
+	// are discarded, the unit is completed by the current lease holder
+	if state.status != unitLeased || state.worker != request.Worker {
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if request.Error != "" {
+		gologger.Warning().Msgf("Worker %s could not execute work unit %s: %s\n", request.Worker, request.Unit, request.Error)
+		c.retry(state)
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	c.finish(state, unitCompleted)
+	c.mu.Unlock()
+
+	for _, result := range request.Results {
+		c.writeResult(result)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleResults writes results of interactions a worker received after
+// completing the work unit they belong to
+func (c *Coordinator) handleResults(w http.ResponseWriter, r *http.Request) {
+	var request resultsRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	for _, result := range request.Results {
+		c.writeResult(result)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeResult writes a result of a worker to the output and the issue trackers
+func (c *Coordinator) writeResult(result *output.ResultEvent) {
+	c.results.Add(1)
+	if c.options.IssuesClient != nil {
+		if err := c.options.IssuesClient.CreateIssue(result); err != nil {
+			gologger.Warning().Msgf("Could not create issue on tracker: %s", err)
+		}
+	}
+	if c.options.Output != nil {
+		if err := c.options.Output.Write(result); err != nil {
+			gologger.Warning().Msgf("Could not write output event: %s\n", err)
+		}
+	}
+}
+
+// requeueExpired retries the units whose lease expired
+// Note: it must be called with the lock held
+func (c *Coordinator) requeueExpired(now time.Time) {
+	for _, state := range c.units {
+		if state.status == unitLeased && now.After(state.deadline) {
+			gologger.Warning().Msgf("Lease of work unit %s by worker %s expired\n", state.unit.ID, state.worker)
+			c.retry(state)
+		}
+	}
+}
+
+// retry requeues a unit or marks it as failed once all the attempts are used
+// Note: it must be called with the lock held
+func (c *Coordinator) retry(state *unitState) {
+	if state.attempts >= c.options.MaxAttempts {
+		gologger.Error().Msgf("Work unit %s failed after %d attempts\n", state.unit.ID, state.attempts)
+		c.failed++
+		c.finish(state, unitFailed)
+		return
+	}
+	state.status = unitPending
+	state.worker = ""
+	c.pending = append(c.pending, state)
+}
+
+// nextPending removes and returns the first pending unit of the queue
+// skipping units which are no longer pending
+// Note: it must be called with the lock held
+func (c *Coordinator) nextPending() *unitState {
+	for len(c.pending) > 0 {
+		state := c.pending[0]
+		c.pending = c.pending[1:]
+		if state.status == unitPending {
+			return state
+		}
+	}
+	return nil
+}
+
+// finish marks a unit as completed or failed
+// Note: it must be called with the lock held
+func (c *Coordinator) finish(state *unitState, status unitStatus) {
+	state.status = status
+	state.worker = ""
+	for i, pending := range c.pending {
+		if pending == state {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			break
+		}
+	}
+	c.remaining--
+	if c.remaining == 0 {
+		close(c.done)
+	}
+}
+
+// decodeRequest decodes the json body of a POST request writing an error response on failure
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Unsupported HTTP method", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
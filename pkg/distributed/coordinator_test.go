@@ -0,0 +1,218 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+const testToken = "test-token"
+
+func TestShard(t *testing.T) {
+	units := Shard([]string{"a.yaml", "b.yaml"}, []string{"1", "2", "3"}, 2)
+	require.Len(t, units, 4, "could not get correct number of units")
+	require.Equal(t, []string{"a.yaml"}, units[0].Templates)
+	require.Equal(t, []string{"1", "2"}, units[0].Targets)
+	require.Equal(t, []string{"3"}, units[1].Targets)
+	require.Equal(t, []string{"b.yaml"}, units[3].Templates)
+	require.Equal(t, "4", units[3].ID)
+}
+
+func newTestCoordinator(t *testing.T, options *Options) (*Coordinator, *[]*output.ResultEvent, func(worker string) *Client) {
+	var mu sync.Mutex
+	var written []*output.ResultEvent
+	writer := testutils.NewMockOutputWriter(false)
+	writer.WriteCallback = func(event *output.ResultEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		written = append(written, event)
+	}
+	options.Output = writer
+	if options.Token == "" {
+		options.Token = testToken
+	}
+
+	coordinator := NewCoordinator(options)
+	server := httptest.NewServer(coordinator.Handler())
+	t.Cleanup(server.Close)
+	return coordinator, &written, func(worker string) *Client {
+		return NewClient(server.URL, worker, testToken)
+	}
+}
+
+func TestCoordinatorComplete(t *testing.T) {
+	ctx := context.Background()
+	coordinator, written, newClient := newTestCoordinator(t, &Options{
+		Units:  Shard([]string{"a.yaml"}, []string{"1", "2"}, 1),
+		Config: WorkerConfig{TemplateThreads: 7},
+	})
+	first, second := newClient("first"), newClient("second")
+
+	config, err := first.Config(ctx)
+	require.Nil(t, err, "could not get config")
+	require.Equal(t, 7, config.TemplateThreads)
+	require.Equal(t, DefaultLeaseTimeout, config.LeaseTimeout)
+
+	unit, err := first.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+	require.Equal(t, 1, unit.Attempt)
+	other, err := second.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+	require.NotEqual(t, unit.ID, other.ID)
+
+	// no more units are pending until the leases are completed or expire
+	pending, err := first.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+	require.Nil(t, pending, "got unit with no pending units")
+
+	require.Nil(t, first.Heartbeat(ctx, unit), "could not extend lease")
+	require.NotNil(t, second.Heartbeat(ctx, unit), "could extend lease of another worker")
+
+	require.Nil(t, first.Complete(ctx, unit, []*output.ResultEvent{{TemplateID: "a"}}, nil))
+	// completing a unit twice does not write results again
+	require.Nil(t, second.Complete(ctx, unit, []*output.ResultEvent{{TemplateID: "a"}}, nil))
+	require.Nil(t, second.Complete(ctx, other, nil, nil))
+
+	for _, client := range []*Client{first, second} {
+		_, err = client.Lease(ctx)
+		require.True(t, errors.Is(err, ErrScanCompleted), "could not get scan completed error")
+	}
+	require.Nil(t, coordinator.Wait(ctx), "could not wait for scan")
+	require.Len(t, *written, 1, "could not get correct number of results")
+	require.Equal(t, int64(1), coordinator.Results())
+}
+
+func TestCoordinatorRetry(t *testing.T) {
+	ctx := context.Background()
+	coordinator, _, newClient := newTestCoordinator(t, &Options{
+		Units:       Shard([]string{"a.yaml"}, []string{"1"}, 1),
+		MaxAttempts: 2,
+	})
+	client := newClient("worker")
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		unit, err := client.Lease(ctx)
+		require.Nil(t, err, "could not lease unit")
+		require.Equal(t, attempt, unit.Attempt)
+		require.Nil(t, client.Complete(ctx, unit, nil, errors.New("failed")))
+	}
+	_, err := client.Lease(ctx)
+	require.True(t, errors.Is(err, ErrScanCompleted), "could not get scan completed error")
+	require.Nil(t, coordinator.Wait(ctx), "could not wait for scan")
+	require.Equal(t, 1, coordinator.Failed(), "could not fail unit after all attempts")
+}
+
+func TestCoordinatorLeaseExpiry(t *testing.T) {
+	ctx := context.Background()
+	coordinator, written, newClient := newTestCoordinator(t, &Options{
+		Units:        Shard([]string{"a.yaml"}, []string{"1"}, 1),
+		LeaseTimeout: 50 * time.Millisecond,
+	})
+	crashed, alive := newClient("crashed"), newClient("alive")
+
+	unit, err := crashed.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+
+	time.Sleep(100 * time.Millisecond)
+	retried, err := alive.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+	require.NotNil(t, retried, "could not retry expired unit")
+	require.Equal(t, unit.ID, retried.ID)
+	require.Equal(t, 2, retried.Attempt)
+
+	// errors of the worker whose lease expired are ignored
+	require.Nil(t, crashed.Complete(ctx, unit, nil, errors.New("failed")))
+	require.Nil(t, alive.Complete(ctx, retried, []*output.ResultEvent{{TemplateID: "a"}}, nil))
+
+	for _, client := range []*Client{crashed, alive} {
+		_, err = client.Lease(ctx)
+		require.True(t, errors.Is(err, ErrScanCompleted), "could not get scan completed error")
+	}
+	require.Nil(t, coordinator.Wait(ctx), "could not wait for scan")
+	require.Equal(t, 0, coordinator.Failed())
+	require.Len(t, *written, 1, "could not get correct number of results")
+}
+
+func TestCoordinatorStaleComplete(t *testing.T) {
+	ctx := context.Background()
+	coordinator, written, newClient := newTestCoordinator(t, &Options{
+		Units:        Shard([]string{"a.yaml"}, []string{"1", "2"}, 1),
+		LeaseTimeout: 50 * time.Millisecond,
+	})
+	stale, first, second := newClient("stale"), newClient("first"), newClient("second")
+
+	unit, err := stale.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+
+	// the expired unit is requeued after the other unit
+	time.Sleep(100 * time.Millisecond)
+	other, err := first.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+	require.NotEqual(t, unit.ID, other.ID)
+
+	// completions of the worker whose lease expired are discarded
+	require.Nil(t, stale.Complete(ctx, unit, []*output.ResultEvent{{TemplateID: "stale"}}, nil))
+	require.Nil(t, first.Complete(ctx, other, nil, nil))
+
+	retried, err := second.Lease(ctx)
+	require.Nil(t, err, "could not lease unit")
+	require.NotNil(t, retried, "could not retry expired unit")
+	require.Equal(t, unit.ID, retried.ID)
+	require.Nil(t, stale.Complete(ctx, unit, []*output.ResultEvent{{TemplateID: "stale"}}, nil))
+
+	// the scan is not completed while the retried unit is leased
+	pending, err := first.Lease(ctx)
+	require.Nil(t, err, "scan completed while unit is leased")
+	require.Nil(t, pending, "got unit with no pending units")
+
+	require.Nil(t, second.Complete(ctx, retried, []*output.ResultEvent{{TemplateID: "a"}}, nil))
+	_, err = first.Lease(ctx)
+	require.True(t, errors.Is(err, ErrScanCompleted), "could not get scan completed error")
+	require.Len(t, *written, 1, "could not get correct number of results")
+	require.Equal(t, "a", (*written)[0].TemplateID)
+	require.Equal(t, 0, coordinator.Failed())
+}
+
+func TestCoordinatorAuthentication(t *testing.T) {
+	ctx := context.Background()
+	coordinator, written, newClient := newTestCoordinator(t, &Options{
+		Units:  Shard([]string{"a.yaml"}, []string{"1"}, 1),
+		Config: WorkerConfig{InteractshToken: "secret"},
+	})
+	server := httptest.NewServer(coordinator.Handler())
+	defer server.Close()
+
+	for _, token := range []string{"", "wrong-token", testToken + "x"} {
+		client := NewClient(server.URL, "forged", token)
+		_, err := client.Config(ctx)
+		require.NotNil(t, err, "could get config with token %q", token)
+		require.NotNil(t, client.Results(ctx, []*output.ResultEvent{{TemplateID: "forged"}}), "could send results with token %q", token)
+	}
+	resp, err := http.Get(server.URL + configEndpoint)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode, "could get config without token")
+	require.Empty(t, *written, "wrote forged results")
+
+	config, err := newClient("worker").Config(ctx)
+	require.Nil(t, err, "could not get config with token")
+	require.Equal(t, "secret", config.InteractshToken)
+
+	// results of late interactions are written by the coordinator
+	writer := NewResultWriter(newClient("worker"))
+	require.Nil(t, writer.Write(&output.ResultEvent{TemplateID: "interactsh"}))
+	require.Len(t, *written, 1, "could not write late result")
+	require.Equal(t, int64(1), coordinator.Results())
+
+	noToken := NewCoordinator(&Options{Address: "127.0.0.1:0"})
+	require.NotNil(t, noToken.Start(), "could start coordinator without token")
+}
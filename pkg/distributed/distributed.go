@@ -0,0 +1,172 @@
+// Package distributed implements distributed scanning where a coordinator
+// shards (template, targets) work units and assigns them to stateless
+// worker processes over a simple HTTP/JSON protocol.
+package distributed
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+const (
+	// DefaultUnitSize is the default number of targets per work unit
+	DefaultUnitSize = 25
+	// DefaultMaxAttempts is the default number of attempts of a work unit
+	DefaultMaxAttempts = 3
+	// DefaultLeaseTimeout is the default time after which a work unit
+	// leased by a worker which stopped sending heartbeats is retried
+	DefaultLeaseTimeout = time.Minute
+)
+
+// HTTP endpoints of the coordinator
+const (
+	configEndpoint    = "/api/distributed/config"
+	leaseEndpoint     = "/api/distributed/lease"
+	heartbeatEndpoint = "/api/distributed/heartbeat"
+	completeEndpoint  = "/api/distributed/complete"
+	resultsEndpoint   = "/api/distributed/results"
+)
+
+// TokenEnv is the environment variable containing the token
+// authenticating workers with the coordinator
+const TokenEnv = "NUCLEI_DISTRIBUTED_TOKEN"
+
+// ErrScanCompleted is returned to workers leasing work units once all units are completed
+var ErrScanCompleted = errors.New("distributed scan completed")
+
+// WorkUnit is a set of targets to scan with a template
+type WorkUnit struct {
+	// ID is the unique id of the unit
+	ID string `json:"id"`
+	// Templates are the paths of the templates to execute
+	Templates []string `json:"templates"`
+	// Targets are the targets to scan
+	Targets []string `json:"targets"`
+	// Attempt is the attempt number of the unit starting from 1
+	Attempt int `json:"attempt"`
+}
+
+// WorkerConfig contains the scan options of the coordinator which
+// workers use to execute work units.
+//
+// Note: RateLimit is applied by each worker, N workers send up to N times
+// the rate limit of the coordinator in total.
+type WorkerConfig struct {
+	TemplateThreads               int                    `json:"template_threads,omitempty"`
+	BulkSize                      int                    `json:"bulk_size,omitempty"`
+	HeadlessTemplateThreads       int                    `json:"headless_template_threads,omitempty"`
+	HeadlessBulkSize              int                    `json:"headless_bulk_size,omitempty"`
+	JsConcurrency                 int                    `json:"js_concurrency,omitempty"`
+	PayloadConcurrency            int                    `json:"payload_concurrency,omitempty"`
+	RateLimit                     int                    `json:"rate_limit,omitempty"`
+	RateLimitDuration             time.Duration          `json:"rate_limit_duration,omitempty"`
+	Timeout                       int                    `json:"timeout,omitempty"`
+	Retries                       int                    `json:"retries,omitempty"`
+	Headless                      bool                   `json:"headless,omitempty"`
+	EnableCodeTemplates           bool                   `json:"code,omitempty"`
+	EnableSelfContainedTemplates  bool                   `json:"self_contained,omitempty"`
+	EnableFileTemplates           bool                   `json:"file,omitempty"`
+	NoInteractsh                  bool                   `json:"no_interactsh,omitempty"`
+	InteractshURL                 string                 `json:"interactsh_url,omitempty"`
+	InteractshToken               string                 `json:"interactsh_token,omitempty"`
+	CustomHeaders                 []string               `json:"headers,omitempty"`
+	Vars                          map[string]interface{} `json:"vars,omitempty"`
+	HttpProxy                     string                 `json:"http_proxy,omitempty"`
+	SocksProxy                    string                 `json:"socks_proxy,omitempty"`
+	LeaseTimeout                  time.Duration          `json:"lease_timeout,omitempty"`
+	DisableUnsignedTemplates      bool                   `json:"disable_unsigned_templates,omitempty"`
+	EnableGlobalMatchersTemplates bool                   `json:"global_matchers,omitempty"`
+}
+
+// NewWorkerConfig returns the worker config from the options of the coordinator
+func NewWorkerConfig(options *types.Options) WorkerConfig {
+	return WorkerConfig{
+		TemplateThreads:               options.TemplateThreads,
+		BulkSize:                      options.BulkSize,
+		HeadlessTemplateThreads:       options.HeadlessTemplateThreads,
+		HeadlessBulkSize:              options.HeadlessBulkSize,
+		JsConcurrency:                 options.JsConcurrency,
+		PayloadConcurrency:            options.PayloadConcurrency,
+		RateLimit:                     options.RateLimit,
+		RateLimitDuration:             options.RateLimitDuration,
+		Timeout:                       options.Timeout,
+		Retries:                       options.Retries,
+		Headless:                      options.Headless,
+		EnableCodeTemplates:           options.EnableCodeTemplates,
+		EnableSelfContainedTemplates:  options.EnableSelfContainedTemplates,
+		EnableFileTemplates:           options.EnableFileTemplates,
+		NoInteractsh:                  options.NoInteractsh,
+		InteractshURL:                 options.InteractshURL,
+		InteractshToken:               options.InteractshToken,
+		CustomHeaders:                 options.CustomHeaders,
+		Vars:                          options.Vars.AsMap(),
+		HttpProxy:                     options.AliveHttpProxy,
+		SocksProxy:                    options.AliveSocksProxy,
+		DisableUnsignedTemplates:      options.DisableUnsignedTemplates,
+		EnableGlobalMatchersTemplates: options.EnableGlobalMatchersTemplates,
+	}
+}
+
+// NewToken returns a new random token authenticating workers with a coordinator
+func NewToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// Shard splits the scan of targets with templates into work units
+// of a single template and up to unitSize targets
+func Shard(templates []string, targets []string, unitSize int) []*WorkUnit {
+	if unitSize <= 0 {
+		unitSize = DefaultUnitSize
+	}
+	var units []*WorkUnit
+	for _, template := range templates {
+		for start := 0; start < len(targets); start += unitSize {
+			end := start + unitSize
+			if end > len(targets) {
+				end = len(targets)
+			}
+			units = append(units, &WorkUnit{
+				ID:        fmt.Sprintf("%d", len(units)+1),
+				Templates: []string{template},
+				Targets:   targets[start:end],
+			})
+		}
+	}
+	return units
+}
+
+// leaseRequest is the request of a worker leasing a work unit
+type leaseRequest struct {
+	Worker string `json:"worker"`
+}
+
+// heartbeatRequest is the request of a worker extending the lease of a work unit
+type heartbeatRequest struct {
+	Worker string `json:"worker"`
+	Unit   string `json:"unit"`
+}
+
+// resultsRequest is the request of a worker sending results of interactions
+// received after the work unit they belong to was completed
+type resultsRequest struct {
+	Worker  string                `json:"worker"`
+	Results []*output.ResultEvent `json:"results"`
+}
+
+// completeRequest is the request of a worker completing a work unit
+type completeRequest struct {
+	Worker  string                `json:"worker"`
+	Unit    string                `json:"unit"`
+	Results []*output.ResultEvent `json:"results,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
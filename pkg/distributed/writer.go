@@ -0,0 +1,44 @@
+package distributed
+
+import (
+	"context"
+
+	"github.com/logrusorgru/aurora"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// resultWriter is an output writer of workers sending results to the coordinator
+type resultWriter struct {
+	client *Client
+}
+
+// NewResultWriter returns an output writer sending the results written
+// outside of work units (ex: late interactsh interactions) to the coordinator
+func NewResultWriter(client *Client) output.Writer {
+	return &resultWriter{client: client}
+}
+
+// Write sends the result to the coordinator
+func (w *resultWriter) Write(event *output.ResultEvent) error {
+	return w.client.Results(context.Background(), []*output.ResultEvent{event})
+}
+
+// Close is a no-op
+func (w *resultWriter) Close() {}
+
+// Colorizer returns a colorizer without colors
+func (w *resultWriter) Colorizer() aurora.Aurora {
+	return aurora.NewAurora(false)
+}
+
+// WriteFailure is a no-op since failures are not reported by workers
+func (w *resultWriter) WriteFailure(*output.InternalWrappedEvent) error {
+	return nil
+}
+
+// Request is a no-op
+func (w *resultWriter) Request(templateID, url, requestType string, err error) {}
+
+// WriteStoreDebugData is a no-op
+func (w *resultWriter) WriteStoreDebugData(host, templateID, eventType string, data string) {}
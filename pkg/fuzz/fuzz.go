@@ -31,7 +31,8 @@ type Rule struct {
 	//   - "body"
 	//   - "cookie"
 	//   - "request"
-	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query,enum=header,enum=path,enum=body,enum=cookie,enum=request"`
+	//   - "message"
+	Part     string `yaml:"part,omitempty" json:"part,omitempty" jsonschema:"title=part of rule,description=Part of request rule to fuzz,enum=query,enum=header,enum=path,enum=body,enum=cookie,enum=request,enum=message"`
 	partType partType
 	// description: |
 	//   Parts is the list of parts to fuzz. If multiple parts need to be
@@ -43,7 +44,8 @@ type Rule struct {
 	//   - "body"
 	//   - "cookie"
 	//   - "request"
-	Parts []string `yaml:"parts,omitempty" json:"parts,omitempty" jsonschema:"title=parts of rule,description=Part of request rule to fuzz,enum=query,enum=header,enum=path,enum=body,enum=cookie,enum=request"`
+	//   - "message"
+	Parts []string `yaml:"parts,omitempty" json:"parts,omitempty" jsonschema:"title=parts of rule,description=Part of request rule to fuzz,enum=query,enum=header,enum=path,enum=body,enum=cookie,enum=request,enum=message"`
 
 	// description: |
 	//   Mode is the mode of fuzzing to perform.
@@ -131,6 +133,7 @@ const (
 	bodyPartType
 	cookiePartType
 	requestPartType
+	messagePartType
 )
 
 var stringToPartType = map[string]partType{
@@ -140,6 +143,7 @@ var stringToPartType = map[string]partType{
 	"body":    bodyPartType,
 	"cookie":  cookiePartType,
 	"request": requestPartType, // request means all request parts
+	"message": messagePartType, // message means protocol messages (ex: websocket)
 }
 
 // modeType is the mode of rule enum declaration
@@ -0,0 +1,166 @@
+package fuzz
+
+import (
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/component"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// MessagePart is the name of the part for fuzzing rules on
+// protocol messages (ex: websocket messages)
+const MessagePart = "message"
+
+// ExecuteMessageInput is the input for rule ExecuteMessage function
+type ExecuteMessageInput struct {
+	// Message is the message to fuzz
+	Message string
+	// Callback is the callback for generated rule messages
+	Callback func(GeneratedMessage) bool
+	// Values contains dynamic values for the rule
+	Values map[string]interface{}
+}
+
+// GeneratedMessage is a single generated message for rule
+type GeneratedMessage struct {
+	// Message is the mutated message
+	Message string
+	// InteractURLs is the list of interactsh urls
+	InteractURLs []string
+	// DynamicValues contains dynamic values map
+	DynamicValues map[string]interface{}
+	// Parameter being fuzzed
+	Parameter string
+	// Value is the value for the parameter
+	Value string
+}
+
+// ExecuteMessage executes a fuzzing rule on the fields of a message
+// decoded using dataformat (json, xml, form, etc) accepting a callback
+// on which generated messages are returned.
+func (rule *Rule) ExecuteMessage(input *ExecuteMessageInput) error {
+	if rule.partType != messagePartType && rule.partType != requestPartType && !sliceutil.Contains(rule.Parts, MessagePart) {
+		return ErrRuleNotApplicable.Msgf("rule part is not %s", MessagePart)
+	}
+	value := component.NewValue(input.Message)
+	parsed := value.Parsed()
+	if parsed.IsNIL() {
+		return ErrRuleNotApplicable.Msgf("could not decode message")
+	}
+	applicable := false
+	parsed.Iterate(func(key string, v any) bool {
+		applicable = rule.matchKeyOrValue(key, types.ToString(v))
+		return !applicable
+	})
+	if !applicable {
+		return ErrRuleNotApplicable.Msgf("no message field matched on this rule")
+	}
+
+	if rule.generator == nil {
+		evaluatedValues, interactURLs := rule.options.Variables.EvaluateWithInteractsh(input.Values, rule.options.Interactsh)
+		values := generators.MergeMaps(evaluatedValues, input.Values, rule.options.Options.Vars.AsMap(), rule.options.Constants)
+		values, interactURLs = rule.evaluateVarsWithInteractsh(values, interactURLs)
+		return rule.executeMessageValues(input, value, values, interactURLs)
+	}
+	iterator := rule.generator.NewIterator()
+	for {
+		payloads, next := iterator.Value()
+		if !next {
+			return nil
+		}
+		evaluatedValues, interactURLs := rule.options.Variables.EvaluateWithInteractsh(generators.MergeMaps(payloads, input.Values), rule.options.Interactsh)
+		values := generators.MergeMaps(payloads, evaluatedValues, input.Values, rule.options.Options.Vars.AsMap(), rule.options.Constants)
+		values, interactURLs = rule.evaluateVarsWithInteractsh(values, interactURLs)
+		if err := rule.executeMessageValues(input, value, values, interactURLs); err != nil {
+			return err
+		}
+	}
+}
+
+// executeMessageValues executes the fuzz payloads of the rule on a decoded message
+func (rule *Rule) executeMessageValues(input *ExecuteMessageInput, original *component.Value, values map[string]interface{}, interactURLs []string) error {
+	ruleInput := &ExecuteRuleInput{Values: values, InteractURLs: interactURLs}
+
+	var keys []string
+	parsed := original.Parsed()
+	parsed.Iterate(func(key string, value any) bool {
+		if rule.matchKeyOrValue(key, types.ToString(value)) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+
+	for _, payload := range rule.Fuzz.Value {
+		value := original.Clone()
+		for _, key := range keys {
+			originalValue := types.ToString(parsed.Get(key))
+
+			var evaluated string
+			evaluated, ruleInput.InteractURLs = rule.executeEvaluate(ruleInput, key, originalValue, payload, ruleInput.InteractURLs)
+			if !value.SetParsedValue(key, evaluated) {
+				// format restrictions, ex: fuzzing string value in a json int field
+				continue
+			}
+			if rule.modeType == singleModeType {
+				if err := rule.execMessage(input, ruleInput, value, key, evaluated); err != nil {
+					return err
+				}
+				value.SetParsedValue(key, originalValue)
+			}
+		}
+		if rule.modeType == multipleModeType {
+			if err := rule.execMessage(input, ruleInput, value, "", ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rule.Fuzz.KV != nil {
+		value := original.Clone()
+		var gotErr error
+		rule.Fuzz.KV.Iterate(func(key, payload string) bool {
+			var evaluated string
+			evaluated, ruleInput.InteractURLs = rule.executeEvaluate(ruleInput, key, "", payload, ruleInput.InteractURLs)
+			current := value.Parsed()
+			originalValue := current.Get(key)
+			value.SetParsedValue(key, evaluated)
+			if rule.modeType == singleModeType {
+				if gotErr = rule.execMessage(input, ruleInput, value, key, evaluated); gotErr != nil {
+					return false
+				}
+				if originalValue != nil {
+					value.SetParsedValue(key, types.ToString(originalValue))
+				} else {
+					value.Delete(key)
+				}
+			}
+			return true
+		})
+		if gotErr != nil {
+			return gotErr
+		}
+		if rule.modeType == multipleModeType {
+			return rule.execMessage(input, ruleInput, value, "", "")
+		}
+	}
+	return nil
+}
+
+// execMessage encodes the mutated message and executes the callback with it
+func (rule *Rule) execMessage(input *ExecuteMessageInput, ruleInput *ExecuteRuleInput, value *component.Value, parameter, parameterValue string) error {
+	encoded, err := value.Encode()
+	if err != nil {
+		return err
+	}
+	message := GeneratedMessage{
+		Message:       encoded,
+		InteractURLs:  ruleInput.InteractURLs,
+		DynamicValues: ruleInput.Values,
+		Parameter:     parameter,
+		Value:         parameterValue,
+	}
+	if !input.Callback(message) {
+		return types.ErrNoMoreRequests
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ const (
 	MultiFormatInputProvider = "MultiFormatInputProvider"
 	ListInputProvider        = "ListInputProvider"
 	SimpleListInputProvider  = "SimpleInputProvider"
+	StreamListInputProvider  = "StreamInputProvider"
 )
 
 // IsErrNotImplemented checks if an error is a not implemented error
@@ -48,6 +49,8 @@ var (
 	_ InputProvider = &http.HttpInputProvider{}
 	// ListInputProvider provides support for simple list of urls or files etc
 	_ InputProvider = &list.ListInputProvider{}
+	// StreamInputProvider provides support for inputs added while scanning
+	_ InputProvider = &StreamInputProvider{}
 )
 
 // InputProvider is unified input provider interface that provides
@@ -70,6 +73,22 @@ type InputProvider interface {
 	Close()
 }
 
+// SpawnedInputStore is implemented by input providers which can dedupe
+// targets spawned during the scan (ex: by pivots) against their inputs
+type SpawnedInputStore interface {
+	// AddSpawned stores a spawned target returning false if it
+	// is an input of the provider or was already spawned
+	AddSpawned(value string) bool
+}
+
+// ObservableInput is implemented by input providers to which inputs
+// are added while they are iterated (ex: streams)
+type ObservableInput interface {
+	// Observe calls init with the number of inputs added so far and
+	// added once for each input added afterwards
+	Observe(init func(count int64), added func())
+}
+
 // InputOptions contains options for input provider
 type InputOptions struct {
 	// Options for global config
@@ -47,6 +47,9 @@ type ListInputProvider struct {
 	excludedHosts     map[string]struct{}
 	hostMapStream     *filekv.FileDB
 	hostMapStreamOnce sync.Once
+	// spawnedMap contains the targets spawned during the scan
+	spawnedMap   *hybrid.HybridMap
+	spawnedMutex sync.Mutex
 	sync.Once
 }
 
@@ -262,6 +265,38 @@ func (i *ListInputProvider) Close() {
 	if i.hostMapStream != nil {
 		i.hostMapStream.Close()
 	}
+	if i.spawnedMap != nil {
+		i.spawnedMap.Close()
+	}
+}
+
+// AddSpawned stores a target spawned during the scan returning
+// false if it is an input or was already spawned
+func (i *ListInputProvider) AddSpawned(value string) bool {
+	metaInput := contextargs.NewMetaInput()
+	metaInput.Input = value
+	key, err := metaInput.MarshalString()
+	if err != nil {
+		return false
+	}
+	if _, ok := i.hostMap.Get(key); ok {
+		return false
+	}
+
+	i.spawnedMutex.Lock()
+	defer i.spawnedMutex.Unlock()
+
+	if i.spawnedMap == nil {
+		if i.spawnedMap, err = hybrid.New(hybrid.DefaultMemoryOptions); err != nil {
+			gologger.Warning().Msgf("Could not create spawned input store: %s\n", err)
+			return false
+		}
+	}
+	if _, ok := i.spawnedMap.Get(key); ok {
+		return false
+	}
+	_ = i.spawnedMap.Set(key, nil)
+	return true
 }
 
 // initializeInputSources initializes the input sources for hmap input
@@ -204,3 +204,16 @@ func Test_expandASNInputValue(t *testing.T) {
 		require.ElementsMatch(t, items, got, "could not get correct ips")
 	}
 }
+
+func Test_addSpawned(t *testing.T) {
+	hm, err := hybrid.New(hybrid.DefaultMemoryOptions)
+	require.Nil(t, err, "could not create input store")
+	input := &ListInputProvider{hostMap: hm, ipOptions: &ipOptions{IPV4: true}}
+	defer input.Close()
+
+	input.Set("https://example.com")
+	require.False(t, input.AddSpawned("https://example.com"), "could spawn input")
+	require.True(t, input.AddSpawned("https://a.example.com"), "could not spawn target")
+	require.False(t, input.AddSpawned("https://a.example.com"), "could spawn target twice")
+	require.Equal(t, int64(1), input.Count(), "spawned target was added to inputs")
+}
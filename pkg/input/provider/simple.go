@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"sync"
+
 	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 )
@@ -9,6 +11,9 @@ import (
 // that acts like a No-Op and returns given list of urls as input
 type SimpleInputProvider struct {
 	Inputs []*contextargs.MetaInput
+
+	spawned      map[string]struct{}
+	spawnedMutex sync.Mutex
 }
 
 // NewSimpleInputProvider creates a new simple input provider
@@ -68,6 +73,27 @@ func (s *SimpleInputProvider) SetWithExclusions(value string) error {
 	return nil
 }
 
+// AddSpawned stores a target spawned during the scan returning
+// false if it is an input or was already spawned
+func (s *SimpleInputProvider) AddSpawned(value string) bool {
+	for _, input := range s.Inputs {
+		if input.Input == value {
+			return false
+		}
+	}
+	s.spawnedMutex.Lock()
+	defer s.spawnedMutex.Unlock()
+
+	if s.spawned == nil {
+		s.spawned = make(map[string]struct{})
+	}
+	if _, ok := s.spawned[value]; ok {
+		return false
+	}
+	s.spawned[value] = struct{}{}
+	return true
+}
+
 // InputType returns the type of input provider
 func (s *SimpleInputProvider) InputType() string {
 	return "SimpleInputProvider"
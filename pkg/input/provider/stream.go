@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/input/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+)
+
+// StreamInputProvider is an input provider to which inputs can be added
+// while it is being iterated.
+//
+// Iteration blocks waiting for new inputs until the provider is closed
+// and can be paused to stop handing out inputs.
+type StreamInputProvider struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	inputs   []*contextargs.MetaInput
+	seen     map[string]struct{}
+	iterated int
+	closed   bool
+	paused   bool
+	added    func()
+}
+
+var _ ObservableInput = &StreamInputProvider{}
+
+// NewStreamInputProvider creates a new stream input provider
+func NewStreamInputProvider() *StreamInputProvider {
+	provider := &StreamInputProvider{seen: make(map[string]struct{})}
+	provider.cond = sync.NewCond(&provider.mu)
+	return provider
+}
+
+// Count returns the number of inputs added to the input provider
+func (s *StreamInputProvider) Count() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.inputs))
+}
+
+// Iterated returns the number of inputs handed out to iterators
+func (s *StreamInputProvider) Iterated() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(s.iterated)
+}
+
+// Iterate over all inputs in order waiting for new inputs until the provider is closed
+func (s *StreamInputProvider) Iterate(callback func(value *contextargs.MetaInput) bool) {
+	for index := 0; ; index++ {
+		s.mu.Lock()
+		for s.paused || (index >= len(s.inputs) && !s.closed) {
+			s.cond.Wait()
+		}
+		if index >= len(s.inputs) {
+			s.mu.Unlock()
+			return
+		}
+		input := s.inputs[index]
+		if index >= s.iterated {
+			s.iterated = index + 1
+		}
+		s.mu.Unlock()
+
+		if !callback(input) {
+			return
+		}
+	}
+}
+
+// Set adds an item to the input provider if it was not already added
+func (s *StreamInputProvider) Set(value string) {
+	_ = s.add(value)
+}
+
+// SetWithProbe adds an item to the input provider with HTTP probing
+func (s *StreamInputProvider) SetWithProbe(value string, probe types.InputLivenessProbe) error {
+	probedValue, err := probe.ProbeURL(value)
+	if err != nil {
+		return err
+	}
+	return s.add(probedValue)
+}
+
+// SetWithExclusions adds an item to the input provider
+func (s *StreamInputProvider) SetWithExclusions(value string) error {
+	return s.add(value)
+}
+
+// add adds an input to the provider and wakes up waiting iterators
+func (s *StreamInputProvider) add(value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrInactiveInput
+	}
+	if _, ok := s.seen[value]; ok {
+		return nil
+	}
+	s.seen[value] = struct{}{}
+
+	metaInput := contextargs.NewMetaInput()
+	metaInput.Input = value
+	s.inputs = append(s.inputs, metaInput)
+	if s.added != nil {
+		s.added()
+	}
+	s.cond.Broadcast()
+	return nil
+}
+
+// Observe calls init with the number of inputs added so far and added once
+// for each input added afterwards, both are called with the provider locked
+// so that no input is missed or counted twice
+func (s *StreamInputProvider) Observe(init func(count int64), added func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	init(int64(len(s.inputs)))
+	s.added = added
+}
+
+// Pause stops handing out inputs to iterators until resumed
+func (s *StreamInputProvider) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = true
+}
+
+// Resume resumes handing out inputs to iterators
+func (s *StreamInputProvider) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = false
+	s.cond.Broadcast()
+}
+
+// Paused returns true if the input provider is paused
+func (s *StreamInputProvider) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused
+}
+
+// Closed returns true if no more inputs can be added to the input provider
+func (s *StreamInputProvider) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// InputType returns the type of input provider
+func (s *StreamInputProvider) InputType() string {
+	return StreamListInputProvider
+}
+
+// Close stops accepting new inputs, iterators return
+// once all the added inputs have been handed out
+func (s *StreamInputProvider) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.cond.Broadcast()
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamInputProvider(t *testing.T) {
+	stream := NewStreamInputProvider()
+	stream.Set("scanme.sh")
+
+	var mu sync.Mutex
+	var got []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stream.Iterate(func(value *contextargs.MetaInput) bool {
+			mu.Lock()
+			got = append(got, value.Input)
+			mu.Unlock()
+			return true
+		})
+	}()
+	received := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got)
+	}
+	require.Eventually(t, func() bool { return received() == 1 }, time.Second, 10*time.Millisecond, "could not iterate initial input")
+
+	stream.Pause()
+	stream.Set("honey.scanme.sh")
+	stream.Set("scanme.sh")
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 1, received(), "could iterate input while paused")
+	require.Equal(t, int64(2), stream.Count(), "could not dedupe inputs")
+
+	stream.Resume()
+	require.Eventually(t, func() bool { return received() == 2 }, time.Second, 10*time.Millisecond, "could not iterate input after resume")
+
+	stream.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("iterate did not return after close")
+	}
+	require.ErrorIs(t, stream.SetWithExclusions("example.com"), ErrInactiveInput, "could add input after close")
+	require.Equal(t, []string{"scanme.sh", "honey.scanme.sh"}, got, "could not get inputs in order")
+	require.Equal(t, int64(2), stream.Iterated(), "could not get iterated inputs")
+}
+
+func TestStreamInputProviderObserve(t *testing.T) {
+	stream := NewStreamInputProvider()
+	stream.Set("scanme.sh")
+
+	var initial, added int64
+	stream.Observe(func(count int64) { initial = count }, func() { added++ })
+	stream.Set("honey.scanme.sh")
+	stream.Set("scanme.sh")
+	stream.Set("example.com")
+
+	require.Equal(t, int64(1), initial, "could not get inputs added before observing")
+	require.Equal(t, int64(2), added, "could not observe added inputs")
+}
@@ -0,0 +1,174 @@
+package installer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// BundleLockfileName is the name of the signed lockfile at the root of template bundles
+const BundleLockfileName = "nuclei-templates.lock"
+
+// maxBundleFileSize is the maximum size of a file extracted from a bundle
+const maxBundleFileSize = 100 << 20
+
+// ExportBundle writes the files of the lockfile with the lockfile signed
+// by signer to a gzipped tar bundle at path
+func ExportBundle(path string, lockfile *Lockfile, templateSigner *signer.TemplateSigner) error {
+	if templateSigner == nil {
+		return errorutil.New("a signer is required to export a bundle")
+	}
+	// imports of templates reference files by their original path which
+	// would not resolve to the copy of files outside of the templates directory
+	for _, locked := range lockfile.Templates {
+		if isExternalBundlePath(locked.Path) {
+			return errorutil.New("%s is outside of the templates directory and can not be bundled", locked.file)
+		}
+	}
+	lockfileData, err := lockfile.Marshal(templateSigner)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not create bundle %s", path)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	// the lockfile is written first so it is verified before any other file is extracted
+	if err := writeBundleFile(tarWriter, BundleLockfileName, lockfileData); err != nil {
+		return err
+	}
+	for _, locked := range lockfile.Templates {
+		data, err := os.ReadFile(locked.file)
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("could not read %s", locked.file)
+		}
+		if hashContent(data) != locked.SHA256 {
+			return errorutil.New("%s was modified while exporting bundle", locked.file)
+		}
+		if err := writeBundleFile(tarWriter, locked.Path, data); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// ImportBundle extracts the bundle at path to dir verifying the signature of its
+// lockfile with verifiers and the content of every file against the lockfile
+func ImportBundle(path, dir string, verifiers []*signer.TemplateSigner) (*Lockfile, error) {
+	if len(verifiers) == 0 {
+		return nil, errorutil.New("no verifiers available to verify bundle signature")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not open bundle %s", path)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read bundle %s", path)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	header, err := tarReader.Next()
+	if err != nil || header.Name != BundleLockfileName {
+		return nil, errorutil.New("bundle %s does not start with a lockfile", path)
+	}
+	lockfileData, err := readBundleFile(tarReader, header)
+	if err != nil {
+		return nil, err
+	}
+	lockfile, err := ParseLockfile(lockfileData, verifiers...)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]*LockedTemplate, len(lockfile.Templates))
+	for _, template := range lockfile.Templates {
+		locked[template.Path] = template
+	}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not read bundle %s", path)
+		}
+		template, ok := locked[header.Name]
+		if !ok || template.file != "" {
+			return nil, errorutil.New("bundle file %s is not locked", header.Name)
+		}
+		if isExternalBundlePath(template.Path) {
+			return nil, errorutil.New("bundle file %s is outside of the templates directory", header.Name)
+		}
+		data, err := readBundleFile(tarReader, header)
+		if err != nil {
+			return nil, err
+		}
+		if hashContent(data) != template.SHA256 {
+			return nil, errorutil.New("bundle file %s does not match locked hash", header.Name)
+		}
+
+		// paths of the lockfile are validated to stay within dir
+		target := filepath.Join(dir, filepath.FromSlash(template.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not write %s", target)
+		}
+		template.file = target
+	}
+	for _, template := range lockfile.Templates {
+		if template.file == "" {
+			return nil, errorutil.New("locked file %s is missing from bundle", template.Path)
+		}
+	}
+	return lockfile, nil
+}
+
+func writeBundleFile(tarWriter *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not write %s to bundle", name)
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not write %s to bundle", name)
+	}
+	return nil
+}
+
+func readBundleFile(tarReader *tar.Reader, header *tar.Header) ([]byte, error) {
+	if header.Typeflag != tar.TypeReg {
+		return nil, errorutil.New("bundle entry %s is not a regular file", header.Name)
+	}
+	if header.Size > maxBundleFileSize {
+		return nil, errorutil.New("bundle file %s is too large", header.Name)
+	}
+	data, err := io.ReadAll(io.LimitReader(tarReader, maxBundleFileSize))
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read %s from bundle", header.Name)
+	}
+	return data, nil
+}
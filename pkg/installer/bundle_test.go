@@ -0,0 +1,152 @@
+package installer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
+)
+
+const (
+	testCertFile = "../../integration_tests/protocols/keys/ci.crt"
+	testKeyFile  = "../../integration_tests/protocols/keys/ci-private-key.pem"
+)
+
+const testBundleTemplate = `id: bundle-test
+
+info:
+  name: Bundle Test
+  author: pdteam
+  severity: info
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}"
+`
+
+// setupBundleTemplates writes a template and a payload to a temporary templates directory
+func setupBundleTemplates(t *testing.T) (string, []string) {
+	previous := config.DefaultConfig.TemplatesDirectory
+	t.Cleanup(func() { config.DefaultConfig.SetTemplatesDir(previous) })
+
+	dir := t.TempDir()
+	config.DefaultConfig.SetTemplatesDir(dir)
+	files := []string{filepath.Join(dir, "http", "bundle-test.yaml"), filepath.Join(dir, "http", "payloads.txt")}
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "http"), 0755))
+	require.Nil(t, os.WriteFile(files[0], []byte(testBundleTemplate), 0644))
+	require.Nil(t, os.WriteFile(files[1], []byte("admin\nroot\n"), 0644))
+	return dir, files
+}
+
+func TestBundleExportImport(t *testing.T) {
+	_, files := setupBundleTemplates(t)
+	templateSigner, err := signer.NewTemplateSignerFromFiles(testCertFile, testKeyFile)
+	require.Nil(t, err, "could not create signer")
+
+	lockfile, err := NewLockfile(files)
+	require.Nil(t, err, "could not create lockfile")
+	require.Len(t, lockfile.Templates, 2)
+	require.Equal(t, "http/bundle-test.yaml", lockfile.Templates[0].Path)
+	require.Equal(t, "bundle-test", lockfile.Templates[0].ID)
+	require.Equal(t, "", lockfile.Templates[1].ID)
+
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.Nil(t, ExportBundle(bundle, lockfile, templateSigner), "could not export bundle")
+
+	dir := t.TempDir()
+	imported, err := ImportBundle(bundle, dir, []*signer.TemplateSigner{templateSigner})
+	require.Nil(t, err, "could not import bundle")
+	require.Equal(t, lockfile.Templates[0].SHA256, imported.Templates[0].SHA256)
+	data, err := os.ReadFile(filepath.Join(dir, "http", "bundle-test.yaml"))
+	require.Nil(t, err, "could not read imported template")
+	require.Equal(t, testBundleTemplate, string(data))
+
+	// bundles are rejected by verifiers of other certificates
+	_, err = ImportBundle(bundle, t.TempDir(), signer.DefaultTemplateVerifiers)
+	require.NotNil(t, err, "could import bundle with untrusted signature")
+}
+
+func TestBundleExportExternalFile(t *testing.T) {
+	_, files := setupBundleTemplates(t)
+	templateSigner, err := signer.NewTemplateSignerFromFiles(testCertFile, testKeyFile)
+	require.Nil(t, err, "could not create signer")
+
+	external := filepath.Join(t.TempDir(), "external.txt")
+	require.Nil(t, os.WriteFile(external, []byte("admin\n"), 0644))
+	lockfile, err := NewLockfile(append(files, external))
+	require.Nil(t, err, "could not create lockfile")
+
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NotNil(t, ExportBundle(bundle, lockfile, templateSigner), "could export file outside of templates directory")
+}
+
+func TestBundleImportTampered(t *testing.T) {
+	_, files := setupBundleTemplates(t)
+	templateSigner, err := signer.NewTemplateSignerFromFiles(testCertFile, testKeyFile)
+	require.Nil(t, err, "could not create signer")
+	lockfile, err := NewLockfile(files)
+	require.Nil(t, err, "could not create lockfile")
+	lockfileData, err := lockfile.Marshal(templateSigner)
+	require.Nil(t, err, "could not marshal lockfile")
+
+	writeBundle := func(entries map[string]string) string {
+		path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+		file, err := os.Create(path)
+		require.Nil(t, err)
+		defer file.Close()
+		gzipWriter := gzip.NewWriter(file)
+		tarWriter := tar.NewWriter(gzipWriter)
+		require.Nil(t, writeBundleFile(tarWriter, BundleLockfileName, lockfileData))
+		for _, name := range []string{"http/bundle-test.yaml", "http/payloads.txt", "../escape.yaml"} {
+			if content, ok := entries[name]; ok {
+				require.Nil(t, writeBundleFile(tarWriter, name, []byte(content)))
+			}
+		}
+		require.Nil(t, tarWriter.Close())
+		require.Nil(t, gzipWriter.Close())
+		return path
+	}
+	verifiers := []*signer.TemplateSigner{templateSigner}
+
+	_, err = ImportBundle(writeBundle(map[string]string{"http/bundle-test.yaml": testBundleTemplate + "\n", "http/payloads.txt": "admin\nroot\n"}), t.TempDir(), verifiers)
+	require.NotNil(t, err, "could import modified template")
+
+	_, err = ImportBundle(writeBundle(map[string]string{"http/bundle-test.yaml": testBundleTemplate}), t.TempDir(), verifiers)
+	require.NotNil(t, err, "could import bundle with missing file")
+
+	_, err = ImportBundle(writeBundle(map[string]string{"http/bundle-test.yaml": testBundleTemplate, "http/payloads.txt": "admin\nroot\n", "../escape.yaml": "id: escape"}), t.TempDir(), verifiers)
+	require.NotNil(t, err, "could import file outside of lockfile")
+
+	// lockfiles with modified content do not verify
+	tampered := append([]byte("# comment\n"), lockfileData...)
+	_, err = ParseLockfile(tampered, verifiers...)
+	require.NotNil(t, err, "could parse tampered lockfile")
+}
+
+func TestLockfileVerify(t *testing.T) {
+	dir, files := setupBundleTemplates(t)
+	lockfile, err := NewLockfile(files)
+	require.Nil(t, err, "could not create lockfile")
+	data, err := lockfile.Marshal(nil)
+	require.Nil(t, err, "could not marshal lockfile")
+	parsed, err := ParseLockfile(data)
+	require.Nil(t, err, "could not parse lockfile")
+	require.Nil(t, parsed.Verify(files), "could not verify unchanged files")
+
+	require.Nil(t, os.WriteFile(files[0], []byte(testBundleTemplate+"\n"), 0644))
+	require.NotNil(t, parsed.Verify(files), "could verify modified template")
+
+	other := filepath.Join(dir, "http", "other.yaml")
+	require.Nil(t, os.WriteFile(other, []byte("id: other\n"), 0644))
+	require.NotNil(t, parsed.Verify([]string{other}), "could verify unlocked template")
+
+	_, err = ParseLockfile([]byte("version: 1\ntemplates:\n  - path: ../../etc/passwd\n    sha256: x\n"))
+	require.NotNil(t, err, "could parse lockfile with path outside of directory")
+}
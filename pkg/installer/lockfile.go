@@ -0,0 +1,260 @@
+package installer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates/signer"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+const (
+	// LockfileVersion is the version of the lockfile format
+	LockfileVersion = 1
+	// externalBundleDir is the directory of bundles files outside of the templates directory are stored in
+	externalBundleDir = "external"
+)
+
+// Sources of locked templates
+const (
+	SourceOfficial = "official"
+	SourceGitHub   = "github"
+	SourceGitLab   = "gitlab"
+	SourceS3       = "s3"
+	SourceAzure    = "azure"
+	SourceLocal    = "local"
+)
+
+// Lockfile pins the templates of a scan to their content hashes and versions
+type Lockfile struct {
+	// Version is the version of the lockfile format
+	Version int `yaml:"version"`
+	// NucleiVersion is the version of nuclei which created the lockfile
+	NucleiVersion string `yaml:"nuclei-version"`
+	// TemplatesVersion is the version of the official nuclei-templates
+	TemplatesVersion string `yaml:"templates-version,omitempty"`
+	// Templates are the locked templates and the files they reference
+	Templates []*LockedTemplate `yaml:"templates"`
+}
+
+// LockedTemplate is a template or a file referenced by templates pinned to its content hash
+type LockedTemplate struct {
+	// ID is the id of the template, empty for other files
+	ID string `yaml:"id,omitempty"`
+	// Path is the path of the file relative to the templates directory
+	Path string `yaml:"path"`
+	// SHA256 is the hex encoded sha256 hash of the content of the file
+	SHA256 string `yaml:"sha256"`
+	// Source is the source the file was installed from
+	Source string `yaml:"source"`
+	// Version is the release version of official templates
+	Version string `yaml:"version,omitempty"`
+
+	// file is the path of the file on disk
+	file string
+}
+
+// NewLockfile creates a lockfile of the files at the given paths
+// which are stored relative to the templates directory
+func NewLockfile(files []string) (*Lockfile, error) {
+	lockfile := &Lockfile{
+		Version:          LockfileVersion,
+		NucleiVersion:    config.Version,
+		TemplatesVersion: config.DefaultConfig.TemplateVersion,
+	}
+	seen := make(map[string]struct{})
+	for _, file := range files {
+		absPath, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[absPath]; ok {
+			continue
+		}
+		seen[absPath] = struct{}{}
+
+		data, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not read %s", file)
+		}
+		locked := &LockedTemplate{
+			Path:   bundlePath(absPath),
+			SHA256: hashContent(data),
+			Source: templateSource(absPath),
+			file:   absPath,
+		}
+		if config.IsTemplate(absPath) {
+			locked.ID, _ = config.GetTemplateIDFromReader(bytes.NewReader(data), absPath)
+		}
+		if locked.Source == SourceOfficial {
+			locked.Version = lockfile.TemplatesVersion
+		}
+		lockfile.Templates = append(lockfile.Templates, locked)
+	}
+	sort.Slice(lockfile.Templates, func(i, j int) bool {
+		return lockfile.Templates[i].Path < lockfile.Templates[j].Path
+	})
+	return lockfile, nil
+}
+
+// ReadLockfile reads and parses a lockfile verifying its signature if verifiers are given
+func ReadLockfile(path string, verifiers ...*signer.TemplateSigner) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLockfile(data, verifiers...)
+}
+
+// ParseLockfile parses a lockfile verifying its signature if verifiers are given
+func ParseLockfile(data []byte, verifiers ...*signer.TemplateSigner) (*Lockfile, error) {
+	if len(verifiers) > 0 && !verifyLockfile(data, verifiers) {
+		return nil, errorutil.New("lockfile signature could not be verified")
+	}
+	_, content := signer.ExtractSignatureAndContent(data)
+	lockfile := &Lockfile{}
+	if err := yaml.Unmarshal(content, lockfile); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse lockfile")
+	}
+	if lockfile.Version != LockfileVersion {
+		return nil, errorutil.New("unsupported lockfile version %d", lockfile.Version)
+	}
+	for _, locked := range lockfile.Templates {
+		if !isValidBundlePath(locked.Path) {
+			return nil, errorutil.New("invalid path %s in lockfile", locked.Path)
+		}
+	}
+	return lockfile, nil
+}
+
+// Marshal returns the lockfile signed with signer if given
+func (l *Lockfile) Marshal(templateSigner *signer.TemplateSigner) ([]byte, error) {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	if templateSigner == nil {
+		return data, nil
+	}
+	signature, err := templateSigner.Sign(data, lockfileSignable{})
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not sign lockfile")
+	}
+	_, content := signer.ExtractSignatureAndContent(data)
+	return []byte(string(content) + "\n" + signature + "\n"), nil
+}
+
+// Write writes the lockfile to path signed with signer if given
+func (l *Lockfile) Write(path string, templateSigner *signer.TemplateSigner) error {
+	data, err := l.Marshal(templateSigner)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, checkSumFilePerm)
+}
+
+// Verify verifies that the given files are locked with the same content hashes.
+// Templates are matched by id and other files by path relative to the templates directory.
+func (l *Lockfile) Verify(files []string) error {
+	byID := make(map[string]*LockedTemplate)
+	byPath := make(map[string]*LockedTemplate)
+	for _, locked := range l.Templates {
+		if locked.ID != "" {
+			byID[locked.ID] = locked
+		}
+		byPath[locked.Path] = locked
+	}
+
+	var errs []string
+	for _, file := range files {
+		current, err := NewLockfile([]string{file})
+		if err != nil {
+			return err
+		}
+		for _, template := range current.Templates {
+			locked, ok := byID[template.ID]
+			if template.ID == "" || !ok {
+				locked, ok = byPath[template.Path]
+			}
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Sprintf("%s is not locked", file))
+			case locked.SHA256 != template.SHA256:
+				errs = append(errs, fmt.Sprintf("%s does not match locked hash %s", file, locked.SHA256))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errorutil.New("templates do not match lockfile:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// verifyLockfile returns true if the lockfile is signed by any of the verifiers
+func verifyLockfile(data []byte, verifiers []*signer.TemplateSigner) bool {
+	for _, verifier := range verifiers {
+		if verified, err := verifier.Verify(data, lockfileSignable{}); err == nil && verified {
+			return true
+		}
+	}
+	return false
+}
+
+// lockfileSignable is a lockfile signed with the template signer
+type lockfileSignable struct{}
+
+func (lockfileSignable) GetFileImports() []string { return nil }
+
+func (lockfileSignable) HasCodeProtocol() bool { return false }
+
+// bundlePath returns the path of a file relative to the templates directory
+// or under the external directory for files outside of it
+func bundlePath(absPath string) string {
+	if relPath, err := filepath.Rel(config.DefaultConfig.TemplatesDirectory, absPath); err == nil && !strings.HasPrefix(relPath, "..") {
+		return filepath.ToSlash(relPath)
+	}
+	return filepath.ToSlash(filepath.Join(externalBundleDir, strings.TrimPrefix(filepath.ToSlash(filepath.Clean(absPath)), filepath.VolumeName(absPath))))
+}
+
+// isExternalBundlePath returns true if a path of a lockfile is a file outside of the templates directory
+func isExternalBundlePath(path string) bool {
+	return strings.HasPrefix(path, externalBundleDir+"/")
+}
+
+// isValidBundlePath returns true if a path of a lockfile stays within the templates directory
+func isValidBundlePath(path string) bool {
+	cleaned := filepath.Clean(filepath.FromSlash(path))
+	return path != "" && !filepath.IsAbs(cleaned) && cleaned != ".." && !strings.HasPrefix(cleaned, ".."+string(filepath.Separator))
+}
+
+// templateSource returns the source a file was installed from
+func templateSource(absPath string) string {
+	cfg := config.DefaultConfig
+	switch {
+	case stringsutil.HasPrefixAny(absPath, cfg.CustomGitHubTemplatesDirectory):
+		return SourceGitHub
+	case stringsutil.HasPrefixAny(absPath, cfg.CustomGitLabTemplatesDirectory):
+		return SourceGitLab
+	case stringsutil.HasPrefixAny(absPath, cfg.CustomS3TemplatesDirectory):
+		return SourceS3
+	case stringsutil.HasPrefixAny(absPath, cfg.CustomAzureTemplatesDirectory):
+		return SourceAzure
+	case stringsutil.HasPrefixAny(absPath, cfg.TemplatesDirectory+string(filepath.Separator)):
+		return SourceOfficial
+	}
+	return SourceLocal
+}
+
+// hashContent returns the hex encoded sha256 hash of data
+func hashContent(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
@@ -2,8 +2,10 @@ package compiler
 
 import (
 	"sync"
+	"time"
 
 	"github.com/dop251/goja"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	syncutil "github.com/projectdiscovery/utils/sync"
 )
 
@@ -16,8 +18,11 @@ var (
 
 func executeWithoutPooling(p *goja.Program, args *ExecuteArgs, opts *ExecuteOptions) (result goja.Value, err error) {
 	lazyFixedSgInit()
+	waitStart := time.Now()
 	ephemeraljsc.Add()
 	defer ephemeraljsc.Done()
+	telemetry.AcquireJSRuntime("ephemeral", time.Since(waitStart))
+	defer telemetry.ReleaseJSRuntime("ephemeral")
 	runtime := createNewRuntime()
 	return executeWithRuntime(runtime, p, args, opts)
 }
@@ -105,6 +105,11 @@ func (severityHolder *Holder) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &marshalledSeverity); err != nil {
 		return err
 	}
+	// undefined severities are marshalled as empty strings
+	if marshalledSeverity == "" {
+		severityHolder.Severity = Undefined
+		return nil
+	}
 
 	computedSeverity, err := toSeverity(marshalledSeverity)
 	if err != nil {
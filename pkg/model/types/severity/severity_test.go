@@ -1,6 +1,7 @@
 package severity
 
 import (
+	"encoding/json"
 	"testing"
 
 	"gopkg.in/yaml.v2"
@@ -83,3 +84,12 @@ func TestSeveritiesMarshalYaml(t *testing.T) {
 	}
 	require.Equal(t, "- low\n- medium\n", string(marshalled), "could not marshal yaml")
 }
+
+func TestUndefinedJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(Holder{})
+	require.Nil(t, err, "could not marshal json")
+
+	var holder Holder
+	require.Nil(t, json.Unmarshal(data, &holder), "could not unmarshal undefined severity")
+	require.Equal(t, Undefined, holder.Severity)
+}
@@ -50,6 +50,15 @@ func (e *Extractor) CompileExtractors() error {
 		e.dslCompiled = append(e.dslCompiled, compiled)
 	}
 
+	if e.GetType() == EntropyExtractor {
+		if e.Entropy < 0 {
+			return fmt.Errorf("invalid entropy specified: %v", e.Entropy)
+		}
+		if e.MinLength <= 0 {
+			e.MinLength = defaultEntropyMinLength
+		}
+	}
+
 	if e.CaseInsensitive {
 		if e.GetType() != KValExtractor {
 			return fmt.Errorf("case-insensitive flag is supported only for 'kval' extractors (not '%s')", e.Type)
@@ -3,6 +3,8 @@ package extractors
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
 	"strings"
 
 	"github.com/antchfx/htmlquery"
@@ -11,6 +13,21 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
+const (
+	// defaultEntropy is the default minimum entropy of the tokens extracted by entropy extractors
+	defaultEntropy = 4.5
+	// defaultHexEntropy is the default minimum entropy of hex tokens, hex having a smaller alphabet
+	defaultHexEntropy = 3.0
+	// defaultEntropyMinLength is the default minimum length of the tokens extracted by entropy extractors
+	defaultEntropyMinLength = 20
+)
+
+var (
+	// entropyTokenRegex matches runs of base64, base64url and hex characters
+	entropyTokenRegex = regexp.MustCompile(`[A-Za-z0-9+/_\-]+={0,2}`)
+	hexTokenRegex     = regexp.MustCompile(`^[A-Fa-f0-9]+$`)
+)
+
 // ExtractRegex extracts text from a corpus and returns it
 func (e *Extractor) ExtractRegex(corpus string) map[string]struct{} {
 	results := make(map[string]struct{})
@@ -184,3 +201,63 @@ func (e *Extractor) ExtractDSL(data map[string]interface{}) map[string]struct{}
 	}
 	return results
 }
+
+// ExtractEntropy extracts the tokens of the corpus having a high shannon entropy
+func (e *Extractor) ExtractEntropy(corpus string) map[string]struct{} {
+	results := make(map[string]struct{})
+
+	var candidates []string
+	if len(e.regexCompiled) > 0 {
+		candidates = e.regexCandidates(corpus)
+	} else {
+		candidates = entropyTokenRegex.FindAllString(corpus, -1)
+	}
+	for _, candidate := range candidates {
+		if len(candidate) < e.MinLength {
+			continue
+		}
+		threshold := e.Entropy
+		if threshold == 0 {
+			threshold = defaultEntropy
+			if hexTokenRegex.MatchString(candidate) {
+				threshold = defaultHexEntropy
+			}
+		}
+		if ShannonEntropy(candidate) >= threshold {
+			results[candidate] = struct{}{}
+		}
+	}
+	return results
+}
+
+// regexCandidates returns the regex group matched by the regexes in the corpus
+func (e *Extractor) regexCandidates(corpus string) []string {
+	var candidates []string
+	for _, regex := range e.regexCompiled {
+		for _, match := range regex.FindAllStringSubmatch(corpus, -1) {
+			if len(match) > e.RegexGroup {
+				candidates = append(candidates, match[e.RegexGroup])
+			}
+		}
+	}
+	return candidates
+}
+
+// ShannonEntropy returns the shannon entropy of the value in bits per character
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	frequencies := make(map[rune]int)
+	var length int
+	for _, char := range value {
+		frequencies[char]++
+		length++
+	}
+	var entropy float64
+	for _, count := range frequencies {
+		probability := float64(count) / float64(length)
+		entropy -= probability * math.Log2(probability)
+	}
+	return entropy
+}
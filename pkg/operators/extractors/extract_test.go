@@ -88,3 +88,26 @@ func TestExtractor_ExtractDSL(t *testing.T) {
 	got = e.ExtractDSL(map[string]interface{}{"hi": "hello"})
 	require.Equal(t, map[string]struct{}{}, got)
 }
+
+func TestExtractor_ExtractEntropy(t *testing.T) {
+	e := &Extractor{Type: ExtractorTypeHolder{ExtractorType: EntropyExtractor}}
+	err := e.CompileExtractors()
+	require.Nil(t, err)
+
+	corpus := `aws_secret_access_key = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+commit = 3f786850e387550fdab836ed7e6dc881de23001b
+placeholder = aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+function = getConfigurationValueFromEnvironment`
+	got := e.ExtractEntropy(corpus)
+	require.Equal(t, map[string]struct{}{
+		"wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY": {},
+		"3f786850e387550fdab836ed7e6dc881de23001b": {},
+	}, got)
+
+	e = &Extractor{Type: ExtractorTypeHolder{ExtractorType: EntropyExtractor}, Regex: []string{`token=([^&\s]+)`}, RegexGroup: 1, Entropy: 3.5, MinLength: 8}
+	err = e.CompileExtractors()
+	require.Nil(t, err)
+
+	got = e.ExtractEntropy("token=Zq8x2LpW9vKt&token=password&id=Zq8x2LpW9vKt")
+	require.Equal(t, map[string]struct{}{"Zq8x2LpW9vKt": {}}, got)
+}
@@ -93,6 +93,24 @@ type Extractor struct {
 	DSL         []string `yaml:"dsl,omitempty" json:"dsl,omitempty" jsonschema:"title=dsl expressions to extract,description=Optional attribute to extract from response dsl"`
 	dslCompiled []*govaluate.EvaluableExpression
 
+	// description: |
+	//   Entropy is the minimum shannon entropy (bits per character) of the tokens
+	//   extracted by the entropy extractor.
+	//
+	//   Tokens are found using the regex patterns if any or as runs of base64/hex
+	//   characters otherwise. By default, hex tokens require an entropy of 3.0
+	//   and other tokens an entropy of 4.5.
+	// examples:
+	//   - value: "4.2"
+	Entropy float64 `yaml:"entropy,omitempty" json:"entropy,omitempty" jsonschema:"title=minimum entropy of extracted tokens,description=Minimum shannon entropy of the tokens extracted by the entropy extractor"`
+	// description: |
+	//   MinLength is the minimum length of the tokens extracted by the entropy extractor.
+	//
+	//   Default is 20.
+	// examples:
+	//   - value: "32"
+	MinLength int `yaml:"min-length,omitempty" json:"min-length,omitempty" jsonschema:"title=minimum length of extracted tokens,description=Minimum length of the tokens extracted by the entropy extractor"`
+
 	// description: |
 	//   Part is the part of the request response to extract data from.
 	//
@@ -51,6 +51,14 @@ func (w *StandardWriter) formatScreen(output *ResultEvent) []byte {
 		builder.WriteString("[")
 		builder.WriteString(w.severityColors(output.Info.SeverityHolder.Severity))
 		builder.WriteString("] ")
+
+		if output.Risk != nil {
+			builder.WriteString("[risk:")
+			builder.WriteString(strconv.FormatFloat(output.Risk.Score, 'f', 1, 64))
+			builder.WriteString(" ")
+			builder.WriteString(w.severityColors(output.Risk.Priority.Severity))
+			builder.WriteString("] ")
+		}
 	}
 	if output.Matched != "" {
 		builder.WriteString(output.Matched)
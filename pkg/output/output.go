@@ -188,6 +188,9 @@ type ResultEvent struct {
 	// of another template's result event
 	GlobalMatchers bool `json:"global-matchers,omitempty"`
 
+	// Risk is the risk of the result computed from the asset context
+	Risk *Risk `json:"risk,omitempty"`
+
 	// IssueTrackers is the metadata for issue trackers
 	IssueTrackers map[string]IssueTrackerMetadata `json:"issue_trackers,omitempty"`
 	// ReqURLPattern when enabled contains base URL pattern that was used to generate the request
@@ -208,6 +211,23 @@ type ResultEvent struct {
 	Error               string         `json:"error,omitempty"`
 }
 
+// Risk is the risk of a result computed from the template
+// classification and the context of the matched asset.
+type Risk struct {
+	// Score is the risk score of the result between 0 and 10
+	Score float64 `json:"score"`
+	// Priority is the adjusted priority of the result derived from the score
+	Priority severity.Holder `json:"priority"`
+	// Environment is the environment of the matched asset
+	Environment string `json:"environment,omitempty"`
+	// Exposure is the exposure of the matched asset
+	Exposure string `json:"exposure,omitempty"`
+	// Criticality is the business criticality of the matched asset
+	Criticality string `json:"criticality,omitempty"`
+	// AssetTags are the tags of the matched asset
+	AssetTags []string `json:"asset-tags,omitempty"`
+}
+
 type IssueTrackerMetadata struct {
 	// IssueID is the ID of the issue created
 	IssueID string `json:"id,omitempty"`
@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/redact"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/risk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/suppress"
+)
+
+// Pipeline processes results before they are written to the output
+// and reported to exporters and issue trackers.
+//
+// Results are processed in a fixed order: suppressed results are dropped
+// first so suppressions match the raw evidence, then the remaining results
+// are redacted and scored.
+type Pipeline struct {
+	redactor   *redact.Redactor
+	suppressor *suppress.Suppressor
+	scorer     *risk.Scorer
+}
+
+// New returns a pipeline processing results with the given steps or nil if
+// there is nothing to process, nil steps are skipped
+func New(redactor *redact.Redactor, suppressor *suppress.Suppressor, scorer *risk.Scorer) *Pipeline {
+	if redactor == nil && suppressor == nil && scorer == nil {
+		return nil
+	}
+	return &Pipeline{redactor: redactor, suppressor: suppressor, scorer: scorer}
+}
+
+// Apply returns the writer and the client wrapped to process results with
+// the pipeline. Suppressed results are only counted by the output writer.
+func (p *Pipeline) Apply(writer output.Writer, client reporting.Client) (output.Writer, reporting.Client) {
+	if p == nil {
+		return writer, client
+	}
+	if writer != nil {
+		writer = &Writer{Writer: writer, pipeline: p}
+	}
+	if client != nil {
+		client = &ReportingClient{Client: client, pipeline: p}
+	}
+	return writer, client
+}
+
+// Process returns the processed copy of the event or nil if it is suppressed,
+// suppressed results are counted if count is true
+func (p *Pipeline) Process(event *output.ResultEvent, count bool) *output.ResultEvent {
+	if count && p.suppressor.Suppress(event) {
+		return nil
+	}
+	if !count && p.suppressor.Suppression(event) != nil {
+		return nil
+	}
+	event = p.redactor.Event(event)
+	p.scorer.Apply(event)
+	return event
+}
+
+// Writer is an output writer passing processed results to the underlying writer.
+type Writer struct {
+	output.Writer
+	pipeline *Pipeline
+}
+
+var _ output.Writer = &Writer{}
+
+// Write writes the processed event to the underlying writer unless it is suppressed
+func (w *Writer) Write(event *output.ResultEvent) error {
+	processed := w.pipeline.Process(event, true)
+	if processed == nil {
+		return nil
+	}
+	return w.Writer.Write(processed)
+}
+
+// WriteFailure writes a redacted copy of the failure event to the underlying writer
+func (w *Writer) WriteFailure(event *output.InternalWrappedEvent) error {
+	return w.Writer.WriteFailure(w.pipeline.redactor.FailureEvent(event))
+}
+
+// ReportingClient is a reporting client passing processed results to
+// the exporters and issue trackers.
+type ReportingClient struct {
+	reporting.Client
+	pipeline *Pipeline
+}
+
+var _ reporting.Client = &ReportingClient{}
+
+// CreateIssue creates an issue for the processed event with the underlying client unless it is suppressed
+func (c *ReportingClient) CreateIssue(event *output.ResultEvent) error {
+	processed := c.pipeline.Process(event, false)
+	if processed == nil {
+		return nil
+	}
+	err := c.Client.CreateIssue(processed)
+	// keep the created issues on the event for the output
+	event.IssueTrackers = processed.IssueTrackers
+	return err
+}
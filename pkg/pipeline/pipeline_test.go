@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/redact"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/risk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/suppress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+type mockClient struct {
+	reporting.Client
+	created []*output.ResultEvent
+}
+
+func (m *mockClient) CreateIssue(event *output.ResultEvent) error {
+	m.created = append(m.created, event)
+	return nil
+}
+
+func TestPipelineOrder(t *testing.T) {
+	redactor, err := redact.NewRedactor(&redact.Policy{Detectors: []string{"none"}, Keys: []string{"token"}})
+	require.Nil(t, err, "could not create redactor")
+	// suppressions match the raw evidence of results
+	suppressor, err := suppress.NewSuppressor([]*suppress.Suppression{{
+		MatchedAt:     "https://www.example.com/?token=123456",
+		Justification: "accepted",
+		Owner:         "appsec",
+		Expires:       "2999-01-01",
+	}})
+	require.Nil(t, err, "could not create suppressor")
+	scorer, err := risk.NewScorer(&risk.Context{Assets: []*risk.Asset{{Hosts: []string{"*.example.com"}, Environment: "production"}}})
+	require.Nil(t, err, "could not create scorer")
+
+	newEvents := func() []*output.ResultEvent {
+		return []*output.ResultEvent{
+			{TemplateID: "suppressed", Host: "www.example.com", Matched: "https://www.example.com/?token=123456"},
+			{TemplateID: "written", Host: "api.example.com", Matched: "https://api.example.com/?token=123456"},
+		}
+	}
+
+	t.Run("suppress-redact-score", func(t *testing.T) {
+		var written []*output.ResultEvent
+		mock := &testutils.MockOutputWriter{WriteCallback: func(event *output.ResultEvent) { written = append(written, event) }}
+		client := &mockClient{}
+		writer, reportingClient := New(redactor, suppressor, scorer).Apply(mock, client)
+
+		events := newEvents()
+		for _, event := range events {
+			require.Nil(t, writer.Write(event))
+			require.Nil(t, reportingClient.CreateIssue(event))
+		}
+		require.Equal(t, int64(1), suppressor.Suppressed(), "could not suppress raw result once")
+
+		for _, results := range [][]*output.ResultEvent{written, client.created} {
+			require.Len(t, results, 1)
+			require.Equal(t, "written", results[0].TemplateID)
+			require.Equal(t, "https://api.example.com/?token=[REDACTED:token]", results[0].Matched)
+			require.NotNil(t, results[0].Risk, "could not score result")
+			require.Equal(t, "production", results[0].Risk.Environment)
+		}
+		require.Nil(t, events[1].Risk, "scored the original result instead of the redacted copy")
+	})
+
+	t.Run("suppress-before-score", func(t *testing.T) {
+		suppressor, err := suppress.NewSuppressor([]*suppress.Suppression{{
+			TemplateID:    "suppressed",
+			Justification: "accepted",
+			Owner:         "appsec",
+			Expires:       "2999-01-01",
+		}})
+		require.Nil(t, err)
+		mock := &testutils.MockOutputWriter{}
+		writer, _ := New(nil, suppressor, scorer).Apply(mock, nil)
+
+		events := newEvents()
+		for _, event := range events {
+			require.Nil(t, writer.Write(event))
+		}
+		require.Nil(t, events[0].Risk, "scored suppressed result")
+		require.NotNil(t, events[1].Risk, "could not score result")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Nil(t, New(nil, nil, nil), "could create pipeline without steps")
+		mock := &testutils.MockOutputWriter{}
+		writer, client := New(nil, nil, nil).Apply(mock, nil)
+		require.Equal(t, output.Writer(mock), writer)
+		require.Nil(t, client)
+	})
+}
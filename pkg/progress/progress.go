@@ -34,7 +34,18 @@ type Progress interface {
 	IncrementFailedRequestsBy(count int64)
 }
 
-var _ Progress = &StatsTicker{}
+// ThrottledProgress is implemented by progress drivers counting the requests
+// slowed down by the per-host rate limiter.
+type ThrottledProgress interface {
+	// IncrementThrottled increments the counter of requests slowed down
+	// by the per-host rate limiter by 1.
+	IncrementThrottled()
+}
+
+var (
+	_ Progress          = &StatsTicker{}
+	_ ThrottledProgress = &StatsTicker{}
+)
 
 // StatsTicker is a progress instance for showing program stats
 type StatsTicker struct {
@@ -84,6 +95,7 @@ func (p *StatsTicker) Init(hostCount int64, rulesCount int, requestCount int64)
 	p.stats.AddCounter("requests", uint64(0))
 	p.stats.AddCounter("errors", uint64(0))
 	p.stats.AddCounter("matched", uint64(0))
+	p.stats.AddCounter("throttled", uint64(0))
 	p.stats.AddCounter("total", uint64(requestCount))
 
 	if p.active {
@@ -142,6 +154,11 @@ func (p *StatsTicker) IncrementFailedRequestsBy(count int64) {
 	p.stats.IncrementCounter("errors", int(count))
 }
 
+// IncrementThrottled increments the counter of requests slowed down by the per-host rate limiter by 1.
+func (p *StatsTicker) IncrementThrottled() {
+	p.stats.IncrementCounter("throttled", 1)
+}
+
 func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient) interface{} {
 	return func(stats clistats.StatisticsClient) interface{} {
 		builder := &strings.Builder{}
@@ -187,6 +204,11 @@ func (p *StatsTicker) makePrintCallback() func(stats clistats.StatisticsClient)
 			builder.WriteString(clistats.String(errors))
 		}
 
+		if throttled, ok := stats.GetCounter("throttled"); ok && throttled > 0 && !p.cloud {
+			builder.WriteString(" | Throttled: ")
+			builder.WriteString(clistats.String(throttled))
+		}
+
 		if okRequests && okTotal {
 			if p.cloud {
 				builder.WriteString(" | Task: ")
@@ -246,6 +268,8 @@ func metricsMap(stats clistats.StatisticsClient) map[string]interface{} {
 	results["rps"] = clistats.String(uint64(float64(requests) / duration.Seconds()))
 	errors, _ := stats.GetCounter("errors")
 	results["errors"] = clistats.String(errors)
+	throttled, _ := stats.GetCounter("throttled")
+	results["throttled"] = clistats.String(throttled)
 
 	// nolint:gomnd // this is not a magic number
 	percentData := (float64(requests) * float64(100)) / float64(total)
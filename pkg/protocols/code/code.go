@@ -73,7 +73,7 @@ type Request struct {
 
 	options              *protocols.ExecutorOptions `yaml:"-" json:"-"`
 	preConditionCompiled *goja.Program              `yaml:"-" json:"-"`
-	gozero               *gozero.Gozero             `yaml:"-" json:"-"`
+	executor             executor                   `yaml:"-" json:"-"`
 	src                  *gozero.Source             `yaml:"-" json:"-"`
 }
 
@@ -92,13 +92,23 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		gozeroOptions.DebugMode = true
 	}
 
-	engine, err := gozero.New(gozeroOptions)
-	if err != nil {
-		return errorutil.NewWithErr(err).Msgf("[%s] engines '%s' not available on host", options.TemplateID, strings.Join(request.Engine, ","))
+	if options.Options.CodeSandbox != "" {
+		// engines are resolved inside the sandbox image instead of the host
+		sandbox, err := newSandboxExecutor(request, options.Options)
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("[%s] could not create code sandbox", options.TemplateID)
+		}
+		request.executor = sandbox
+	} else {
+		engine, err := gozero.New(gozeroOptions)
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("[%s] engines '%s' not available on host", options.TemplateID, strings.Join(request.Engine, ","))
+		}
+		request.executor = engine
 	}
-	request.gozero = engine
 
 	var src *gozero.Source
+	var err error
 
 	src, err = gozero.NewSourceWithString(request.Source, request.Pattern, request.options.TemporaryDirectory)
 	if err != nil {
@@ -224,7 +234,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	defer cancel()
 	// Note: we use contextutil despite the fact that gozero accepts context as argument
 	gOutput, err := contextutil.ExecFuncWithTwoReturns(ctx, func() (*gozerotypes.Result, error) {
-		return request.gozero.Eval(ctx, request.src, metaSrc)
+		return request.executor.Eval(ctx, request.src, metaSrc)
 	})
 	if gOutput == nil {
 		// write error to stderr buff
@@ -238,7 +248,11 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 			Stderr: buff,
 		}
 	}
-	gologger.Verbose().Msgf("[%s] Executed code on local machine %v", request.options.TemplateID, input.MetaInput.Input)
+	if request.options.Options.CodeSandbox != "" {
+		gologger.Verbose().Msgf("[%s] Executed code in %s sandbox %v", request.options.TemplateID, request.options.Options.CodeSandbox, input.MetaInput.Input)
+	} else {
+		gologger.Verbose().Msgf("[%s] Executed code on local machine %v", request.options.TemplateID, input.MetaInput.Input)
+	}
 
 	if vardump.EnableVarDump {
 		gologger.Debug().Msgf("Code Protocol request variables: %s\n", vardump.DumpVariables(allvars))
@@ -305,6 +319,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			ResultCallback: interactsh.ResultCallbackFromContext(input.Context()),
 		})
 	}
 
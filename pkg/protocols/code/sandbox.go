@@ -0,0 +1,187 @@
+package code
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/gozero"
+	"github.com/projectdiscovery/gozero/cmdexec"
+	gozerotypes "github.com/projectdiscovery/gozero/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+	"github.com/rs/xid"
+)
+
+const (
+	// sandboxSourceDir is the directory the source file is mounted read-only in
+	sandboxSourceDir = "/nuclei-source"
+	// sandboxWorkDir is the writable tmpfs directory inside the sandbox
+	sandboxWorkDir = "/tmp"
+	// defaultSandboxImage is the image used when no image is configured for the engine
+	defaultSandboxImage = "docker.io/library/python:3-slim"
+	// defaultSandboxPidsLimit is the maximum number of processes a sandbox can spawn
+	defaultSandboxPidsLimit = "256"
+)
+
+const (
+	// DefaultSandboxCPUs is the default number of cpus available to a sandbox
+	DefaultSandboxCPUs = "1"
+	// DefaultSandboxMemory is the default memory available to a sandbox
+	DefaultSandboxMemory = "256m"
+)
+
+// SandboxRuntimes is the list of supported code sandbox runtimes
+var SandboxRuntimes = []string{"docker", "podman"}
+
+// defaultSandboxImages contains the default sandbox image for an engine
+var defaultSandboxImages = map[string]string{
+	"pwsh":       "mcr.microsoft.com/powershell:latest",
+	"powershell": "mcr.microsoft.com/powershell:latest",
+	"node":       "docker.io/library/node:lts-slim",
+	"ruby":       "docker.io/library/ruby:slim",
+	"perl":       "docker.io/library/perl:slim",
+	"go":         "docker.io/library/golang:alpine",
+}
+
+// sandboxEntrypoint executes the mounted source with the first engine available in the image.
+//
+// $1 is the space separated list of engines, $2 the source file and the
+// remaining arguments are passed to the engine before the source file.
+const sandboxEntrypoint = `engines=$1; source=$2; shift 2
+for engine in $engines; do
+	if command -v "$engine" >/dev/null 2>&1; then exec "$engine" "$@" "$source"; fi
+done
+echo "no valid engine found in sandbox" >&2; exit 127`
+
+// executor evaluates the source of a code request against an input
+type executor interface {
+	Eval(ctx context.Context, src, input *gozero.Source, args ...string) (*gozerotypes.Result, error)
+}
+
+// sandboxExecutor executes code requests in an isolated rootless
+// container with a read-only filesystem, no capabilities and
+// restricted network, cpu and memory.
+type sandboxExecutor struct {
+	runtime string
+	image   string
+	network string
+	cpus    string
+	memory  string
+	engines []string
+	args    []string
+	debug   bool
+}
+
+// newSandboxExecutor creates a new sandbox executor for the request
+func newSandboxExecutor(request *Request, options *types.Options) (*sandboxExecutor, error) {
+	if !stringsutil.EqualFoldAny(options.CodeSandbox, SandboxRuntimes...) {
+		return nil, errorutil.New("unsupported code sandbox runtime %s", options.CodeSandbox)
+	}
+	if len(request.Engine) == 0 {
+		return nil, errorutil.New("no engines provided")
+	}
+	executor := &sandboxExecutor{
+		runtime: strings.ToLower(options.CodeSandbox),
+		image:   options.CodeSandboxImage,
+		network: options.CodeSandboxNetwork,
+		cpus:    options.CodeSandboxCPUs,
+		memory:  options.CodeSandboxMemory,
+		engines: request.Engine,
+		args:    request.Args,
+		debug:   options.Debug || options.DebugResponse,
+	}
+	if executor.image == "" {
+		executor.image = defaultSandboxImage
+		for _, engine := range request.Engine {
+			if image, ok := defaultSandboxImages[filepath.Base(engine)]; ok {
+				executor.image = image
+				break
+			}
+		}
+	}
+	if executor.network == "" {
+		executor.network = "none"
+	}
+	return executor, nil
+}
+
+// Eval executes the source in a new sandbox with the input as stdin and
+// variables as environment, returning the output as if run on the host.
+func (s *sandboxExecutor) Eval(ctx context.Context, src, input *gozero.Source, args ...string) (*gozerotypes.Result, error) {
+	name := "nuclei-code-" + xid.New().String()
+	// runtimes only bind mount absolute paths
+	sourceFile, err := filepath.Abs(src.Filename)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not get path of source file")
+	}
+	cmd, err := cmdexec.NewCommand(s.runtime, s.commandArgs(name, sourceFile, args, src.Variables, input.Variables)...)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("code sandbox runtime %s not available on host", s.runtime)
+	}
+	if s.debug {
+		cmd.EnableDebugMode()
+	}
+	cmd.SetStdin(input.File)
+	// values are passed through the environment of the runtime client
+	// instead of its arguments so that they are not visible in the process list
+	cmd.AddVars(src.Variables...)
+	cmd.AddVars(input.Variables...)
+
+	result, err := cmd.Execute(ctx)
+	if ctx.Err() != nil {
+		// killing the runtime client does not stop an attached container
+		s.remove(name)
+	}
+	return result, err
+}
+
+// commandArgs returns the runtime arguments to execute the source file in a sandbox,
+// the source file is mounted read-only as it can be larger than environment variables
+func (s *sandboxExecutor) commandArgs(name, sourceFile string, args []string, variables ...[]gozerotypes.Variable) []string {
+	source := path.Join(sandboxSourceDir, filepath.Base(sourceFile))
+	cmdArgs := []string{
+		"run", "--rm", "--interactive",
+		"--name", name,
+		"--network", s.network,
+		"--read-only",
+		"--tmpfs", sandboxWorkDir + ":rw,nosuid,nodev",
+		"--workdir", sandboxWorkDir,
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		"--pids-limit", defaultSandboxPidsLimit,
+		"--volume", sourceFile + ":" + source + ":ro,z",
+	}
+	if s.cpus != "" {
+		cmdArgs = append(cmdArgs, "--cpus", s.cpus)
+	}
+	if s.memory != "" {
+		cmdArgs = append(cmdArgs, "--memory", s.memory, "--memory-swap", s.memory)
+	}
+	var envNames []string
+	for _, vars := range variables {
+		for _, variable := range vars {
+			envNames = append(envNames, variable.Name)
+		}
+	}
+	for _, envName := range sliceutil.Dedupe(envNames) {
+		cmdArgs = append(cmdArgs, "--env", envName)
+	}
+	cmdArgs = append(cmdArgs, "--entrypoint", "sh", s.image, "-c", sandboxEntrypoint, "sh", strings.Join(s.engines, " "), source)
+	cmdArgs = append(cmdArgs, s.args...)
+	return append(cmdArgs, args...)
+}
+
+// remove forcefully removes a sandbox by name
+func (s *sandboxExecutor) remove(name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if cmd, err := cmdexec.NewCommand(s.runtime, "rm", "--force", name); err == nil {
+		_, _ = cmd.Execute(ctx)
+	}
+}
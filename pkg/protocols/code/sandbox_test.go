@@ -0,0 +1,53 @@
+//go:build linux || darwin
+
+package code
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	gozerotypes "github.com/projectdiscovery/gozero/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+func TestSandboxExecutor(t *testing.T) {
+	request := &Request{Engine: []string{"py", "python3"}, Args: []string{"-u"}, Source: "print(1)"}
+
+	_, err := newSandboxExecutor(request, &types.Options{CodeSandbox: "chroot"})
+	require.NotNil(t, err, "could not reject unsupported runtime")
+
+	executor, err := newSandboxExecutor(request, &types.Options{CodeSandbox: "Podman", CodeSandboxMemory: "128m"})
+	require.Nil(t, err, "could not create sandbox executor")
+	require.Equal(t, "podman", executor.runtime)
+	require.Equal(t, defaultSandboxImage, executor.image)
+	require.Equal(t, "none", executor.network)
+
+	args := executor.commandArgs("nuclei-code-test", "/tmp/nuclei/1234.py", nil,
+		[]gozerotypes.Variable{{Name: "Host", Value: "example.com"}},
+		[]gozerotypes.Variable{{Name: "Host", Value: "example.com"}, {Name: "Port", Value: "80"}})
+	cmdline := strings.Join(args, " ")
+	require.Contains(t, cmdline, "--network none --read-only")
+	require.Contains(t, cmdline, "--memory 128m --memory-swap 128m")
+	require.Contains(t, cmdline, "--volume /tmp/nuclei/1234.py:"+sandboxSourceDir+"/1234.py:ro,z")
+	require.Contains(t, cmdline, "--env Host --env Port --entrypoint sh")
+	require.NotContains(t, cmdline, "example.com", "variable values must not be passed as arguments")
+	require.Equal(t, []string{"py python3", sandboxSourceDir + "/1234.py", "-u"}, args[len(args)-3:])
+}
+
+func TestSandboxEntrypoint(t *testing.T) {
+	// sources larger than the limit of environment variables are supported
+	padding := strings.Repeat("#", 256*1024)
+	source := filepath.Join(t.TempDir(), "nuclei-sandbox-test.sh")
+	require.Nil(t, os.WriteFile(source, []byte("echo \"$Host\" \"$1\"\n"+padding+"\n"), 0644))
+
+	cmd := exec.Command("sh", "-c", sandboxEntrypoint, "sh", "nuclei-missing-engine sh", source)
+	cmd.Env = append(os.Environ(), "Host=example.com")
+	output, err := cmd.CombinedOutput()
+	require.Nil(t, err, "could not run sandbox entrypoint")
+	require.Equal(t, "example.com \n", string(output))
+}
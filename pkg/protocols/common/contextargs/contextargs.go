@@ -68,6 +68,11 @@ func (ctx *Context) Context() context.Context {
 	return ctx.ctx
 }
 
+// SetContext sets the context of the current contextargs
+func (ctx *Context) SetContext(c context.Context) {
+	ctx.ctx = c
+}
+
 // Set the specific key-value pair
 func (ctx *Context) Set(key string, value interface{}) {
 	_ = ctx.args.Set(key, value)
@@ -0,0 +1,69 @@
+package generators
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// encoders contains the encoders which can be chained on payload values
+var encoders = map[string]func(string) string{
+	"url": url.QueryEscape,
+	"double-url": func(value string) string {
+		return url.QueryEscape(url.QueryEscape(value))
+	},
+	"base64": func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	},
+	"unicode": func(value string) string {
+		builder := &strings.Builder{}
+		for _, char := range value {
+			if char > 0xffff {
+				// encode as utf-16 surrogate pair
+				char -= 0x10000
+				_, _ = fmt.Fprintf(builder, "\\u%04x\\u%04x", 0xd800+(char>>10), 0xdc00+(char&0x3ff))
+				continue
+			}
+			_, _ = fmt.Fprintf(builder, "\\u%04x", char)
+		}
+		return builder.String()
+	},
+	"html": func(value string) string {
+		builder := &strings.Builder{}
+		for _, char := range value {
+			_, _ = fmt.Fprintf(builder, "&#%d;", char)
+		}
+		return builder.String()
+	},
+}
+
+// encodedSource applies a chain of encoders to the values of a source
+type encodedSource struct {
+	source   payloadSource
+	encoders []func(string) string
+}
+
+func newEncodedSource(source payloadSource, names []string) (*encodedSource, error) {
+	encoded := &encodedSource{source: source}
+	for _, name := range names {
+		encoder, ok := encoders[normalizeValue(name)]
+		if !ok {
+			return nil, errors.Errorf("invalid encoder %s", name)
+		}
+		encoded.encoders = append(encoded.encoders, encoder)
+	}
+	return encoded, nil
+}
+
+func (e *encodedSource) Len() int { return e.source.Len() }
+
+func (e *encodedSource) At(index int) string {
+	value := e.source.At(index)
+	for _, encoder := range e.encoders {
+		value = encoder(value)
+	}
+	return value
+}
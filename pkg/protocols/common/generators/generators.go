@@ -13,7 +13,7 @@ import (
 type PayloadGenerator struct {
 	Type     AttackType
 	catalog  catalog.Catalog
-	payloads map[string][]string
+	payloads map[string]payloadSource
 	options  *types.Options
 }
 
@@ -25,9 +25,18 @@ func New(payloads map[string]interface{}, attackType AttackType, templatePath st
 
 	// Resolve payload paths if they are files.
 	payloadsFinal := make(map[string]interface{})
+	specs := make(map[string]*PayloadSpec)
 	for payloadName, v := range payloads {
 		switch value := v.(type) {
 		case map[interface{}]interface{}:
+			if isPayloadSpec(value) {
+				spec, err := parsePayloadSpec(payloadName, value)
+				if err != nil {
+					return nil, err
+				}
+				specs[payloadName] = spec
+				continue
+			}
 			values, err := parsePayloadsWithAggression(payloadName, value, opts.FuzzAggressionLevel)
 			if err != nil {
 				return nil, errors.Wrap(err, "could not parse payloads with aggression")
@@ -35,6 +44,12 @@ func New(payloads map[string]interface{}, attackType AttackType, templatePath st
 			for k, v := range values {
 				payloadsFinal[k] = v
 			}
+		case map[string]interface{}:
+			spec, err := parsePayloadSpec(payloadName, value)
+			if err != nil {
+				return nil, err
+			}
+			specs[payloadName] = spec
 		default:
 			payloadsFinal[payloadName] = v
 		}
@@ -45,10 +60,21 @@ func New(payloads map[string]interface{}, attackType AttackType, templatePath st
 		return nil, err
 	}
 
-	compiled, err := generator.loadPayloads(payloadsFinal, templatePath)
+	loaded, err := generator.loadPayloads(payloadsFinal, templatePath)
 	if err != nil {
 		return nil, err
 	}
+	compiled := make(map[string]payloadSource, len(loaded)+len(specs))
+	for name, values := range loaded {
+		compiled[name] = listSource(values)
+	}
+	for name, spec := range specs {
+		source, err := generator.loadPayloadSpec(name, spec, templatePath)
+		if err != nil {
+			return nil, err
+		}
+		compiled[name] = source
+	}
 	generator.Type = attackType
 	generator.payloads = compiled
 
@@ -167,19 +193,19 @@ func (i *Iterator) Total() int {
 	switch i.Type {
 	case BatteringRamAttack:
 		for _, p := range i.payloads {
-			count += len(p.values)
+			count += p.values.Len()
 		}
 	case PitchForkAttack:
-		count = len(i.payloads[0].values)
+		count = i.payloads[0].values.Len()
 		for _, p := range i.payloads {
-			if count > len(p.values) {
-				count = len(p.values)
+			if count > p.values.Len() {
+				count = p.values.Len()
 			}
 		}
 	case ClusterBombAttack:
 		count = 1
 		for _, p := range i.payloads {
-			count *= len(p.values)
+			count *= p.values.Len()
 		}
 	}
 	return count
@@ -285,12 +311,12 @@ func (i *Iterator) clusterbombIteratorReset() {
 type payloadIterator struct {
 	index  int
 	name   string
-	values []string
+	values payloadSource
 }
 
 // next returns true if there are more values in payload iterator
 func (i *payloadIterator) next() bool {
-	return i.index < len(i.values)
+	return i.index < i.values.Len()
 }
 
 // resetPosition resets the position of the payload iterator
@@ -305,5 +331,5 @@ func (i *payloadIterator) incrementPosition() {
 
 // value returns the value of the payload at an index
 func (i *payloadIterator) value() string {
-	return i.values[i.index]
+	return i.values.At(i.index)
 }
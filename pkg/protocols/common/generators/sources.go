@@ -0,0 +1,458 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	timeutil "github.com/projectdiscovery/utils/time"
+	"gopkg.in/yaml.v2"
+)
+
+// Supported types of programmable payloads
+const (
+	// RangePayload generates numbers between from and to with a step
+	RangePayload = "range"
+	// DateRangePayload generates dates between from and to with a step
+	DateRangePayload = "date-range"
+	// CharsetPayload generates all the combinations of a charset with length bounds
+	CharsetPayload = "charset"
+	// MutatePayload generates mutations of the words of a wordlist
+	MutatePayload = "mutate"
+)
+
+const (
+	defaultRangeFormat = "%d"
+	defaultDateFormat  = "2006-01-02"
+	defaultDateStep    = 24 * time.Hour
+	// maxSourceValues is the maximum number of values a payload source can generate
+	maxSourceValues = math.MaxInt32
+)
+
+// dateLayouts are the layouts accepted for the bounds of a date range
+var dateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04:05"}
+
+// leetReplacer replaces characters with their leet speak equivalent
+var leetReplacer = strings.NewReplacer("a", "4", "A", "4", "e", "3", "E", "3", "i", "1", "I", "1", "o", "0", "O", "0", "s", "5", "S", "5", "t", "7", "T", "7")
+
+// payloadSource is a source of values for a payload.
+//
+// Values are computed on access so that large sources
+// are never materialised in memory.
+type payloadSource interface {
+	// Len returns the number of values of the source
+	Len() int
+	// At returns the value at an index of the source
+	At(index int) string
+}
+
+// PayloadSpec is a definition generating the values of a payload
+// instead of listing them or reading them from a file.
+type PayloadSpec struct {
+	// description: |
+	//   Type is the type of values generated.
+	//
+	//   range generates the numbers from one bound to another, date-range the dates
+	//   between two bounds, charset all the combinations of a set of characters and
+	//   mutate the mutations of a list of words. Without a type, the values or the
+	//   file of the definition are used as is.
+	// values:
+	//   - "range"
+	//   - "date-range"
+	//   - "charset"
+	//   - "mutate"
+	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=type of the generated values,description=Type of the generated values,enum=range,enum=date-range,enum=charset,enum=mutate"`
+	// description: |
+	//   From is the first value of a range or date-range.
+	//
+	//   Dates are in 2006-01-02, 2006-01-02 15:04:05 or RFC3339 format.
+	// examples:
+	//   - value: "\"1\""
+	//   - value: "\"2024-01-01\""
+	From string `yaml:"from,omitempty" json:"from,omitempty" jsonschema:"title=first value of the range,description=First value of a range or date-range"`
+	// description: |
+	//   To is the last value of a range or date-range.
+	// examples:
+	//   - value: "\"100\""
+	//   - value: "\"2024-12-31\""
+	To string `yaml:"to,omitempty" json:"to,omitempty" jsonschema:"title=last value of the range,description=Last value of a range or date-range"`
+	// description: |
+	//   Step is the increment between two values of a range or date-range.
+	//
+	//   It is a number for a range (default 1) and a duration for a date-range (default 24h).
+	// examples:
+	//   - value: "\"5\""
+	//   - value: "\"168h\""
+	Step string `yaml:"step,omitempty" json:"step,omitempty" jsonschema:"title=increment of the range,description=Increment between two values of a range or date-range"`
+	// description: |
+	//   Format is the format of the generated values.
+	//
+	//   It is a printf format for a range (default %d) and a Go time layout for a date-range (default 2006-01-02).
+	// examples:
+	//   - value: "\"%04d\""
+	//   - value: "\"20060102\""
+	Format string `yaml:"format,omitempty" json:"format,omitempty" jsonschema:"title=format of the values,description=Format of the generated values of a range or date-range"`
+	// description: |
+	//   Charset is the set of characters combined by a charset payload.
+	// examples:
+	//   - value: "\"abcdef0123456789\""
+	Charset string `yaml:"charset,omitempty" json:"charset,omitempty" jsonschema:"title=characters to combine,description=Set of characters combined by a charset payload"`
+	// description: |
+	//   MinLength is the minimum length of the combinations of a charset payload.
+	// examples:
+	//   - value: "1"
+	MinLength int `yaml:"min-length,omitempty" json:"min-length,omitempty" jsonschema:"title=minimum length of combinations,description=Minimum length of the combinations of a charset payload"`
+	// description: |
+	//   MaxLength is the maximum length of the combinations of a charset payload.
+	//
+	//   It defaults to the minimum length.
+	// examples:
+	//   - value: "3"
+	MaxLength int `yaml:"max-length,omitempty" json:"max-length,omitempty" jsonschema:"title=maximum length of combinations,description=Maximum length of the combinations of a charset payload"`
+	// description: |
+	//   Values are the words to use as is or to mutate.
+	// examples:
+	//   - value: >
+	//       []string{"admin", "root"}
+	Values []string `yaml:"values,omitempty" json:"values,omitempty" jsonschema:"title=words of the payload,description=Words to use as is or to mutate"`
+	// description: |
+	//   File is a wordlist file read instead of values.
+	// examples:
+	//   - value: "\"helpers/wordlists/users.txt\""
+	File string `yaml:"file,omitempty" json:"file,omitempty" jsonschema:"title=wordlist file of the payload,description=Wordlist file read instead of values"`
+	// description: |
+	//   Rules are the mutations of a mutate payload. Each word is generated as is
+	//   and once mutated by every rule.
+	//
+	//   Supported rules are lower, upper, capitalize, toggle, reverse, leet,
+	//   prefix:<value> and suffix:<value>.
+	// examples:
+	//   - value: >
+	//       []string{"capitalize", "leet", "suffix:123"}
+	Rules []string `yaml:"rules,omitempty" json:"rules,omitempty" jsonschema:"title=mutation rules,description=Mutations of a mutate payload"`
+	// description: |
+	//   Encoders is a chain of encoders applied in order to every value.
+	//
+	//   Supported encoders are url, double-url, base64, unicode and html.
+	// examples:
+	//   - value: >
+	//       []string{"base64", "url"}
+	Encoders []string `yaml:"encoders,omitempty" json:"encoders,omitempty" jsonschema:"title=encoders of the values,description=Chain of encoders applied in order to every value"`
+}
+
+// isPayloadSpec returns true if a payload map is a programmable payload
+// definition instead of a list of payloads per aggression level.
+func isPayloadSpec(value map[interface{}]interface{}) bool {
+	_, hasType := value["type"]
+	_, hasEncoders := value["encoders"]
+	return hasType || hasEncoders
+}
+
+// parsePayloadSpec parses a programmable payload definition
+func parsePayloadSpec(name string, value interface{}) (*PayloadSpec, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not marshal payload %s", name)
+	}
+	spec := &PayloadSpec{}
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, errors.Wrapf(err, "invalid payload %s", name)
+	}
+	return spec, nil
+}
+
+// loadPayloadSpec creates the payload source of a programmable payload
+func (generator *PayloadGenerator) loadPayloadSpec(name string, spec *PayloadSpec, templatePath string) (payloadSource, error) {
+	var source payloadSource
+	var err error
+
+	switch spec.Type {
+	case RangePayload:
+		source, err = newRangeSource(spec)
+	case DateRangePayload:
+		source, err = newDateRangeSource(spec)
+	case CharsetPayload:
+		source, err = newCharsetSource(spec)
+	case MutatePayload, "":
+		var words []string
+		if words, err = generator.loadSpecWords(name, spec, templatePath); err != nil {
+			return nil, err
+		}
+		if spec.Type == "" {
+			source = listSource(words)
+		} else {
+			source, err = newMutateSource(words, spec.Rules)
+		}
+	default:
+		return nil, errors.Errorf("invalid type %s for payload %s", spec.Type, name)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s payload %s", spec.Type, name)
+	}
+	if source.Len() == 0 {
+		return nil, errors.Errorf("the payload %s does not contain enough elements", name)
+	}
+	if len(spec.Encoders) > 0 {
+		if source, err = newEncodedSource(source, spec.Encoders); err != nil {
+			return nil, errors.Wrapf(err, "invalid encoders for payload %s", name)
+		}
+	}
+	return source, nil
+}
+
+// loadSpecWords loads the values or the wordlist file of a payload definition
+func (generator *PayloadGenerator) loadSpecWords(name string, spec *PayloadSpec, templatePath string) ([]string, error) {
+	if len(spec.Values) > 0 {
+		return spec.Values, nil
+	}
+	if spec.File == "" {
+		return nil, errors.Errorf("no values or file provided for payload %s", name)
+	}
+	payloads := map[string]interface{}{name: spec.File}
+	if err := generator.validate(payloads, templatePath); err != nil {
+		return nil, err
+	}
+	loaded, err := generator.loadPayloads(payloads, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	return loaded[name], nil
+}
+
+// listSource is a static list of payload values
+type listSource []string
+
+func (l listSource) Len() int            { return len(l) }
+func (l listSource) At(index int) string { return l[index] }
+
+// rangeSource generates numbers between two bounds
+type rangeSource struct {
+	from, step int64
+	count      int
+	format     string
+}
+
+func newRangeSource(spec *PayloadSpec) (*rangeSource, error) {
+	from, err := strconv.ParseInt(spec.From, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid from")
+	}
+	to, err := strconv.ParseInt(spec.To, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid to")
+	}
+	step := int64(1)
+	if spec.Step != "" {
+		if step, err = strconv.ParseInt(spec.Step, 10, 64); err != nil {
+			return nil, errors.Wrap(err, "invalid step")
+		}
+	}
+	if step == 0 || (to-from)/step < 0 {
+		return nil, errors.Errorf("step %d does not go from %d to %d", step, from, to)
+	}
+	count := (to-from)/step + 1
+	if count > maxSourceValues {
+		return nil, errors.Errorf("range generates more than %d values", maxSourceValues)
+	}
+	format := spec.Format
+	if format == "" {
+		format = defaultRangeFormat
+	}
+	return &rangeSource{from: from, step: step, count: int(count), format: format}, nil
+}
+
+func (r *rangeSource) Len() int { return r.count }
+
+func (r *rangeSource) At(index int) string {
+	return fmt.Sprintf(r.format, r.from+int64(index)*r.step)
+}
+
+// dateRangeSource generates dates between two bounds
+type dateRangeSource struct {
+	from   time.Time
+	step   time.Duration
+	count  int
+	format string
+}
+
+func newDateRangeSource(spec *PayloadSpec) (*dateRangeSource, error) {
+	from, err := parseDate(spec.From)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid from")
+	}
+	to, err := parseDate(spec.To)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid to")
+	}
+	step := defaultDateStep
+	if spec.Step != "" {
+		if step, err = timeutil.ParseDuration(spec.Step); err != nil {
+			return nil, errors.Wrap(err, "invalid step")
+		}
+	}
+	if step <= 0 || to.Before(from) {
+		return nil, errors.Errorf("step %s does not go from %s to %s", step, spec.From, spec.To)
+	}
+	count := int64(to.Sub(from)/step) + 1
+	if count > maxSourceValues {
+		return nil, errors.Errorf("date range generates more than %d values", maxSourceValues)
+	}
+	format := spec.Format
+	if format == "" {
+		format = defaultDateFormat
+	}
+	return &dateRangeSource{from: from, step: step, count: int(count), format: format}, nil
+}
+
+// parseDate parses a date in any of the supported layouts
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("could not parse date %s", value)
+}
+
+func (d *dateRangeSource) Len() int { return d.count }
+
+func (d *dateRangeSource) At(index int) string {
+	return d.from.Add(time.Duration(index) * d.step).Format(d.format)
+}
+
+// charsetSource generates all the combinations of a charset
+// from the minimum to the maximum length
+type charsetSource struct {
+	charset []rune
+	// lengths contains the number of combinations up to each length
+	lengths []int
+	min     int
+}
+
+func newCharsetSource(spec *PayloadSpec) (*charsetSource, error) {
+	var charset []rune
+	seen := make(map[rune]struct{})
+	for _, char := range spec.Charset {
+		if _, ok := seen[char]; !ok {
+			seen[char] = struct{}{}
+			charset = append(charset, char)
+		}
+	}
+	if len(charset) == 0 {
+		return nil, errors.New("empty charset")
+	}
+	minLength, maxLength := spec.MinLength, spec.MaxLength
+	if minLength <= 0 {
+		minLength = 1
+	}
+	if maxLength == 0 {
+		maxLength = minLength
+	}
+	if maxLength < minLength {
+		return nil, errors.Errorf("max-length %d is lower than min-length %d", maxLength, minLength)
+	}
+
+	source := &charsetSource{charset: charset, min: minLength}
+	total := 0
+	for length := minLength; length <= maxLength; length++ {
+		combinations := math.Pow(float64(len(charset)), float64(length))
+		if float64(total)+combinations > maxSourceValues {
+			return nil, errors.Errorf("charset generates more than %d values", maxSourceValues)
+		}
+		total += int(combinations)
+		source.lengths = append(source.lengths, total)
+	}
+	return source, nil
+}
+
+func (c *charsetSource) Len() int { return c.lengths[len(c.lengths)-1] }
+
+func (c *charsetSource) At(index int) string {
+	length := c.min
+	for i, total := range c.lengths {
+		if index < total {
+			length = c.min + i
+			if i > 0 {
+				index -= c.lengths[i-1]
+			}
+			break
+		}
+	}
+	value := make([]rune, length)
+	for position := length - 1; position >= 0; position-- {
+		value[position] = c.charset[index%len(c.charset)]
+		index /= len(c.charset)
+	}
+	return string(value)
+}
+
+// mutateSource generates the words of a wordlist followed
+// by their mutations for each rule.
+type mutateSource struct {
+	words []string
+	rules []func(string) string
+}
+
+func newMutateSource(words []string, rules []string) (*mutateSource, error) {
+	source := &mutateSource{words: words, rules: []func(string) string{func(word string) string { return word }}}
+	for _, rule := range rules {
+		mutation, err := toMutation(rule)
+		if err != nil {
+			return nil, err
+		}
+		source.rules = append(source.rules, mutation)
+	}
+	return source, nil
+}
+
+// toMutation returns the mutation function for a rule
+func toMutation(rule string) (func(string) string, error) {
+	name, argument, _ := strings.Cut(rule, ":")
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "lower":
+		return strings.ToLower, nil
+	case "upper":
+		return strings.ToUpper, nil
+	case "capitalize":
+		return func(word string) string {
+			if word == "" {
+				return word
+			}
+			return strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}, nil
+	case "toggle":
+		return func(word string) string {
+			return strings.Map(func(char rune) rune {
+				if unicode.IsUpper(char) {
+					return unicode.ToLower(char)
+				}
+				return unicode.ToUpper(char)
+			}, word)
+		}, nil
+	case "reverse":
+		return func(word string) string {
+			runes := []rune(word)
+			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			return string(runes)
+		}, nil
+	case "leet":
+		return leetReplacer.Replace, nil
+	case "prefix":
+		return func(word string) string { return argument + word }, nil
+	case "suffix":
+		return func(word string) string { return word + argument }, nil
+	}
+	return nil, errors.Errorf("invalid mutation rule %s", rule)
+}
+
+func (m *mutateSource) Len() int { return len(m.words) * len(m.rules) }
+
+func (m *mutateSource) At(index int) string {
+	return m.rules[index%len(m.rules)](m.words[index/len(m.rules)])
+}
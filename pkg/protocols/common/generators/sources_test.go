@@ -0,0 +1,103 @@
+package generators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
+)
+
+func TestProgrammablePayloads(t *testing.T) {
+	testPayload := `id:
+  type: range
+  from: 8
+  to: 12
+  step: 2
+  format: "%03d"
+day:
+  type: date-range
+  from: 2024-02-28
+  to: 2024-03-01
+  format: "20060102"
+pin:
+  type: charset
+  charset: "ab"
+  min-length: 1
+  max-length: 2
+password:
+  type: mutate
+  values:
+    - admin
+  rules:
+    - capitalize
+    - leet
+    - suffix:123
+xss:
+  values:
+    - "<a b>"
+  encoders:
+    - url
+    - base64`
+
+	var payloads map[string]interface{}
+	err := yaml.NewDecoder(strings.NewReader(testPayload)).Decode(&payloads)
+	require.Nil(t, err, "could not unmarshal yaml")
+
+	expected := map[string][]string{
+		"id":       {"008", "010", "012"},
+		"day":      {"20240228", "20240229", "20240301"},
+		"pin":      {"a", "b", "aa", "ab", "ba", "bb"},
+		"password": {"admin", "Admin", "4dm1n", "admin123"},
+		"xss":      {"JTNDYStiJTNF"},
+	}
+
+	catalogInstance := disk.NewCatalog("")
+	for name, values := range expected {
+		generator, err := New(map[string]interface{}{name: payloads[name]}, BatteringRamAttack, "", catalogInstance, "", getOptions(false))
+		require.Nil(t, err, "could not create generator for %s", name)
+
+		iterator := generator.NewIterator()
+		require.Equal(t, len(values), iterator.Total(), "could not get correct total for %s", name)
+
+		var got []string
+		for {
+			value, ok := iterator.Value()
+			if !ok {
+				break
+			}
+			got = append(got, value[name].(string))
+		}
+		require.Equal(t, values, got, "could not get correct values for %s", name)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := []map[interface{}]interface{}{
+			{"type": "range", "from": 10, "to": 1},
+			{"type": "charset", "charset": "0123456789", "min-length": 12},
+			{"type": "mutate", "values": []interface{}{"admin"}, "rules": []interface{}{"unknown"}},
+			{"values": []interface{}{"admin"}, "encoders": []interface{}{"unknown"}},
+			{"type": "unknown"},
+		}
+		for _, payload := range invalid {
+			_, err := New(map[string]interface{}{"invalid": payload}, BatteringRamAttack, "", catalogInstance, "", getOptions(false))
+			require.Error(t, err, "could create generator for %v", payload)
+		}
+	})
+}
+
+func TestPayloadEncoders(t *testing.T) {
+	source, err := newEncodedSource(listSource{"<'é😀'>"}, []string{"unicode"})
+	require.Nil(t, err, "could not create encoded source")
+	require.Equal(t, "\\u003c\\u0027\\u00e9\\ud83d\\ude00\\u0027\\u003e", source.At(0), "could not get unicode encoded value")
+
+	source, err = newEncodedSource(listSource{"<a>"}, []string{"html"})
+	require.Nil(t, err, "could not create encoded source")
+	require.Equal(t, "&#60;&#97;&#62;", source.At(0), "could not get html encoded value")
+
+	source, err = newEncodedSource(listSource{"a b/"}, []string{"double-url"})
+	require.Nil(t, err, "could not create encoded source")
+	require.Equal(t, "a%2Bb%252F", source.At(0), "could not get double url encoded value")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types/nucleierr"
 	"github.com/projectdiscovery/utils/errkit"
 	stringsutil "github.com/projectdiscovery/utils/strings"
@@ -125,6 +126,7 @@ func (c *Cache) Check(protoType string, ctx *contextargs.Context) bool {
 	if existingCacheItem.isPermanentErr {
 		// skipping permanent errors is expected so verbose instead of info
 		gologger.Verbose().Msgf("Skipped %s from target list as found unresponsive permanently: %s", finalValue, existingCacheItem.cause)
+		telemetry.ObserveHostErrorSkip(protoType, finalValue)
 		return true
 	}
 
@@ -132,6 +134,7 @@ func (c *Cache) Check(protoType string, ctx *contextargs.Context) bool {
 		existingCacheItem.Do(func() {
 			gologger.Info().Msgf("Skipped %s from target list as found unresponsive %d times", finalValue, existingCacheItem.errors.Load())
 		})
+		telemetry.ObserveHostErrorSkip(protoType, finalValue)
 		return true
 	}
 	return false
@@ -0,0 +1,391 @@
+// Package hostratelimit implements per-host rate limiting with token
+// buckets adapting their rate to the throttling signals of the host.
+package hostratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mzack9999/gcache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	urlutil "github.com/projectdiscovery/utils/url"
+)
+
+const (
+	// DefaultMaxBackoff is the default maximum pause honoured from a Retry-After header
+	DefaultMaxBackoff = time.Minute
+	// DefaultMaxHosts is the default maximum number of host buckets kept
+	DefaultMaxHosts = 10000
+	// DefaultIdleTimeout is the default time after which the bucket of an idle host is dropped
+	DefaultIdleTimeout = 10 * time.Minute
+	// DefaultMinRate is the default rate in requests per second a host is never slowed down below
+	DefaultMinRate = 0.1
+
+	// unlimitedRate is the rate an unlimited host starts from when it is throttled
+	unlimitedRate = 100
+	// backoffFactor is the factor the rate is multiplied with on a throttled response
+	backoffFactor = 0.5
+	// latencyBackoffFactor is the factor the rate is multiplied with on a latency spike
+	latencyBackoffFactor = 0.75
+	// recoveryStep is the fraction of the configured rate recovered on every healthy response
+	recoveryStep = 0.1
+	// latencySpikeFactor is how many times the average latency a response must take to be a spike
+	latencySpikeFactor = 3
+	// minLatencySpike is the minimum latency of a spike to ignore jitter of fast hosts
+	minLatencySpike = 500 * time.Millisecond
+	// latencyWeight is the weight of a new sample in the moving average of the latency
+	latencyWeight = 0.2
+)
+
+// Rule configures the rate limit of the hosts matching a pattern
+type Rule struct {
+	// Pattern is a glob pattern matched against the hostname, e.g. *.example.com
+	Pattern string
+	// Rate is the maximum number of requests per second
+	Rate float64
+}
+
+// ParseRule parses a rule in pattern=rate format. The rate is in requests
+// per second unless suffixed with /m for requests per minute.
+func ParseRule(value string) (*Rule, error) {
+	pattern, rate, ok := strings.Cut(value, "=")
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if !ok || pattern == "" {
+		return nil, errorutil.New("invalid host rate limit %s: expected pattern=rate", value)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("invalid host pattern %s", pattern)
+	}
+	per := time.Second
+	rate = strings.TrimSpace(rate)
+	if strings.HasSuffix(rate, "/m") {
+		per = time.Minute
+	}
+	rate = strings.TrimSuffix(strings.TrimSuffix(rate, "/m"), "/s")
+	value64, err := strconv.ParseFloat(rate, 64)
+	if err != nil || value64 < 0 {
+		return nil, errorutil.New("invalid rate %s for host pattern %s", rate, pattern)
+	}
+	return &Rule{Pattern: pattern, Rate: value64 / per.Seconds()}, nil
+}
+
+// Options contains the configuration options for the limiter
+type Options struct {
+	// Rules are the per-host rate limits. The first matching rule is used.
+	Rules []*Rule
+	// DefaultRate is the rate of hosts not matching any rule. 0 means unlimited.
+	DefaultRate float64
+	// Adaptive enables slowing down hosts on throttling signals and
+	// recovering their rate gradually.
+	Adaptive bool
+	// MinRate is the rate a host is never slowed down below
+	MinRate float64
+	// MaxBackoff is the maximum pause honoured from a Retry-After header
+	MaxBackoff time.Duration
+	// MaxHosts is the maximum number of host buckets kept, the least
+	// recently used ones are dropped first.
+	MaxHosts int
+	// IdleTimeout is the time after which the bucket of an idle host is dropped
+	IdleTimeout time.Duration
+}
+
+// Signal is the outcome of a request used to adapt the rate of a host
+type Signal struct {
+	// StatusCode is the status code of the response
+	StatusCode int
+	// RetryAfter is the value of the Retry-After header of the response
+	RetryAfter string
+	// Latency is the time taken by the request
+	Latency time.Duration
+	// Err is the error of the request if it failed
+	Err error
+}
+
+// HostStats contains the rate limiting statistics of a host
+type HostStats struct {
+	Host string `json:"host"`
+	// Rate is the current rate in requests per second. 0 means unlimited.
+	Rate float64 `json:"rate"`
+	// ConfiguredRate is the configured rate in requests per second. 0 means unlimited.
+	ConfiguredRate float64 `json:"configured-rate"`
+	// Throttled is the number of times the host was slowed down
+	Throttled int64 `json:"throttled"`
+}
+
+// Limiter is a per-host rate limiter. All methods are safe to call on a
+// nil limiter, in which case no limits are applied.
+//
+// It uses an LRU cache internally so that the buckets of hosts which are
+// no longer scanned are dropped and start over from the configured rate.
+type Limiter struct {
+	options *Options
+
+	mu      sync.Mutex
+	buckets gcache.Cache[string, *bucket]
+}
+
+// New returns a new per-host rate limiter
+func New(options *Options) *Limiter {
+	if options.MinRate <= 0 {
+		options.MinRate = DefaultMinRate
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = DefaultMaxBackoff
+	}
+	if options.MaxHosts <= 0 {
+		options.MaxHosts = DefaultMaxHosts
+	}
+	if options.IdleTimeout <= 0 {
+		options.IdleTimeout = DefaultIdleTimeout
+	}
+	// the lease renews the expiration of a bucket on every access
+	buckets := gcache.New[string, *bucket](options.MaxHosts).
+		LRU().
+		Expiration(options.IdleTimeout).
+		Lease(options.IdleTimeout).
+		Build()
+	return &Limiter{options: options, buckets: buckets}
+}
+
+// NewFromOptions returns the per-host rate limiter configured by the
+// nuclei options or nil if per-host rate limiting is not enabled.
+func NewFromOptions(options *types.Options) (*Limiter, error) {
+	if len(options.HostRateLimits) == 0 && !options.AdaptiveRateLimit {
+		return nil, nil
+	}
+	limiterOptions := &Options{Adaptive: options.AdaptiveRateLimit}
+	// hosts without a rule start from the global rate limit
+	if options.RateLimit > 0 && options.RateLimitDuration > 0 {
+		limiterOptions.DefaultRate = float64(options.RateLimit) / options.RateLimitDuration.Seconds()
+	}
+	for _, value := range options.HostRateLimits {
+		rule, err := ParseRule(value)
+		if err != nil {
+			return nil, err
+		}
+		limiterOptions.Rules = append(limiterOptions.Rules, rule)
+	}
+	return New(limiterOptions), nil
+}
+
+// HostFromURL returns the rate limiting key of a URL or host:port value
+func HostFromURL(value string) string {
+	if parsed, err := urlutil.Parse(value); err == nil && parsed.Hostname() != "" {
+		return strings.ToLower(parsed.Hostname())
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(value)
+}
+
+// Take waits until a request can be sent to the host or ctx is done
+func (l *Limiter) Take(ctx context.Context, host string) error {
+	if l == nil || host == "" {
+		return nil
+	}
+	wait := l.bucket(host).reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe adapts the rate of the host to the signal of a request. It
+// returns true if the host was slowed down.
+func (l *Limiter) Observe(host string, signal Signal) bool {
+	if l == nil || host == "" || !l.options.Adaptive {
+		return false
+	}
+	return l.bucket(host).observe(time.Now(), signal, l.options)
+}
+
+// Stats returns the statistics of the tracked hosts that were slowed down
+func (l *Limiter) Stats() []HostStats {
+	if l == nil {
+		return nil
+	}
+	var stats []HostStats
+	for host, bucket := range l.buckets.GetALL(true) {
+		bucket.mu.Lock()
+		if bucket.throttled > 0 {
+			stats = append(stats, HostStats{Host: host, Rate: bucket.rate, ConfiguredRate: bucket.configured, Throttled: bucket.throttled})
+		}
+		bucket.mu.Unlock()
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
+
+// bucket returns the bucket of the host creating it if needed
+func (l *Limiter) bucket(host string) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, err := l.buckets.GetIFPresent(host); err == nil {
+		return b
+	}
+	rate := l.options.DefaultRate
+	for _, rule := range l.options.Rules {
+		if matched, _ := path.Match(rule.Pattern, host); matched {
+			rate = rule.Rate
+			break
+		}
+	}
+	b := &bucket{configured: rate, rate: rate}
+	_ = l.buckets.Set(host, b)
+	return b
+}
+
+// bucket is the token bucket of a host
+type bucket struct {
+	mu sync.Mutex
+	// configured is the configured rate, rate the current adapted one
+	configured  float64
+	rate        float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	latency     time.Duration
+	throttled   int64
+}
+
+// reserve takes a token from the bucket returning how long to wait
+// before sending the request.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var wait time.Duration
+	start := now
+	if b.pausedUntil.After(now) {
+		start = b.pausedUntil
+		wait = b.pausedUntil.Sub(now)
+	}
+	if b.rate <= 0 {
+		return wait
+	}
+	burst := math.Max(1, b.rate)
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else if start.After(b.last) {
+		b.tokens += start.Sub(b.last).Seconds() * b.rate
+	}
+	b.tokens = math.Min(b.tokens, burst)
+	if start.After(b.last) {
+		b.last = start
+	}
+	b.tokens--
+	if b.tokens < 0 {
+		wait += time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	return wait
+}
+
+func (b *bucket) observe(now time.Time, signal Signal, options *Options) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case signal.StatusCode == http.StatusTooManyRequests || signal.StatusCode == http.StatusServiceUnavailable:
+		b.slowDown(backoffFactor, options.MinRate)
+		if pause := retryAfter(signal.RetryAfter, now); pause > 0 {
+			if pause > options.MaxBackoff {
+				pause = options.MaxBackoff
+			}
+			if until := now.Add(pause); until.After(b.pausedUntil) {
+				b.pausedUntil = until
+			}
+		}
+		return true
+	case isTimeout(signal.Err), b.isLatencySpike(signal.Latency):
+		b.slowDown(latencyBackoffFactor, options.MinRate)
+		return true
+	}
+	if signal.Err == nil && signal.Latency > 0 {
+		if b.latency == 0 {
+			b.latency = signal.Latency
+		} else {
+			b.latency = time.Duration(float64(b.latency)*(1-latencyWeight) + float64(signal.Latency)*latencyWeight)
+		}
+	}
+	b.recover()
+	return false
+}
+
+// slowDown multiplies the current rate with factor
+func (b *bucket) slowDown(factor, minRate float64) {
+	b.throttled++
+	rate := b.rate
+	if rate <= 0 {
+		rate = unlimitedRate
+	}
+	b.rate = math.Max(minRate, rate*factor)
+}
+
+// recover increases the current rate by a step towards the configured rate
+func (b *bucket) recover() {
+	if b.rate <= 0 || b.rate == b.configured {
+		return
+	}
+	target := b.configured
+	if target <= 0 {
+		target = unlimitedRate
+	}
+	b.rate += target * recoveryStep
+	if b.rate >= target {
+		b.rate = b.configured
+	}
+}
+
+// isLatencySpike returns true if latency is well above the average latency of the host
+func (b *bucket) isLatencySpike(latency time.Duration) bool {
+	return b.latency > 0 && latency >= minLatencySpike && latency > b.latency*latencySpikeFactor
+}
+
+// retryAfter returns the pause requested by a Retry-After header value
+// in either delay-seconds or http-date format.
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,110 @@
+package hostratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("*.Example.com=10")
+	require.Nil(t, err)
+	require.Equal(t, "*.example.com", rule.Pattern)
+	require.Equal(t, float64(10), rule.Rate)
+
+	rule, err = ParseRule("api.example.com=120/m")
+	require.Nil(t, err)
+	require.Equal(t, float64(2), rule.Rate)
+
+	for _, value := range []string{"example.com", "=10", "example.com=fast", "[=10"} {
+		_, err := ParseRule(value)
+		require.NotNil(t, err, "could parse invalid rule %s", value)
+	}
+}
+
+func TestHostFromURL(t *testing.T) {
+	require.Equal(t, "example.com", HostFromURL("https://EXAMPLE.com:8443/path"))
+	require.Equal(t, "example.com", HostFromURL("example.com:80"))
+	require.Equal(t, "127.0.0.1", HostFromURL("127.0.0.1"))
+}
+
+func TestLimiterRules(t *testing.T) {
+	limiter := New(&Options{
+		Rules:       []*Rule{{Pattern: "*.example.com", Rate: 2}},
+		DefaultRate: 0,
+	})
+	now := time.Now()
+
+	slow := limiter.bucket("api.example.com")
+	require.Zero(t, slow.reserve(now))
+	require.Zero(t, slow.reserve(now))
+	require.Equal(t, 500*time.Millisecond, slow.reserve(now), "could not limit host matching rule")
+
+	fast := limiter.bucket("other.com")
+	for i := 0; i < 100; i++ {
+		require.Zero(t, fast.reserve(now), "could not skip limits of unlimited host")
+	}
+	require.Nil(t, limiter.Take(context.Background(), "other.com"))
+}
+
+func TestLimiterAdaptive(t *testing.T) {
+	limiter := New(&Options{DefaultRate: 10, Adaptive: true})
+	host := "example.com"
+
+	require.False(t, limiter.Observe(host, Signal{StatusCode: http.StatusOK, Latency: 100 * time.Millisecond}))
+	require.True(t, limiter.Observe(host, Signal{StatusCode: http.StatusTooManyRequests, RetryAfter: "2"}))
+
+	stats := limiter.Stats()
+	require.Len(t, stats, 1)
+	require.Equal(t, float64(5), stats[0].Rate, "could not slow down throttled host")
+	require.Equal(t, float64(10), stats[0].ConfiguredRate)
+
+	wait := limiter.bucket(host).reserve(time.Now())
+	require.InDelta(t, 2*time.Second, wait, float64(100*time.Millisecond), "could not honour retry-after")
+
+	// latency spikes slow the host down as well
+	require.True(t, limiter.Observe(host, Signal{StatusCode: http.StatusOK, Latency: 2 * time.Second}))
+	require.Equal(t, 3.75, limiter.Stats()[0].Rate)
+
+	// healthy responses recover the rate gradually up to the configured one
+	for i := 0; i < 10; i++ {
+		limiter.Observe(host, Signal{StatusCode: http.StatusOK, Latency: 100 * time.Millisecond})
+	}
+	require.Equal(t, float64(10), limiter.Stats()[0].Rate)
+	require.Equal(t, int64(2), limiter.Stats()[0].Throttled)
+}
+
+func TestLimiterAdaptiveUnlimited(t *testing.T) {
+	limiter := New(&Options{Adaptive: true})
+	host := "example.com"
+
+	require.True(t, limiter.Observe(host, Signal{StatusCode: http.StatusServiceUnavailable}))
+	require.Equal(t, float64(unlimitedRate*backoffFactor), limiter.Stats()[0].Rate)
+
+	for i := 0; i < 5; i++ {
+		limiter.Observe(host, Signal{StatusCode: http.StatusOK})
+	}
+	require.Zero(t, limiter.Stats()[0].Rate, "could not recover unlimited host")
+
+	var nilLimiter *Limiter
+	require.Nil(t, nilLimiter.Take(context.Background(), host))
+	require.False(t, nilLimiter.Observe(host, Signal{StatusCode: http.StatusTooManyRequests}))
+}
+
+func TestLimiterEviction(t *testing.T) {
+	limiter := New(&Options{DefaultRate: 10, Adaptive: true, MaxHosts: 2, IdleTimeout: 100 * time.Millisecond})
+
+	require.True(t, limiter.Observe("a.com", Signal{StatusCode: http.StatusTooManyRequests}))
+	limiter.bucket("b.com")
+	limiter.bucket("c.com")
+	require.Empty(t, limiter.Stats(), "could not evict least recently used host")
+	require.Equal(t, float64(10), limiter.bucket("a.com").rate, "could not reset evicted host")
+
+	require.True(t, limiter.Observe("a.com", Signal{StatusCode: http.StatusTooManyRequests}))
+	require.Len(t, limiter.Stats(), 1)
+	time.Sleep(200 * time.Millisecond)
+	require.Empty(t, limiter.Stats(), "could not expire idle host")
+}
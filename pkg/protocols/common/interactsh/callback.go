@@ -0,0 +1,72 @@
+package interactsh
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// ResultCallback receives the results of interactions of the requests sent
+// by an execution instead of the output of the client.
+//
+// Interactions may arrive after the execution is completed, the owner of
+// the callback waits for them and closes it. Results of interactions
+// received once the callback is closed are written to the output.
+type ResultCallback struct {
+	mu         sync.Mutex
+	callback   func(event *output.ResultEvent)
+	closed     bool
+	registered atomic.Bool
+}
+
+type resultCallbackKey struct{}
+
+// NewResultCallback returns a new result callback calling callback on each result
+func NewResultCallback(callback func(event *output.ResultEvent)) *ResultCallback {
+	return &ResultCallback{callback: callback}
+}
+
+// WithResultCallback returns a copy of ctx in which interactsh results of
+// requests sent with it are delivered to callback
+func WithResultCallback(ctx context.Context, callback *ResultCallback) context.Context {
+	return context.WithValue(ctx, resultCallbackKey{}, callback)
+}
+
+// ResultCallbackFromContext returns the result callback of ctx or nil
+func ResultCallbackFromContext(ctx context.Context) *ResultCallback {
+	if ctx == nil {
+		return nil
+	}
+	callback, _ := ctx.Value(resultCallbackKey{}).(*ResultCallback)
+	return callback
+}
+
+// Registered returns true if requests waiting for interactions were sent with the callback
+func (r *ResultCallback) Registered() bool {
+	return r.registered.Load()
+}
+
+// Close stops delivering results to the callback
+func (r *ResultCallback) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+}
+
+// deliver calls the callback on each result of the event returning false
+// if the callback is closed
+func (r *ResultCallback) deliver(data *output.InternalWrappedEvent) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false
+	}
+	for _, result := range data.Results {
+		r.callback(result)
+	}
+	return true
+}
@@ -212,8 +212,8 @@ func (c *Client) processInteractionForRequest(interaction *server.Interaction, d
 		c.debugPrintInteraction(interaction, data.Event.OperatorsResult)
 	}
 
-	// if event is not already matched, write it to output
-	if !data.Event.InteractshMatched.Load() && writer.WriteResult(data.Event, c.options.Output, c.options.Progress, c.options.IssuesClient) {
+	// if event is not already matched, write it to the callback of the request or to output
+	if !data.Event.InteractshMatched.Load() && c.writeResult(data) {
 		data.Event.InteractshMatched.Store(true)
 		c.matched.Store(true)
 		if requestShouldStopAtFirstMatch(data) || c.options.StopAtFirstMatch {
@@ -224,6 +224,15 @@ func (c *Client) processInteractionForRequest(interaction *server.Interaction, d
 	return true
 }
 
+// writeResult writes the results of a matched request to its result callback
+// if it is still open or to the output of the client otherwise
+func (c *Client) writeResult(data *RequestData) bool {
+	if data.ResultCallback != nil && data.Event.HasOperatorResult() && data.ResultCallback.deliver(data.Event) {
+		return len(data.Event.Results) > 0
+	}
+	return writer.WriteResult(data.Event, c.options.Output, c.options.Progress, c.options.IssuesClient)
+}
+
 func (c *Client) AlreadyMatched(data *RequestData) bool {
 	data.Event.RLock()
 	defer data.Event.RUnlock()
@@ -332,10 +341,16 @@ type RequestData struct {
 
 	Parameter string
 	Request   *retryablehttp.Request
+
+	// ResultCallback optionally receives the results instead of the output
+	ResultCallback *ResultCallback
 }
 
 // RequestEvent is the event for a network request sent by nuclei.
 func (c *Client) RequestEvent(interactshURLs []string, data *RequestData) {
+	if data.ResultCallback != nil && len(interactshURLs) > 0 {
+		data.ResultCallback.registered.Store(true)
+	}
 	for _, interactshURL := range interactshURLs {
 		id := strings.TrimRight(strings.TrimSuffix(interactshURL, c.getHostname()), ".")
 
@@ -33,7 +33,10 @@ type Request struct {
 	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=hostname to make dns request for,description=Name is the Hostname to make DNS request for"`
 	// description: |
 	//   RequestType is the type of DNS request to make.
-	RequestType DNSRequestTypeHolder `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=type of dns request to make,description=Type is the type of DNS request to make,enum=A,enum=NS,enum=DS,enum=CNAME,enum=SOA,enum=PTR,enum=MX,enum=TXT,enum=AAAA"`
+	//
+	//   AXFR and IXFR attempt a zone transfer from the resolvers of the
+	//   template or the nameservers of the zone, returning the full record set.
+	RequestType DNSRequestTypeHolder `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=type of dns request to make,description=Type is the type of DNS request to make,enum=A,enum=NS,enum=DS,enum=CNAME,enum=SOA,enum=PTR,enum=MX,enum=TXT,enum=AAAA,enum=AXFR,enum=IXFR"`
 	// description: |
 	//   Class is the class of the DNS request.
 	//
@@ -94,7 +97,14 @@ type Request struct {
 	// description: |
 	//   Recursion determines if resolver should recurse all records to get fresh results.
 	Recursion *bool `yaml:"recursion,omitempty" json:"recursion,omitempty" jsonschema:"title=recurse all servers,description=Recursion determines if resolver should recurse all records to get fresh results"`
-	// Resolvers to use for the dns requests
+	// description: |
+	//   Resolvers to use for the dns requests.
+	//
+	//   DNS-over-HTTPS and DNS-over-TLS resolvers are used with the
+	//   `https://` and `tls://` schemes.
+	// examples:
+	//   - value: |
+	//       []string{"8.8.8.8:53", "https://dns.google/dns-query", "tls://1.1.1.1:853"}
 	Resolvers []string `yaml:"resolvers,omitempty" json:"resolvers,omitempty" jsonschema:"title=Resolvers,description=Define resolvers to use within the template"`
 }
 
@@ -225,6 +235,9 @@ func (request *Request) Make(host string, vars map[string]interface{}) (*dns.Msg
 	final := replacer.Replace(request.Name, vars)
 
 	q.Name = dns.Fqdn(final)
+	if request.isTransfer() {
+		return makeTransfer(q.Name, request.question), nil
+	}
 	q.Qclass = request.class
 	q.Qtype = request.question
 	req.Question = append(req.Question, q)
@@ -271,6 +284,10 @@ func questionTypeToInt(questionType string) uint16 {
 		question = dns.TypeANY
 	case "SRV":
 		question = dns.TypeSRV
+	case "AXFR":
+		question = dns.TypeAXFR
+	case "IXFR":
+		question = dns.TypeIXFR
 	}
 	return question
 }
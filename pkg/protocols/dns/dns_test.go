@@ -87,3 +87,31 @@ func TestDNSRequests(t *testing.T) {
 		require.Equal(t, 3, reqCount, "could not get correct dns request count")
 	})
 }
+
+func TestDNSMakeTransfer(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	const templateID = "testing-dns-transfer"
+
+	for _, requestType := range []DNSRequestType{AXFR, IXFR} {
+		request := &Request{
+			RequestType: DNSRequestTypeHolder{DNSRequestType: requestType},
+			Class:       "INET",
+			ID:          templateID,
+			Name:        "{{FQDN}}",
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		err := request.Compile(executerOpts)
+		require.Nil(t, err, "could not compile dns request")
+		require.True(t, request.isTransfer(), "could not get zone transfer request")
+
+		req, err := request.Make("zonetransfer.me", map[string]interface{}{"FQDN": "zonetransfer.me"})
+		require.Nil(t, err, "could not make dns request")
+		require.Equal(t, "zonetransfer.me.", req.Question[0].Name, "could not get correct dns question")
+		require.Equal(t, request.question, req.Question[0].Qtype, "could not get correct dns question type")
+	}
+}
@@ -39,6 +39,10 @@ const (
 	ANY
 	// name:SRV
 	SRV
+	// name:AXFR
+	AXFR
+	// name:IXFR
+	IXFR
 	limit
 )
 
@@ -57,6 +61,8 @@ var DNSRequestTypeMapping = map[DNSRequestType]string{
 	TLSA:  "TLSA",
 	ANY:   "ANY",
 	SRV:   "SRV",
+	AXFR:  "AXFR",
+	IXFR:  "IXFR",
 }
 
 // GetSupportedDNSRequestTypes returns list of supported types
@@ -38,13 +38,31 @@ func Init(options *types.Options) error {
 		resolvers = options.InternalResolversList
 	}
 	var err error
-	normalClient, err = retryabledns.New(resolvers, 1)
+	normalClient, err = retryabledns.New(NormalizeResolvers(resolvers), 1)
 	if err != nil {
 		return errors.Wrap(err, "could not create dns client")
 	}
 	return nil
 }
 
+// NormalizeResolvers converts resolvers with https:// and tls:// schemes to
+// DNS-over-HTTPS and DNS-over-TLS resolvers of the dns client.
+func NormalizeResolvers(resolvers []string) []string {
+	normalized := make([]string, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		switch {
+		case strings.HasPrefix(resolver, "https://"):
+			resolver = "doh:" + resolver
+		case strings.HasPrefix(resolver, "tls://"):
+			resolver = "dot:" + strings.TrimSuffix(strings.TrimPrefix(resolver, "tls://"), "/")
+		case strings.HasPrefix(resolver, "tcp://"), strings.HasPrefix(resolver, "udp://"):
+			resolver = strings.Replace(resolver, "://", ":", 1)
+		}
+		normalized = append(normalized, resolver)
+	}
+	return normalized
+}
+
 // Configuration contains the custom configuration options for a client
 type Configuration struct {
 	// Retries contains the retries for the dns client
@@ -83,7 +101,7 @@ func Get(options *types.Options, configuration *Configuration) (*retryabledns.Cl
 	} else if len(configuration.Resolvers) > 0 {
 		resolvers = configuration.Resolvers
 	}
-	client, err := retryabledns.New(resolvers, configuration.Retries)
+	client, err := retryabledns.New(NormalizeResolvers(resolvers), configuration.Retries)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create dns client")
 	}
@@ -0,0 +1,22 @@
+package dnsclientpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeResolvers(t *testing.T) {
+	resolvers := NormalizeResolvers([]string{
+		"1.1.1.1:53",
+		"tcp://8.8.8.8:53",
+		"https://dns.google/dns-query",
+		"tls://1.1.1.1:853/",
+	})
+	require.Equal(t, []string{
+		"1.1.1.1:53",
+		"tcp:8.8.8.8:53",
+		"doh:https://dns.google/dns-query",
+		"dot:1.1.1.1:853",
+	}, resolvers, "could not normalize resolvers")
+}
@@ -58,6 +58,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 	switch extractor.GetType() {
 	case extractors.RegexExtractor:
 		return extractor.ExtractRegex(types.ToString(item))
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(types.ToString(item))
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
@@ -153,7 +153,7 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 	request.options.RateLimitTake()
 
 	// Send the request to the target servers
-	response, err := dnsClient.Do(compiledRequest)
+	response, err := request.send(input, dnsClient, compiledRequest)
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, domain, request.Type().String(), err)
 		request.options.Progress.IncrementFailedRequestsBy(1)
@@ -169,7 +169,7 @@ func (request *Request) execute(input *contextargs.Context, domain string, metad
 
 	// perform trace if necessary
 	var traceData *retryabledns.TraceData
-	if request.Trace {
+	if request.Trace && !request.options.TrafficArchive.Replaying() {
 		traceData, err = request.dnsClient.Trace(domain, request.question, request.TraceMaxRecursion)
 		if err != nil {
 			request.options.Output.Request(request.options.TemplatePath, domain, "dns", err)
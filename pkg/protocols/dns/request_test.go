@@ -2,8 +2,11 @@ package dns
 
 import (
 	"context"
+	"net"
+	"sync"
 	"testing"
 
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/require"
 
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
@@ -69,3 +72,65 @@ func TestDNSExecuteWithResults(t *testing.T) {
 	finalEvent = nil
 	// Note: changing url to domain is responsible at tmplexec package and is implemented there
 }
+
+func TestDNSTransferWithResults(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	const templateID = "testing-dns-transfer"
+
+	// serve the zone over tcp, the records are split in two envelopes
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	var mu sync.Mutex
+	var questions []uint16
+	server := &dns.Server{Listener: listener, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		mu.Lock()
+		questions = append(questions, r.Question[0].Qtype)
+		mu.Unlock()
+		soa, _ := dns.NewRR("example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 3600 1209600 3600")
+		record, _ := dns.NewRR("internal.example.com. 3600 IN A 10.0.0.1")
+		envelopes := make(chan *dns.Envelope, 2)
+		envelopes <- &dns.Envelope{RR: []dns.RR{soa, record}}
+		envelopes <- &dns.Envelope{RR: []dns.RR{soa}}
+		close(envelopes)
+		_ = new(dns.Transfer).Out(w, r, envelopes)
+		w.Hijack()
+	})}
+	go func() { _ = server.ActivateAndServe() }()
+	defer func() { _ = server.Shutdown() }()
+
+	for _, requestType := range []DNSRequestType{AXFR, IXFR} {
+		request := &Request{
+			RequestType: DNSRequestTypeHolder{DNSRequestType: requestType},
+			Class:       "INET",
+			ID:          templateID,
+			Name:        "{{FQDN}}",
+			Resolvers:   []string{"tcp:" + listener.Addr().String()},
+			Operators: operators.Operators{
+				Matchers: []*matchers.Matcher{{
+					Part:  "raw",
+					Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+					Words: []string{"internal.example.com."},
+				}},
+			},
+		}
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		require.Nil(t, request.Compile(executerOpts), "could not compile dns request")
+
+		var finalEvent *output.InternalWrappedEvent
+		ctxArgs := contextargs.NewWithInput(context.Background(), "example.com")
+		err := request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			finalEvent = event
+		})
+		require.Nil(t, err, "could not transfer zone with %s", requestType)
+		require.NotNil(t, finalEvent, "could not get event output from request")
+		require.Len(t, finalEvent.Results, 1, "could not match transferred record with %s", requestType)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []uint16{dns.TypeAXFR, dns.TypeIXFR}, questions, "could not send transfer questions")
+}
@@ -0,0 +1,67 @@
+package dns
+
+import (
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
+	"github.com/projectdiscovery/retryabledns"
+)
+
+// trafficKey returns the traffic archive key for requests sent for input
+func (request *Request) trafficKey(input *contextargs.Context) traffic.Key {
+	return traffic.Key{Protocol: request.Type().String(), TemplateID: request.options.TemplateID, Target: input.MetaInput.Input}
+}
+
+// trafficRequest returns the packed message without its random id
+// so that the same question always has the same fingerprint.
+func trafficRequest(msg *dns.Msg) []byte {
+	normalized := msg.Copy()
+	normalized.Id = 0
+	packed, _ := normalized.Pack()
+	return packed
+}
+
+// send sends the dns message using the client or replays its
+// response from the traffic archive.
+func (request *Request) send(input *contextargs.Context, client *retryabledns.Client, msg *dns.Msg) (*dns.Msg, error) {
+	archive := request.options.TrafficArchive
+	if archive.Replaying() {
+		exchange, err := archive.Replay(request.trafficKey(input), trafficRequest(msg))
+		if err != nil {
+			return nil, err
+		}
+		if err := exchange.Err(); err != nil {
+			return nil, err
+		}
+		response := new(dns.Msg)
+		if err := response.Unpack(exchange.Response); err != nil {
+			return nil, errors.Wrap(err, "could not unpack recorded dns response")
+		}
+		response.Id = msg.Id
+		return response, nil
+	}
+
+	var response *dns.Msg
+	var err error
+	endCall := telemetry.StartCall(input.Context(), request.options.TemplateID, request.Type().String(), input.MetaInput.Input)
+	if request.isTransfer() {
+		response, err = request.transfer(input.Context(), client, msg)
+	} else {
+		response, err = client.Do(msg)
+	}
+	endCall(err)
+	if archive.Recording() {
+		var packed []byte
+		if response != nil {
+			packed, _ = response.Pack()
+		}
+		if recordErr := archive.Record(request.trafficKey(input), trafficRequest(msg), packed, err); recordErr != nil {
+			gologger.Warning().Msgf("[%s] Could not record response in traffic archive: %s\n", request.options.TemplateID, recordErr)
+		}
+	}
+	return response, err
+}
@@ -0,0 +1,127 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	"github.com/projectdiscovery/retryabledns"
+	stringsutil "github.com/projectdiscovery/utils/strings"
+)
+
+// isTransfer returns true if the request is a zone transfer
+func (request *Request) isTransfer() bool {
+	return request.question == dns.TypeAXFR || request.question == dns.TypeIXFR
+}
+
+// makeTransfer returns the zone transfer message for the zone. IXFR
+// requests use the serial 0 so that servers reply with the full zone.
+func makeTransfer(zone string, question uint16) *dns.Msg {
+	msg := new(dns.Msg)
+	if question == dns.TypeIXFR {
+		msg.SetIxfr(zone, 0, ".", ".")
+	} else {
+		msg.SetAxfr(zone)
+	}
+	return msg
+}
+
+// transferServers returns the addresses of the servers to attempt the zone
+// transfer with, the resolvers of the template or the nameservers of the zone.
+func (request *Request) transferServers(client *retryabledns.Client, zone string) []string {
+	var servers []string
+	for _, resolver := range request.Resolvers {
+		// zone transfers are only supported over plain tcp
+		if expressions.ContainsUnresolvedVariables(resolver) != nil || stringsutil.HasPrefixAny(resolver, "https://", "tls://", "doh:", "dot:") {
+			continue
+		}
+		resolver = stringsutil.TrimPrefixAny(resolver, "tcp://", "udp://", "tcp:", "udp:")
+		if _, _, err := net.SplitHostPort(resolver); err != nil {
+			resolver = net.JoinHostPort(resolver, "53")
+		}
+		servers = append(servers, resolver)
+	}
+	if len(servers) > 0 {
+		return servers
+	}
+
+	nsData, err := client.NS(zone)
+	if err != nil {
+		return nil
+	}
+	for _, ns := range nsData.NS {
+		aData, err := client.A(ns)
+		if err != nil {
+			continue
+		}
+		for _, ip := range aData.A {
+			servers = append(servers, net.JoinHostPort(ip, "53"))
+		}
+	}
+	return servers
+}
+
+// transfer attempts the zone transfer of msg with the servers of the zone and
+// returns a message with the full record set of the first successful one.
+//
+// Servers are dialed with the shared dialer so that the proxy and the
+// network policy of the scan apply to zone transfers.
+func (request *Request) transfer(ctx context.Context, client *retryabledns.Client, msg *dns.Msg) (*dns.Msg, error) {
+	if len(msg.Question) == 0 {
+		return nil, errors.New("no zone to transfer")
+	}
+	dialer := protocolstate.GetDialer()
+	if dialer == nil {
+		return nil, errors.New("dialer not initialized")
+	}
+	zone := strings.TrimSuffix(msg.Question[0].Name, ".")
+	servers := request.transferServers(client, zone)
+	if len(servers) == 0 {
+		return nil, errors.Errorf("could not find nameservers of %s", zone)
+	}
+
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	var lastErr error
+	for _, server := range servers {
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		conn, err := dialer.Dial(dialCtx, "tcp", server)
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		transfer := &dns.Transfer{Conn: &dns.Conn{Conn: conn}, ReadTimeout: timeout, WriteTimeout: timeout}
+		envelopes, err := transfer.In(msg, server)
+		if err != nil {
+			_ = conn.Close()
+			lastErr = err
+			continue
+		}
+		var records []dns.RR
+		for envelope := range envelopes {
+			if envelope.Error != nil {
+				err = envelope.Error
+				continue
+			}
+			records = append(records, envelope.RR...)
+		}
+		if err != nil || len(records) == 0 {
+			lastErr = err
+			continue
+		}
+		response := new(dns.Msg)
+		response.SetReply(msg)
+		response.Answer = records
+		return response, nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no records returned")
+	}
+	return nil, errors.Wrapf(lastErr, "could not transfer zone %s", zone)
+}
@@ -0,0 +1,230 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mholt/archiver"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// archivePathSeparator separates the path of an archive from the path of a file inside it
+const archivePathSeparator = "!/"
+
+// defaultArchiveDepth is the default maximum depth of nested archives
+const defaultArchiveDepth = 5
+
+// zipExtensions contains the extensions of archive formats based on zip
+var zipExtensions = []string{".zip", ".jar", ".war", ".ear", ".apk", ".aar", ".ipa", ".whl", ".nupkg", ".xpi", ".vsix"}
+
+var errArchiveMaxSize = errors.New("archive max size exceeded")
+
+// archiveFormat returns the archive reader or decompressor for the extension of name
+func archiveFormat(name string) interface{} {
+	if sliceutil.Contains(zipExtensions, strings.ToLower(filepath.Ext(name))) {
+		return archiver.NewZip()
+	}
+	format, _ := archiver.ByExtension(name)
+	return format
+}
+
+// isArchive returns true if the file at path is an archive to walk.
+// Zip based formats other than zip are only walked when archives are enabled.
+func (request *Request) isArchive(path string) bool {
+	if format, _ := archiver.ByExtension(path); format != nil {
+		return true
+	}
+	return request.Archive && archiveFormat(path) != nil
+}
+
+// archiveWalker walks archives and the archives nested in them reporting
+// every file with its virtual path, e.g. outer.zip!/inner.jar!/config.properties
+type archiveWalker struct {
+	request  *Request
+	callback func(reader io.Reader, virtualPath string, size int64)
+	// remaining is the number of bytes which can still be extracted, -1 if unlimited
+	remaining int64
+}
+
+// newArchiveWalker returns a walker reporting the files of archives to callback
+func (request *Request) newArchiveWalker(callback func(reader io.Reader, virtualPath string, size int64)) *archiveWalker {
+	return &archiveWalker{request: request, callback: callback, remaining: request.archiveMaxSize}
+}
+
+// walk walks the archive named name read from reader at the nesting depth
+func (w *archiveWalker) walk(reader io.Reader, size int64, name, virtualPath string, depth int) error {
+	switch format := archiveFormat(name).(type) {
+	case archiver.Reader:
+		return w.walkReader(format, reader, size, virtualPath, depth)
+	case archiver.Decompressor:
+		return w.decompress(format, reader, strings.TrimSuffix(name, filepath.Ext(name)), virtualPath, depth)
+	}
+	return errors.Errorf("unsupported archive %s", virtualPath)
+}
+
+// walkReader walks the files of a multi-file archive
+func (w *archiveWalker) walkReader(archive archiver.Reader, reader io.Reader, size int64, virtualPath string, depth int) error {
+	if _, ok := archive.(*archiver.Zip); ok {
+		if _, ok := reader.(io.ReaderAt); !ok {
+			// zip archives are read from their central directory at the end of the archive
+			data, err := w.readAll(reader)
+			if err != nil {
+				return err
+			}
+			reader, size = bytes.NewReader(data), int64(len(data))
+		}
+	}
+	if err := archive.Open(reader, size); err != nil {
+		return errors.Wrapf(err, "could not open archive %s", virtualPath)
+	}
+	defer archive.Close()
+
+	for {
+		file, err := archive.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not read archive %s", virtualPath)
+		}
+		err = w.walkFile(file, virtualPath, depth)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// walkFile reports a file of an archive or walks it if it is a nested archive
+func (w *archiveWalker) walkFile(file archiver.File, virtualPath string, depth int) error {
+	if !file.Mode().IsRegular() {
+		return nil
+	}
+	if w.remaining == 0 {
+		return errArchiveMaxSize
+	}
+	name := archiveFileName(file)
+	filePath := virtualPath + archivePathSeparator + name
+	// whiteout files mark deleted files in container image layers
+	if strings.HasPrefix(path.Base(name), ".wh.") {
+		return nil
+	}
+
+	reader := bufio.NewReader(file)
+	archiveName := name
+	if archiveFormat(name) == nil {
+		archiveName = sniffArchive(reader, name)
+	}
+	if archiveName != "" {
+		if depth >= w.request.archiveDepth {
+			gologger.Verbose().Msgf("[%s] Skipping nested archive %s: exceeded archive depth\n", w.request.options.TemplateID, filePath)
+			return nil
+		}
+		if err := w.walk(reader, file.Size(), archiveName, filePath, depth+1); err != nil {
+			if errors.Is(err, errArchiveMaxSize) {
+				return err
+			}
+			gologger.Verbose().Msgf("[%s] Could not walk nested archive %s: %s\n", w.request.options.TemplateID, filePath, err)
+		}
+		return nil
+	}
+
+	if !w.request.validatePath("/", name, true) {
+		return nil
+	}
+	w.callback(&limitedReader{reader: reader, walker: w}, filePath, file.Size())
+	return nil
+}
+
+// decompress walks the data of a single compressed file. Compressed tarballs
+// without a tar extension, such as container image layers, are walked as tarballs.
+func (w *archiveWalker) decompress(decompressor archiver.Decompressor, reader io.Reader, name, virtualPath string, depth int) error {
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close()
+	go func() {
+		_ = pipeWriter.CloseWithError(decompressor.Decompress(reader, pipeWriter))
+	}()
+
+	buffered := bufio.NewReader(pipeReader)
+	archiveName := name
+	if archiveFormat(name) == nil {
+		archiveName = sniffArchive(buffered, name)
+	}
+	if archiveName != "" {
+		return w.walk(buffered, -1, archiveName, virtualPath, depth)
+	}
+	w.callback(&limitedReader{reader: buffered, walker: w}, virtualPath, -1)
+	return nil
+}
+
+// readAll reads the reader in memory within the remaining extraction size
+func (w *archiveWalker) readAll(reader io.Reader) ([]byte, error) {
+	if w.remaining < 0 {
+		return io.ReadAll(reader)
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, w.remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > w.remaining {
+		return nil, errArchiveMaxSize
+	}
+	return data, nil
+}
+
+// limitedReader counts the bytes extracted from archives failing once the
+// archive max size has been exceeded
+type limitedReader struct {
+	reader io.Reader
+	walker *archiveWalker
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.walker.remaining == 0 {
+		return 0, errArchiveMaxSize
+	}
+	if r.walker.remaining > 0 && int64(len(p)) > r.walker.remaining {
+		p = p[:r.walker.remaining]
+	}
+	n, err := r.reader.Read(p)
+	if r.walker.remaining > 0 {
+		r.walker.remaining -= int64(n)
+	}
+	return n, err
+}
+
+// archiveFileName returns the path of the file inside its archive
+func archiveFileName(file archiver.File) string {
+	name := file.Name()
+	switch header := file.Header.(type) {
+	case zip.FileHeader:
+		name = header.Name
+	case *tar.Header:
+		name = header.Name
+	}
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+// sniffArchive returns name with the extension of the archive format detected
+// from the content of the reader, or an empty string if it is not an archive
+func sniffArchive(reader *bufio.Reader, name string) string {
+	header, _ := reader.Peek(262)
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return name + ".zip"
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return name + ".tar"
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return name + ".gz"
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	permissionutil "github.com/projectdiscovery/utils/permission"
+)
+
+func createZip(t *testing.T, files map[string][]byte) []byte {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for name, data := range files {
+		file, err := writer.Create(name)
+		require.Nil(t, err, "could not create zip file")
+		_, _ = file.Write(data)
+	}
+	require.Nil(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func createTar(t *testing.T, compress bool, files map[string][]byte) []byte {
+	buffer := &bytes.Buffer{}
+	var output io.Writer = buffer
+	var gzipWriter *gzip.Writer
+	if compress {
+		gzipWriter = gzip.NewWriter(buffer)
+		output = gzipWriter
+	}
+	writer := tar.NewWriter(output)
+	for name, data := range files {
+		err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		require.Nil(t, err, "could not write tar header")
+		_, _ = writer.Write(data)
+	}
+	require.Nil(t, writer.Close())
+	if gzipWriter != nil {
+		require.Nil(t, gzipWriter.Close())
+	}
+	return buffer.Bytes()
+}
+
+func walkArchiveFiles(t *testing.T, request *Request, path string) map[string]string {
+	file, err := os.Open(path)
+	require.Nil(t, err, "could not open archive")
+	defer file.Close()
+	stat, _ := file.Stat()
+
+	files := make(map[string]string)
+	walker := request.newArchiveWalker(func(reader io.Reader, virtualPath string, size int64) {
+		data, _ := io.ReadAll(reader)
+		files[virtualPath] = string(data)
+	})
+	_ = walker.walk(file, stat.Size(), path, path, 1)
+	return files
+}
+
+func TestArchiveWalker(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   "testing-archive",
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	newRequest := func(depth int, maxSize string) *Request {
+		request := &Request{
+			Archive:        true,
+			ArchiveDepth:   depth,
+			ArchiveMaxSize: maxSize,
+			Extensions:     []string{"all"},
+			Operators: operators.Operators{Matchers: []*matchers.Matcher{{
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"password"},
+			}}},
+		}
+		require.Nil(t, request.Compile(executerOpts), "could not compile file request")
+		return request
+	}
+
+	tempDir := t.TempDir()
+	jar := createZip(t, map[string][]byte{"config.properties": []byte("db.password=secret")})
+	war := createZip(t, map[string][]byte{"WEB-INF/lib/inner.jar": jar, "index.jsp": []byte("<html>")})
+	outer := filepath.Join(tempDir, "outer.zip")
+	err := os.WriteFile(outer, createZip(t, map[string][]byte{"app.war": war}), permissionutil.TempFilePermission)
+	require.Nil(t, err)
+
+	// docker save image tarball with a gzip compressed layer blob
+	layer := createTar(t, true, map[string][]byte{"etc/app.env": []byte("PASSWORD=hunter2"), "etc/.wh.removed": nil})
+	image := filepath.Join(tempDir, "image.tar")
+	err = os.WriteFile(image, createTar(t, false, map[string][]byte{
+		"manifest.json":         []byte(`[{"Layers":["blobs/sha256/5f70bf18"]}]`),
+		"blobs/sha256/5f70bf18": layer,
+	}), permissionutil.TempFilePermission)
+	require.Nil(t, err)
+
+	t.Run("nested", func(t *testing.T) {
+		files := walkArchiveFiles(t, newRequest(0, ""), outer)
+		require.Equal(t, map[string]string{
+			outer + "!/app.war!/index.jsp":                                "<html>",
+			outer + "!/app.war!/WEB-INF/lib/inner.jar!/config.properties": "db.password=secret",
+		}, files)
+	})
+	t.Run("depth", func(t *testing.T) {
+		files := walkArchiveFiles(t, newRequest(2, ""), outer)
+		require.Equal(t, map[string]string{outer + "!/app.war!/index.jsp": "<html>"}, files)
+	})
+	t.Run("max-size", func(t *testing.T) {
+		files := walkArchiveFiles(t, newRequest(0, "4b"), outer)
+		for _, data := range files {
+			require.LessOrEqual(t, len(data), 4)
+		}
+	})
+	t.Run("image", func(t *testing.T) {
+		files := walkArchiveFiles(t, newRequest(0, ""), image)
+		require.Equal(t, "PASSWORD=hunter2", files[image+"!/blobs/sha256/5f70bf18!/etc/app.env"])
+		require.Contains(t, files, image+"!/manifest.json")
+		require.Len(t, files, 2, "could not skip whiteout files")
+	})
+}
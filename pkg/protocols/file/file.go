@@ -53,6 +53,28 @@ type Request struct {
 	//   elaborates archives
 	Archive bool `yaml:"archive,omitempty" json:"archive,omitempty" jsonschema:"title=enable archives,description=Process compressed archives without unpacking"`
 
+	// description: |
+	//   ArchiveDepth is the maximum depth of nested archives to extract.
+	//
+	//   Archives found inside archives (e.g. a jar inside a war inside a zip, or the
+	//   layer tarballs of a docker/OCI image tarball) are extracted recursively and
+	//   their files are reported with their virtual path, e.g. outer.zip!/inner.jar!/config.properties.
+	//   By default, nuclei extracts up to 5 levels of archives.
+	// examples:
+	//   - value: "2"
+	ArchiveDepth int `yaml:"archive-depth,omitempty" json:"archive-depth,omitempty" jsonschema:"title=maximum depth of nested archives,description=Maximum depth of nested archives to extract"`
+	archiveDepth int
+
+	// description: |
+	//   ArchiveMaxSize is the maximum size of data to extract from an archive and the archives nested in it.
+	//
+	//   By default, nuclei will extract 1 GB of content from an archive.
+	//   If set to "no" then all content will be extracted
+	// examples:
+	//   - value: "\"500Mb\""
+	ArchiveMaxSize string `yaml:"archive-max-size,omitempty" json:"archive-max-size,omitempty" jsonschema:"title=max size of data extracted from an archive,description=Maximum size of data to extract from an archive and its nested archives"`
+	archiveMaxSize int64
+
 	// description: |
 	//   enables mime types check
 	MimeType bool `yaml:"mime-type,omitempty" json:"mime-type,omitempty" jsonschema:"title=enable filtering by mime-type,description=Filter files by mime-type"`
@@ -87,10 +109,10 @@ var RequestPartDefinitions = map[string]string{
 }
 
 // defaultDenylist contains common extensions to exclude
-var defaultDenylist = []string{".3g2", ".3gp", ".arj", ".avi", ".axd", ".bmp", ".css", ".csv", ".deb", ".dll", ".doc", ".drv", ".eot", ".exe", ".flv", ".gif", ".gifv", ".h264", ".ico", ".iso", ".jar", ".jpeg", ".jpg", ".lock", ".m4a", ".m4v", ".map", ".mkv", ".mov", ".mp3", ".mp4", ".mpeg", ".mpg", ".msi", ".ogg", ".ogm", ".ogv", ".otf", ".pdf", ".pkg", ".png", ".ppt", ".psd", ".rm", ".rpm", ".svg", ".swf", ".sys", ".tif", ".tiff", ".ttf", ".vob", ".wav", ".webm", ".wmv", ".woff", ".woff2", ".xcf", ".xls", ".xlsx"}
+var defaultDenylist = []string{".3g2", ".3gp", ".arj", ".avi", ".axd", ".bmp", ".css", ".csv", ".deb", ".dll", ".doc", ".drv", ".eot", ".exe", ".flv", ".gif", ".gifv", ".h264", ".ico", ".iso", ".jpeg", ".jpg", ".lock", ".m4a", ".m4v", ".map", ".mkv", ".mov", ".mp3", ".mp4", ".mpeg", ".mpg", ".msi", ".ogg", ".ogm", ".ogv", ".otf", ".pdf", ".pkg", ".png", ".ppt", ".psd", ".rm", ".rpm", ".svg", ".swf", ".sys", ".tif", ".tiff", ".ttf", ".vob", ".wav", ".webm", ".wmv", ".woff", ".woff2", ".xcf", ".xls", ".xlsx"}
 
 // defaultArchiveDenyList contains common archive extensions to exclude
-var defaultArchiveDenyList = []string{".7z", ".apk", ".gz", ".rar", ".tar.gz", ".tar", ".zip"}
+var defaultArchiveDenyList = []string{".7z", ".apk", ".ear", ".gz", ".jar", ".rar", ".tar.gz", ".tar", ".war", ".zip"}
 
 // GetID returns the unique ID of the request if any.
 func (request *Request) GetID() string {
@@ -125,6 +147,23 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		request.maxSize = defaultMaxReadSize
 	}
 
+	switch request.ArchiveMaxSize {
+	case "":
+		request.archiveMaxSize = defaultMaxReadSize
+	case "no":
+		request.archiveMaxSize = -1
+	default:
+		archiveMaxSize, err := units.FromHumanSize(request.ArchiveMaxSize)
+		if err != nil {
+			return errors.Wrap(err, "could not parse archive max size")
+		}
+		request.archiveMaxSize = archiveMaxSize
+	}
+	request.archiveDepth = request.ArchiveDepth
+	if request.archiveDepth <= 0 {
+		request.archiveDepth = defaultArchiveDepth
+	}
+
 	request.options = options
 
 	request.extensions = make(map[string]struct{})
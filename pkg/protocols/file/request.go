@@ -5,11 +5,9 @@ import (
 	"encoding/hex"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/docker/go-units"
-	"github.com/mholt/archiver"
 	"github.com/pkg/errors"
 
 	"github.com/projectdiscovery/gologger"
@@ -58,72 +56,26 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 		wg.Add()
 		func(filePath string) {
 			defer wg.Done()
-			archiveReader, _ := archiver.ByExtension(filePath)
 			switch {
-			case archiveReader != nil:
-				switch archiveInstance := archiveReader.(type) {
-				case archiver.Walker:
-					err := archiveInstance.Walk(filePath, func(file archiver.File) error {
-						if !request.validatePath("/", file.Name(), true) {
-							return nil
-						}
-						// every new file in the compressed multi-file archive counts 1
-						request.options.Progress.AddToTotal(1)
-						archiveFileName := filepath.Join(filePath, file.Name())
-						event, fileMatches, err := request.processReader(file.ReadCloser, archiveFileName, input, file.Size(), previous)
-						if err != nil {
-							if errors.Is(err, errEmptyResult) {
-								// no matches but one file elaborated
-								request.options.Progress.IncrementRequests()
-								return nil
-							}
-							gologger.Error().Msgf("%s\n", err)
-							// error while elaborating the file
-							request.options.Progress.IncrementFailedRequestsBy(1)
-							return err
-						}
-						defer file.Close()
-						dumpResponse(event, request.options, fileMatches, filePath)
-						callback(event)
-						// file elaborated and matched
-						request.options.Progress.IncrementRequests()
-						return nil
-					})
-					if err != nil {
-						gologger.Error().Msgf("%s\n", err)
-						return
-					}
-				case archiver.Decompressor:
-					// compressed archive - contains only one file => increments the counter by 1
+			case request.isArchive(filePath):
+				file, err := os.Open(filePath)
+				if err != nil {
+					gologger.Error().Msgf("%s\n", err)
+					// error while elaborating the file
+					request.options.Progress.IncrementFailedRequestsBy(1)
+					return
+				}
+				defer file.Close()
+				fileStat, err := file.Stat()
+				if err != nil {
+					gologger.Error().Msgf("%s\n", err)
+					request.options.Progress.IncrementFailedRequestsBy(1)
+					return
+				}
+				walker := request.newArchiveWalker(func(reader io.Reader, virtualPath string, size int64) {
+					// every file in the archive and its nested archives counts 1
 					request.options.Progress.AddToTotal(1)
-					file, err := os.Open(filePath)
-					if err != nil {
-						gologger.Error().Msgf("%s\n", err)
-						// error while elaborating the file
-						request.options.Progress.IncrementFailedRequestsBy(1)
-						return
-					}
-					defer file.Close()
-					fileStat, _ := file.Stat()
-					tmpFileOut, err := os.CreateTemp("", "")
-					if err != nil {
-						gologger.Error().Msgf("%s\n", err)
-						// error while elaborating the file
-						request.options.Progress.IncrementFailedRequestsBy(1)
-						return
-					}
-					defer tmpFileOut.Close()
-					defer os.RemoveAll(tmpFileOut.Name())
-					if err := archiveInstance.Decompress(file, tmpFileOut); err != nil {
-						gologger.Error().Msgf("%s\n", err)
-						// error while elaborating the file
-						request.options.Progress.IncrementFailedRequestsBy(1)
-						return
-					}
-					_ = tmpFileOut.Sync()
-					// rewind the file
-					_, _ = tmpFileOut.Seek(0, 0)
-					event, fileMatches, err := request.processReader(tmpFileOut, filePath, input, fileStat.Size(), previous)
+					event, fileMatches, err := request.processReader(reader, virtualPath, input, size, previous)
 					if err != nil {
 						if errors.Is(err, errEmptyResult) {
 							// no matches but one file elaborated
@@ -135,10 +87,17 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 						request.options.Progress.IncrementFailedRequestsBy(1)
 						return
 					}
-					dumpResponse(event, request.options, fileMatches, filePath)
+					dumpResponse(event, request.options, fileMatches, virtualPath)
 					callback(event)
 					// file elaborated and matched
 					request.options.Progress.IncrementRequests()
+				})
+				if err := walker.walk(file, fileStat.Size(), filePath, filePath, 1); err != nil {
+					if errors.Is(err, errArchiveMaxSize) {
+						gologger.Verbose().Msgf("[%s] Stopped extracting %s: %s\n", request.options.TemplateID, filePath, err)
+						return
+					}
+					gologger.Error().Msgf("%s\n", err)
 				}
 			default:
 				// normal file - increments the counter by 1
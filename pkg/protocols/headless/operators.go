@@ -72,6 +72,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 	switch extractor.GetType() {
 	case extractors.RegexExtractor:
 		return extractor.ExtractRegex(itemStr)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(itemStr)
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
 	case extractors.DSLExtractor:
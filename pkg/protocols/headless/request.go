@@ -214,6 +214,7 @@ func (request *Request) executeRequestWithPayloads(input *contextargs.Context, p
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			ResultCallback: interactsh.ResultCallbackFromContext(input.Context()),
 		})
 	}
 	if len(page.InteractshURLs) > 0 {
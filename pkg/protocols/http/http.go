@@ -195,6 +195,13 @@ type Request struct {
 	//   control over the request, with no normalization performed by the client.
 	Unsafe bool `yaml:"unsafe,omitempty" json:"unsafe,omitempty" jsonschema:"title=use rawhttp non-strict-rfc client,description=Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests"`
 	// description: |
+	//   HTTP3 specifies whether https requests should be sent over HTTP/3 (QUIC).
+	//
+	//   The QUIC endpoint is discovered from the Alt-Svc header of the host. Requests
+	//   to hosts without one, failing over QUIC or sent through a proxy use HTTP/1.1 or
+	//   HTTP/2 instead. The negotiated protocol is available as `protocol`.
+	HTTP3 bool `yaml:"http3,omitempty" json:"http3,omitempty" jsonschema:"title=use http3 client,description=HTTP3 specifies whether https requests should be sent over HTTP/3 (QUIC)"`
+	// description: |
 	//   Race determines if all the request have to be attempted at the same time (Race Condition)
 	//
 	//   The actual number of requests that will be sent is determined by the `race_count`  field.
@@ -281,6 +288,7 @@ var RequestPartDefinitions = map[string]string{
 	"content_length":        "HTTP Response content length",
 	"header,all_headers":    "HTTP response headers",
 	"duration":              "HTTP request time duration",
+	"protocol":              "HTTP protocol negotiated with the server",
 	"all":                   "HTTP response body + headers",
 	"cookies_from_response": "HTTP response cookies in name:value format",
 	"headers_from_response": "HTTP response headers in name:value format",
@@ -310,6 +318,7 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 			DisableKeepAlive: httputil.ShouldDisableKeepAlive(options.Options),
 		},
 		RedirectFlow: httpclientpool.DontFollowRedirect,
+		HTTP3:        request.HTTP3,
 	}
 	var customTimeout int
 	if request.Analyzer != nil && request.Analyzer.Name == "time_delay" {
@@ -349,6 +358,9 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	request.customHeaders = make(map[string]string)
 	request.httpClient = client
 	request.options = options
+	if (request.HTTP3 || options.Options.ForceAttemptHTTP3) && options.AltSvcCache == nil {
+		options.AltSvcCache = httpclientpool.NewAltSvcCache()
+	}
 	for _, option := range request.options.Options.CustomHeaders {
 		parts := strings.SplitN(option, ":", 2)
 		if len(parts) != 2 {
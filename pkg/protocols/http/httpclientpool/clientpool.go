@@ -106,6 +106,8 @@ type Configuration struct {
 	Connection *ConnectionConfiguration
 	// ResponseHeaderTimeout is the timeout for response body to be read from the server
 	ResponseHeaderTimeout time.Duration
+	// HTTP3 sends https requests over HTTP/3 (QUIC)
+	HTTP3 bool
 }
 
 func (c *Configuration) Clone() *Configuration {
@@ -146,13 +148,15 @@ func (c *Configuration) Hash() string {
 	}
 	builder.WriteString("r")
 	builder.WriteString(strconv.FormatInt(int64(c.ResponseHeaderTimeout.Seconds()), 10))
+	builder.WriteString("h")
+	builder.WriteString(strconv.FormatBool(c.HTTP3))
 	hash := builder.String()
 	return hash
 }
 
 // HasStandardOptions checks whether the configuration requires custom settings
 func (c *Configuration) HasStandardOptions() bool {
-	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && c.DisableCookie && c.Connection == nil && !c.NoTimeout && c.ResponseHeaderTimeout == 0
+	return c.Threads == 0 && c.MaxRedirects == 0 && c.RedirectFlow == DontFollowRedirect && c.DisableCookie && c.Connection == nil && !c.NoTimeout && c.ResponseHeaderTimeout == 0 && !c.HTTP3
 }
 
 // GetRawHTTP returns the rawhttp request client
@@ -319,8 +323,14 @@ func wrappedGet(options *types.Options, configuration *Configuration) (*retryabl
 		}
 	}
 
+	var roundTripper http.RoundTripper = transport
+	// quic connections can't be sent through the http or socks proxies
+	if (configuration.HTTP3 || options.ForceAttemptHTTP3) && options.AliveHttpProxy == "" && options.AliveSocksProxy == "" {
+		roundTripper = newHTTP3Transport(tlsConfig, transport, options.GetTimeouts().HttpTimeout)
+	}
+
 	httpclient := &http.Client{
-		Transport:     transport,
+		Transport:     roundTripper,
 		CheckRedirect: makeCheckRedirectFunc(redirectFlow, maxRedirects),
 	}
 	if !configuration.NoTimeout {
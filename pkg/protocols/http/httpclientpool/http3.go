@@ -0,0 +1,236 @@
+package httpclientpool
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
+	iputil "github.com/projectdiscovery/utils/ip"
+	mapsutil "github.com/projectdiscovery/utils/maps"
+)
+
+// AltSvcCache contains the HTTP/3 endpoints advertised by hosts through the
+// Alt-Svc header. Hosts not advertising HTTP/3 are cached with an empty value.
+type AltSvcCache = mapsutil.SyncLockMap[string, string]
+
+// NewAltSvcCache returns an empty Alt-Svc cache
+func NewAltSvcCache() *AltSvcCache {
+	return &AltSvcCache{Map: make(mapsutil.Map[string, string])}
+}
+
+type altSvcCacheKey struct{}
+
+// WithAltSvcCache returns a context using the Alt-Svc cache for HTTP/3 discovery
+// of requests, so that it is scoped to the executor sending them instead of the
+// pooled client.
+func WithAltSvcCache(ctx context.Context, cache *AltSvcCache) context.Context {
+	return context.WithValue(ctx, altSvcCacheKey{}, cache)
+}
+
+type hostRateLimiterKey struct{}
+
+// WithHostRateLimiter returns a context applying the per-host rate limits
+// of limiter to the requests sent to discover HTTP/3 endpoints.
+func WithHostRateLimiter(ctx context.Context, limiter *hostratelimit.Limiter) context.Context {
+	return context.WithValue(ctx, hostRateLimiterKey{}, limiter)
+}
+
+// http3Transport sends https requests over HTTP/3 (QUIC) and plain http requests
+// with the fallback transport.
+//
+// The HTTP/3 endpoint of a host is discovered from the Alt-Svc header of the
+// host, requested once over the fallback transport. Requests to hosts not
+// advertising HTTP/3 or whose QUIC connection fails are sent with the fallback
+// transport.
+type http3Transport struct {
+	h3       *http3.RoundTripper
+	fallback http.RoundTripper
+	// cache is used for requests without an Alt-Svc cache in their context
+	cache *AltSvcCache
+
+	quicOnce      sync.Once
+	quicTransport *quic.Transport
+	quicErr       error
+}
+
+// newHTTP3Transport returns a HTTP/3 transport using the tls configuration
+func newHTTP3Transport(tlsConfig *tls.Config, fallback http.RoundTripper, timeout time.Duration) *http3Transport {
+	h3TLSConfig := tlsConfig.Clone()
+	// http3 doesn't support tls versions prior to 1.3
+	h3TLSConfig.MinVersion = tls.VersionTLS13
+	h3TLSConfig.Renegotiation = tls.RenegotiateNever
+
+	transport := &http3Transport{
+		fallback: fallback,
+		cache:    NewAltSvcCache(),
+	}
+	transport.h3 = &http3.RoundTripper{
+		TLSClientConfig: h3TLSConfig,
+		QuicConfig: &quic.Config{
+			HandshakeIdleTimeout: timeout,
+			MaxIdleTimeout:       timeout,
+		},
+		Dial: transport.dial,
+	}
+	return transport
+}
+
+// RoundTrip sends the request over HTTP/3 if it is a https request to a
+// host advertising HTTP/3, falling back to the fallback transport on errors
+// of the QUIC connection.
+func (t *http3Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return t.fallback.RoundTrip(req)
+	}
+	authority := t.discover(req)
+	if authority == "" {
+		return t.fallback.RoundTrip(req)
+	}
+	h3Req := req.Clone(req.Context())
+	if authority != req.URL.Host {
+		if h3Req.Host == "" {
+			h3Req.Host = req.URL.Host
+		}
+		h3Req.URL.Host = authority
+	}
+	resp, err := t.h3.RoundTrip(h3Req)
+	if err == nil || req.Context().Err() != nil {
+		return resp, err
+	}
+	// the request can only be sent again if its body can be replayed
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, err
+		}
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	// later requests to the host are sent with the fallback transport directly
+	_ = t.cacheFor(req).Set(req.URL.Host, "")
+	return t.fallback.RoundTrip(req)
+}
+
+// dial dials a QUIC connection to addr resolving its host with the fastdialer
+// so that the resolvers and the network policy of the scan are applied.
+func (t *http3Transport) dial(ctx context.Context, addr string, tlsConfig *tls.Config, config *quic.Config) (quic.EarlyConnection, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips := []string{host}
+	if !iputil.IsIP(host) {
+		data, err := protocolstate.GetDialer().GetDNSData(host)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(append([]string{}, data.A...), data.AAAA...)
+	}
+	var udpAddr *net.UDPAddr
+	for _, ip := range ips {
+		if protocolstate.NetworkPolicy != nil && !protocolstate.NetworkPolicy.Validate(ip) {
+			continue
+		}
+		if udpAddr, err = net.ResolveUDPAddr("udp", net.JoinHostPort(ip, port)); err == nil {
+			break
+		}
+	}
+	if udpAddr == nil {
+		return nil, errors.Errorf("no allowed address found for %s", host)
+	}
+
+	// all the connections share a single udp socket
+	t.quicOnce.Do(func() {
+		var conn *net.UDPConn
+		if conn, t.quicErr = net.ListenUDP("udp", nil); t.quicErr == nil {
+			t.quicTransport = &quic.Transport{Conn: conn}
+		}
+	})
+	if t.quicErr != nil {
+		return nil, errors.Wrap(t.quicErr, "could not create quic transport")
+	}
+	return t.quicTransport.DialEarly(ctx, udpAddr, tlsConfig, config)
+}
+
+// cacheFor returns the Alt-Svc cache of the request or the cache of the transport
+func (t *http3Transport) cacheFor(req *http.Request) *AltSvcCache {
+	if cache, ok := req.Context().Value(altSvcCacheKey{}).(*AltSvcCache); ok && cache != nil {
+		return cache
+	}
+	return t.cache
+}
+
+// discover returns the HTTP/3 endpoint advertised by the host of the request
+// or an empty string if it advertises none.
+func (t *http3Transport) discover(req *http.Request) string {
+	host := req.URL.Host
+	cache := t.cacheFor(req)
+	if authority, ok := cache.Get(host); ok {
+		return authority
+	}
+
+	// the probe is an additional request subject to the per-host rate limits
+	limiter, _ := req.Context().Value(hostRateLimiterKey{}).(*hostratelimit.Limiter)
+	if err := limiter.Take(req.Context(), hostratelimit.HostFromURL(req.URL.String())); err != nil {
+		return ""
+	}
+
+	var authority string
+	probe, err := http.NewRequestWithContext(req.Context(), http.MethodHead, req.URL.Scheme+"://"+host+"/", nil)
+	if err == nil {
+		if req.Host != "" {
+			probe.Host = req.Host
+		}
+		if resp, err := t.fallback.RoundTrip(probe); err == nil {
+			_ = resp.Body.Close()
+			authority = ParseAltSvc(host, resp.Header.Get("Alt-Svc"))
+		}
+	}
+	_ = cache.Set(host, authority)
+	return authority
+}
+
+// ParseAltSvc returns the HTTP/3 endpoint of host from the value of an
+// Alt-Svc header, or an empty string if no HTTP/3 endpoint is advertised.
+//
+// Alternative services without a hostname, such as h3=":443", are resolved
+// against the hostname of host.
+func ParseAltSvc(host, value string) string {
+	for _, service := range strings.Split(value, ",") {
+		parameters := strings.Split(strings.TrimSpace(service), ";")
+		protocol, authority, ok := strings.Cut(strings.TrimSpace(parameters[0]), "=")
+		if !ok || protocol != "h3" {
+			continue
+		}
+		authority, err := strconv.Unquote(authority)
+		if err != nil {
+			continue
+		}
+		altHost, altPort, err := net.SplitHostPort(authority)
+		if err != nil || altPort == "" {
+			continue
+		}
+		if altHost == "" {
+			altHost = host
+			if hostname, _, err := net.SplitHostPort(host); err == nil {
+				altHost = hostname
+			}
+		}
+		return net.JoinHostPort(altHost, altPort)
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package httpclientpool
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAltSvc(t *testing.T) {
+	tests := []struct {
+		host     string
+		value    string
+		expected string
+	}{
+		{host: "example.com", value: `h3=":443"; ma=86400`, expected: "example.com:443"},
+		{host: "example.com:8443", value: `h3-29=":443", h3=":8443"; ma=86400`, expected: "example.com:8443"},
+		{host: "example.com", value: `h2="alt.example.com:443", h3="alt.example.com:4433"`, expected: "alt.example.com:4433"},
+		{host: "[::1]:443", value: `h3=":443"`, expected: "[::1]:443"},
+		{host: "example.com", value: `h2=":443"`, expected: ""},
+		{host: "example.com", value: "clear", expected: ""},
+		{host: "example.com", value: "", expected: ""},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, ParseAltSvc(test.host, test.value), "could not parse alt-svc %q", test.value)
+	}
+}
+
+func TestHTTP3ConfigurationHash(t *testing.T) {
+	configuration := &Configuration{HTTP3: true}
+	require.False(t, configuration.HasStandardOptions(), "http3 configuration should not use the standard client")
+	require.NotEqual(t, (&Configuration{}).Hash(), configuration.Hash(), "http3 configuration should not share the client")
+}
+
+type altSvcRoundTripper struct {
+	altSvc   string
+	probes   atomic.Int32
+	requests atomic.Int32
+}
+
+func (rt *altSvcRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodHead {
+		rt.probes.Add(1)
+	} else {
+		rt.requests.Add(1)
+	}
+	header := make(http.Header)
+	header.Set("Alt-Svc", rt.altSvc)
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestAltSvcCacheScope(t *testing.T) {
+	fallback := &altSvcRoundTripper{altSvc: `h3=":8443"`}
+	transport := &http3Transport{fallback: fallback, cache: NewAltSvcCache()}
+
+	discover := func(cache *AltSvcCache) string {
+		ctx := context.Background()
+		if cache != nil {
+			ctx = WithAltSvcCache(ctx, cache)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/", nil)
+		require.Nil(t, err)
+		return transport.discover(req)
+	}
+
+	first, second := NewAltSvcCache(), NewAltSvcCache()
+	require.Equal(t, "example.com:8443", discover(first))
+	require.Equal(t, "example.com:8443", discover(first))
+	require.Equal(t, int32(1), fallback.probes.Load(), "alt-svc was not cached for the executor")
+
+	require.Equal(t, "example.com:8443", discover(second))
+	require.Equal(t, int32(2), fallback.probes.Load(), "alt-svc cache was shared between executors")
+	_, ok := transport.cache.Get("example.com")
+	require.False(t, ok, "executor discovery was stored in the transport cache")
+
+	require.Equal(t, "example.com:8443", discover(nil))
+	require.Equal(t, "example.com:8443", discover(nil))
+	require.Equal(t, int32(3), fallback.probes.Load(), "alt-svc was not cached without executor cache")
+}
+
+func TestHTTP3Fallback(t *testing.T) {
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://127.0.0.1/", strings.NewReader("body"))
+		require.Nil(t, err)
+		return req
+	}
+
+	// hosts without an http3 endpoint are not attempted over quic
+	fallback := &altSvcRoundTripper{}
+	transport := &http3Transport{fallback: fallback, cache: NewAltSvcCache()}
+	for i := 0; i < 2; i++ {
+		resp, err := transport.RoundTrip(newRequest())
+		require.Nil(t, err, "could not send request without http3 endpoint")
+		_ = resp.Body.Close()
+	}
+	require.Equal(t, int32(1), fallback.probes.Load())
+	require.Equal(t, int32(2), fallback.requests.Load(), "could not send requests with fallback transport")
+
+	// requests failing over quic are sent again with the fallback transport
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.LocalAddr().String())
+
+	fallback = &altSvcRoundTripper{altSvc: `h3=":` + port + `"`}
+	transport = newHTTP3Transport(&tls.Config{}, fallback, 200*time.Millisecond)
+	resp, err := transport.RoundTrip(newRequest())
+	require.Nil(t, err, "could not fall back after quic error")
+	_ = resp.Body.Close()
+	require.Equal(t, int32(1), fallback.requests.Load())
+	authority, _ := transport.cache.Get("127.0.0.1")
+	require.Empty(t, authority, "host failing over quic was not cached")
+}
@@ -68,6 +68,8 @@ func (request *Request) Extract(data map[string]interface{}, extractor *extracto
 	switch extractor.GetType() {
 	case extractors.RegexExtractor:
 		return extractor.ExtractRegex(item)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(item)
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
 	case extractors.XPathExtractor:
@@ -107,7 +109,7 @@ func (request *Request) getMatchPart(part string, data output.InternalEvent) (st
 
 // responseToDSLMap converts an HTTP response to a map for use in DSL matching
 func (request *Request) responseToDSLMap(resp *http.Response, host, matched, rawReq, rawResp, body, headers string, duration time.Duration, extra map[string]interface{}) output.InternalEvent {
-	data := make(output.InternalEvent, 12+len(extra)+len(resp.Header)+len(resp.Cookies()))
+	data := make(output.InternalEvent, 13+len(extra)+len(resp.Header)+len(resp.Cookies()))
 	for k, v := range extra {
 		data[k] = v
 	}
@@ -124,6 +126,7 @@ func (request *Request) responseToDSLMap(resp *http.Response, host, matched, raw
 	request.setHashOrDefault(data, "request", rawReq)
 	request.setHashOrDefault(data, "response", rawResp)
 	data["status_code"] = resp.StatusCode
+	data["protocol"] = resp.Proto
 	request.setHashOrDefault(data, "body", body)
 	request.setHashOrDefault(data, "all_headers", headers)
 	request.setHashOrDefault(data, "header", headers)
@@ -22,19 +22,23 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz/analyzers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/expressions"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/protocolstate"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httpclientpool"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/httputils"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/signer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/http/signerpool"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types/nucleierr"
 	"github.com/projectdiscovery/rawhttp"
@@ -526,6 +530,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 						Operators:      request.CompiledOperators,
 						MatchFunc:      request.Match,
 						ExtractFunc:    request.Extract,
+						ResultCallback: interactsh.ResultCallbackFromContext(input.Context()),
 					}
 					allOASTUrls := httputils.GetInteractshURLSFromEvent(event.InternalEvent)
 					allOASTUrls = append(allOASTUrls, generatedHttpRequest.interactshURLs...)
@@ -601,6 +606,16 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 
 const drainReqSize = int64(8 * unitutils.Kilo)
 
+// observeHostRateLimit adapts the rate limit of the host to the outcome of a request
+func (request *Request) observeHostRateLimit(host string, signal hostratelimit.Signal) {
+	if !request.options.HostRateLimiter.Observe(host, signal) {
+		return
+	}
+	if throttled, ok := request.options.Progress.(progress.ThrottledProgress); ok {
+		throttled.IncrementThrottled()
+	}
+}
+
 // executeRequest executes the actual generated request and returns error if occurred
 func (request *Request) executeRequest(input *contextargs.Context, generatedRequest *generatedRequest, previousEvent output.InternalEvent, hasInteractMatchers bool, processEvent protocols.OutputEventCallback, requestCount int) (err error) {
 	// Check if hosts keep erroring
@@ -694,9 +709,28 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		generatedRequest.ApplyAuth(request.options.AuthProvider)
 	}
 
+	// if a traffic archive is being replayed the response is served from
+	// the archive instead of being sent over the network
+	replayed := request.options.TrafficArchive.Replaying()
+
+	// per host rate limits are applied once the final url of the request is known.
+	// race requests are not limited since they are released together on purpose.
+	rateLimitHost := hostratelimit.HostFromURL(generatedRequest.URL())
+	if !replayed && !generatedRequest.original.Race {
+		waitStart := time.Now()
+		if err := request.options.HostRateLimiter.Take(input.Context(), rateLimitHost); err != nil {
+			return err
+		}
+		telemetry.ObserveRateLimitWait("host", time.Since(waitStart))
+	}
+
 	var formedURL string
 	var hostname string
 	timeStart := time.Now()
+
+	if replayed {
+		resp, err = request.replayResponse(input, dumpedRequest)
+	}
 	if generatedRequest.original.Pipeline {
 		// if request is a pipeline request, use the pipelined client
 		if generatedRequest.rawRequest != nil {
@@ -704,8 +738,10 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 			if parsed, parseErr := urlutil.ParseURL(formedURL, true); parseErr == nil {
 				hostname = parsed.Host
 			}
-			resp, err = generatedRequest.pipelinedClient.DoRaw(generatedRequest.rawRequest.Method, input.MetaInput.Input, generatedRequest.rawRequest.Path, generators.ExpandMapValues(generatedRequest.rawRequest.Headers), io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)))
-		} else if generatedRequest.request != nil {
+			if !replayed {
+				resp, err = generatedRequest.pipelinedClient.DoRaw(generatedRequest.rawRequest.Method, input.MetaInput.Input, generatedRequest.rawRequest.Path, generators.ExpandMapValues(generatedRequest.rawRequest.Headers), io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)))
+			}
+		} else if generatedRequest.request != nil && !replayed {
 			resp, err = generatedRequest.pipelinedClient.Dor(generatedRequest.request)
 		}
 	} else if generatedRequest.original.Unsafe && generatedRequest.rawRequest != nil {
@@ -739,20 +775,22 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		formedURL = fmt.Sprintf("%s%s", inputUrl, generatedRequest.rawRequest.Path)
 
 		// send rawhttp request and get response
-		resp, err = httpclientpool.SendRawRequest(generatedRequest.original.rawhttpClient, &httpclientpool.RawHttpRequestOpts{
-			Method:  generatedRequest.rawRequest.Method,
-			URL:     inputUrl,
-			Path:    generatedRequest.rawRequest.Path,
-			Headers: generators.ExpandMapValues(generatedRequest.rawRequest.Headers),
-			Body:    io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)),
-			Options: &options,
-		})
+		if !replayed {
+			resp, err = httpclientpool.SendRawRequest(generatedRequest.original.rawhttpClient, &httpclientpool.RawHttpRequestOpts{
+				Method:  generatedRequest.rawRequest.Method,
+				URL:     inputUrl,
+				Path:    generatedRequest.rawRequest.Path,
+				Headers: generators.ExpandMapValues(generatedRequest.rawRequest.Headers),
+				Body:    io.NopCloser(strings.NewReader(generatedRequest.rawRequest.Data)),
+				Options: &options,
+			})
+		}
 	} else {
 		//** For Normal requests **//
 		hostname = generatedRequest.request.URL.Host
 		formedURL = generatedRequest.request.URL.String()
 		// if nuclei-project is available check if the request was already sent previously
-		if request.options.ProjectFile != nil {
+		if request.options.ProjectFile != nil && !replayed {
 			// if unavailable fail silently
 			fromCache = true
 			resp, err = request.options.ProjectFile.Get(dumpedRequest)
@@ -760,7 +798,7 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				fromCache = false
 			}
 		}
-		if resp == nil {
+		if resp == nil && !replayed {
 			if errSignature := request.handleSignature(generatedRequest); errSignature != nil {
 				return errSignature
 			}
@@ -793,7 +831,15 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				httpclient = client
 			}
 
+			if request.options.AltSvcCache != nil {
+				generatedRequest.request = generatedRequest.request.WithContext(httpclientpool.WithAltSvcCache(generatedRequest.request.Context(), request.options.AltSvcCache))
+			}
+			if request.options.HostRateLimiter != nil {
+				generatedRequest.request = generatedRequest.request.WithContext(httpclientpool.WithHostRateLimiter(generatedRequest.request.Context(), request.options.HostRateLimiter))
+			}
+			endCall := telemetry.StartCall(generatedRequest.request.Context(), request.options.TemplateID, request.Type().String(), formedURL)
 			resp, err = httpclient.Do(generatedRequest.request)
+			endCall(err)
 		}
 	}
 	// use request url as matched url if empty
@@ -826,6 +872,10 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	}
 
 	if err != nil {
+		if !replayed {
+			request.recordResponse(input, dumpedRequest, nil, nil, err)
+			request.observeHostRateLimit(rateLimitHost, hostratelimit.Signal{Err: err, Latency: time.Since(timeStart)})
+		}
 		// rawhttp doesn't support draining response bodies.
 		if resp != nil && resp.Body != nil && generatedRequest.rawRequest == nil && !generatedRequest.original.Pipeline {
 			_, _ = io.CopyN(io.Discard, resp.Body, drainReqSize)
@@ -876,6 +926,9 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 	request.options.Output.Request(request.options.TemplatePath, formedURL, request.Type().String(), err)
 
 	duration := time.Since(timeStart)
+	if !replayed && resp != nil {
+		request.observeHostRateLimit(rateLimitHost, hostratelimit.Signal{StatusCode: resp.StatusCode, RetryAfter: resp.Header.Get("Retry-After"), Latency: duration})
+	}
 
 	// define max body read limit
 	maxBodylimit := MaxBodyRead // 10MB
@@ -886,6 +939,12 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 		maxBodylimit = request.options.Options.ResponseReadSize
 	}
 
+	// keep a copy of the raw response body to store in the traffic archive
+	var rawBody *bytes.Buffer
+	if request.options.TrafficArchive.Recording() {
+		rawBody = traffic.TeeBody(resp)
+	}
+
 	// respChain is http response chain that reads response body
 	// efficiently by reusing buffers and does all decoding and optimizations
 	respChain := httpUtils.NewResponseChain(resp, int64(maxBodylimit))
@@ -901,6 +960,9 @@ func (request *Request) executeRequest(input *contextargs.Context, generatedRequ
 				errx = errors.Wrap(err, "could not store in project file")
 			}
 		}
+		if rawBody != nil {
+			request.recordResponse(input, dumpedRequest, resp, rawBody.Bytes(), nil)
+		}
 	})
 
 	// evaluate responses continiously until first redirect request in reverse order
@@ -197,6 +197,7 @@ func (request *Request) executeGeneratedFuzzingRequest(gr fuzz.GeneratedRequest,
 				Operators:      request.CompiledOperators,
 				MatchFunc:      request.Match,
 				ExtractFunc:    request.Extract,
+				ResultCallback: interactsh.ResultCallbackFromContext(input.Context()),
 				Parameter:      gr.Parameter,
 				Request:        gr.Request,
 			}
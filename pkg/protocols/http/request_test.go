@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,8 +17,10 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
 )
 
 func TestHTTPExtractMultipleReuse(t *testing.T) {
@@ -257,3 +260,149 @@ func TestReqURLPattern(t *testing.T) {
 	require.NotEmpty(t, finalEvent.Results[0].ReqURLPattern, "could not get req url pattern")
 	require.Equal(t, `/{{rand_char("abc")}}/{{interactsh-url}}/123?query={{rand_int(1, 10)}}&data={{randstr}}`, finalEvent.Results[0].ReqURLPattern)
 }
+
+func TestHTTPTrafficRecordReplay(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-traffic-replay"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}/version"},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Part:  "body",
+				Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words: []string{"version: 1.2.3"},
+			}},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("version: 1.2.3"))
+	}))
+	target := ts.URL
+
+	execute := func(archive *traffic.Archive) bool {
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.TrafficArchive = archive
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var matched bool
+		ctxArgs := contextargs.NewWithInput(context.Background(), target)
+		err := request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			if event.OperatorsResult != nil && event.OperatorsResult.Matched {
+				matched = true
+			}
+		})
+		require.Nil(t, err, "could not execute http request")
+		return matched
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	archive, err := traffic.Create(path)
+	require.Nil(t, err, "could not create traffic archive")
+	require.True(t, execute(archive), "could not match recorded response")
+	require.Nil(t, archive.Close())
+
+	// the server is gone, the response must come from the archive
+	ts.Close()
+	archive, err = traffic.Open(path)
+	require.Nil(t, err, "could not open traffic archive")
+	require.True(t, execute(archive), "could not match replayed response")
+}
+
+func TestHTTPTrafficReplayRedirects(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-traffic-replay-redirects"
+	request := &Request{
+		ID:        templateID,
+		Method:    HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:      []string{"{{BaseURL}}/login"},
+		Redirects: true,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("home"))
+	}))
+	target := ts.URL
+
+	// execute returns the status codes and urls of the evaluated redirect chain
+	execute := func(archive *traffic.Archive) ([]interface{}, []interface{}) {
+		executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+			ID:   templateID,
+			Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+		})
+		executerOpts.TrafficArchive = archive
+		require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+		var statusCodes, urls []interface{}
+		ctxArgs := contextargs.NewWithInput(context.Background(), target)
+		err := request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+			statusCodes = append(statusCodes, event.InternalEvent["status_code"])
+			urls = append(urls, event.InternalEvent["matched"])
+		})
+		require.Nil(t, err, "could not execute http request")
+		return statusCodes, urls
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	archive, err := traffic.Create(path)
+	require.Nil(t, err, "could not create traffic archive")
+	recordedStatusCodes, recordedURLs := execute(archive)
+	require.Equal(t, []interface{}{http.StatusOK, http.StatusFound}, recordedStatusCodes, "could not follow redirect")
+	require.Nil(t, archive.Close())
+
+	ts.Close()
+	archive, err = traffic.Open(path)
+	require.Nil(t, err, "could not open traffic archive")
+	statusCodes, urls := execute(archive)
+	require.Equal(t, recordedStatusCodes, statusCodes, "could not replay redirect chain")
+	require.Equal(t, recordedURLs, urls, "could not replay redirect urls")
+}
+
+func TestHTTPHostRateLimitAdaptive(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "http-host-rate-limit"
+	request := &Request{
+		ID:     templateID,
+		Method: HTTPMethodTypeHolder{MethodType: HTTPGet},
+		Path:   []string{"{{BaseURL}}/a", "{{BaseURL}}/b"},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/a" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	executerOpts.HostRateLimiter = hostratelimit.New(&hostratelimit.Options{DefaultRate: 10, Adaptive: true})
+	require.Nil(t, request.Compile(executerOpts), "could not compile http request")
+
+	ctxArgs := contextargs.NewWithInput(context.Background(), ts.URL)
+	err := request.ExecuteWithResults(ctxArgs, make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {})
+	require.Nil(t, err, "could not execute http request")
+
+	stats := executerOpts.HostRateLimiter.Stats()
+	require.Len(t, stats, 1, "could not slow down throttled host")
+	require.Equal(t, "127.0.0.1", stats[0].Host)
+	require.Equal(t, int64(1), stats[0].Throttled)
+	// the healthy response after the throttled one recovers part of the rate
+	require.Equal(t, float64(6), stats[0].Rate)
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
+)
+
+// trafficKey returns the traffic archive key for requests sent to input
+func (request *Request) trafficKey(input *contextargs.Context) traffic.Key {
+	return traffic.Key{Protocol: request.Type().String(), TemplateID: request.options.TemplateID, Target: input.MetaInput.Input}
+}
+
+// replayResponse returns the recorded response of a dumped request
+func (request *Request) replayResponse(input *contextargs.Context, dumpedRequest []byte) (*http.Response, error) {
+	exchange, err := request.options.TrafficArchive.Replay(request.trafficKey(input), dumpedRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := exchange.Err(); err != nil {
+		return nil, err
+	}
+	return traffic.UnmarshalHTTPResponse(exchange.Response)
+}
+
+// recordResponse stores the response (or error) of a dumped request in the
+// traffic archive if recording is enabled.
+func (request *Request) recordResponse(input *contextargs.Context, dumpedRequest []byte, resp *http.Response, body []byte, err error) {
+	if !request.options.TrafficArchive.Recording() {
+		return
+	}
+	var data []byte
+	if resp != nil {
+		var marshalErr error
+		if data, marshalErr = traffic.MarshalHTTPResponse(resp, body); marshalErr != nil {
+			gologger.Warning().Msgf("[%s] Could not marshal response for traffic archive: %s\n", request.options.TemplateID, marshalErr)
+			return
+		}
+	}
+	if recordErr := request.options.TrafficArchive.Record(request.trafficKey(input), dumpedRequest, data, err); recordErr != nil {
+		gologger.Warning().Msgf("[%s] Could not record response in traffic archive: %s\n", request.options.TemplateID, recordErr)
+	}
+}
@@ -11,5 +11,9 @@ func (request *Request) validate() error {
 		return errors.New("'redirects' and 'host-redirects' can't be used together")
 	}
 
+	if request.HTTP3 && (request.Unsafe || request.Pipeline) {
+		return errors.New("'http3' can't be used with 'unsafe' or 'pipeline'")
+	}
+
 	return nil
 }
@@ -342,7 +342,7 @@ func (request *Request) ExecuteWithResults(target *contextargs.Context, dynamicV
 		}
 		argsCopy.TemplateCtx = templateCtx.GetAll()
 
-		result, err := request.options.JsCompiler.ExecuteWithOptions(request.preConditionCompiled, argsCopy,
+		result, err := request.executeScript(input, request.preConditionCompiled, argsCopy,
 			&compiler.ExecuteOptions{
 				TimeoutVariants: requestOptions.Options.GetTimeouts(),
 				Source:          &request.PreCondition, Context: target.Context(),
@@ -516,7 +516,7 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 		}
 	}
 
-	results, err := request.options.JsCompiler.ExecuteWithOptions(request.scriptCompiled, argsCopy,
+	results, err := request.executeScript(input, request.scriptCompiled, argsCopy,
 		&compiler.ExecuteOptions{
 			TimeoutVariants: requestOptions.Options.GetTimeouts(),
 			Source:          &request.Code,
@@ -592,6 +592,7 @@ func (request *Request) executeRequestWithPayloads(hostPort string, input *conte
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			ResultCallback: interactsh.ResultCallbackFromContext(input.Context()),
 		})
 	}
 	return nil
@@ -0,0 +1,66 @@
+package javascript
+
+import (
+	"encoding/json"
+
+	"github.com/dop251/goja"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/js/compiler"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
+)
+
+// executeScript executes the compiled script or replays its recorded
+// result from the traffic archive. Scripts are recorded as a whole since
+// the js libraries talk to the network on their own.
+func (request *Request) executeScript(input *contextargs.Context, program *goja.Program, args *compiler.ExecuteArgs, opts *compiler.ExecuteOptions) (compiler.ExecuteResult, error) {
+	archive := request.options.TrafficArchive
+	if !archive.Replaying() && !archive.Recording() {
+		return request.runScript(input, program, args, opts)
+	}
+
+	key := traffic.Key{Protocol: request.Type().String(), TemplateID: request.options.TemplateID, Target: input.MetaInput.Input}
+	// encoding/json sorts map keys which keeps the fingerprint stable
+	var source string
+	if opts != nil && opts.Source != nil {
+		source = *opts.Source
+	}
+	scriptRequest, _ := json.Marshal(map[string]interface{}{"source": source, "args": args.Args})
+
+	if archive.Replaying() {
+		exchange, err := archive.Replay(key, scriptRequest)
+		if err != nil {
+			return nil, err
+		}
+		if err := exchange.Err(); err != nil {
+			return nil, err
+		}
+		result := compiler.ExecuteResult{}
+		if err := jsoniter.Unmarshal(exchange.Response, &result); err != nil {
+			return nil, errors.Wrap(err, "could not parse recorded javascript result")
+		}
+		return result, nil
+	}
+
+	result, err := request.runScript(input, program, args, opts)
+	var data []byte
+	if result != nil {
+		data, _ = jsoniter.Marshal(result)
+	}
+	if recordErr := archive.Record(key, scriptRequest, data, err); recordErr != nil {
+		gologger.Warning().Msgf("[%s] Could not record result in traffic archive: %s\n", request.options.TemplateID, recordErr)
+	}
+	return result, err
+}
+
+// runScript executes the compiled script tracing it as a call to the target
+func (request *Request) runScript(input *contextargs.Context, program *goja.Program, args *compiler.ExecuteArgs, opts *compiler.ExecuteOptions) (compiler.ExecuteResult, error) {
+	endCall := telemetry.StartCall(input.Context(), request.options.TemplateID, request.Type().String(), input.MetaInput.Input)
+	result, err := request.options.JsCompiler.ExecuteWithOptions(program, args, opts)
+	endCall(err)
+	return result, err
+}
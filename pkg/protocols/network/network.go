@@ -25,10 +25,13 @@ type Request struct {
 	//   Host to send network requests to.
 	//
 	//   Usually it's set to `{{Hostname}}`. If you want to enable TLS for
-	//   TCP Connection, you can use `tls://{{Hostname}}`.
+	//   TCP Connection, you can use `tls://{{Hostname}}`. UDP datagrams are
+	//   sent with `udp://{{Hostname}}`, every input being sent as one datagram.
 	// examples:
 	//   - value: |
 	//       []string{"{{Hostname}}"}
+	//   - value: |
+	//       []string{"udp://{{Host}}:161"}
 	Address   []string `yaml:"host,omitempty" json:"host,omitempty" jsonschema:"title=host to send requests to,description=Host to send network requests to"`
 	addresses []addressKV
 
@@ -121,6 +124,15 @@ var RequestPartDefinitions = map[string]string{
 type addressKV struct {
 	address string
 	tls     bool
+	udp     bool
+}
+
+// network returns the network to dial the address on
+func (kv addressKV) network() string {
+	if kv.udp {
+		return "udp"
+	}
+	return "tcp"
 }
 
 // Input is the input to send on the network
@@ -176,7 +188,16 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 			shouldUseTLS = true
 			address = strings.TrimPrefix(address, "tls://")
 		}
-		request.addresses = append(request.addresses, addressKV{address: address, tls: shouldUseTLS})
+		// udp addresses send every input as a datagram
+		var useUDP bool
+		if strings.HasPrefix(address, "udp://") {
+			if shouldUseTLS {
+				return errorutil.NewWithTag(request.TemplateID, "tls is not supported for udp address %v", address)
+			}
+			useUDP = true
+			address = strings.TrimPrefix(address, "udp://")
+		}
+		request.addresses = append(request.addresses, addressKV{address: address, tls: shouldUseTLS, udp: useUDP})
 	}
 	// Pre-compile any input dsl functions before executing the request.
 	for _, input := range request.Inputs {
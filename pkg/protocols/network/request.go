@@ -46,8 +46,9 @@ func (request *Request) Type() templateTypes.ProtocolType {
 // getOpenPorts returns all open ports from list of ports provided in template
 // if only 1 port is provided, no need to check if port is open or not
 func (request *Request) getOpenPorts(target *contextargs.Context) ([]string, error) {
-	if len(request.ports) == 1 {
-		// no need to check if port is open or not
+	if len(request.ports) == 1 || request.options.TrafficArchive.Replaying() || request.isUDPOnly() {
+		// no need to check if port is open or not. udp ports
+		// cannot be checked without a protocol specific probe.
 		return request.ports, nil
 	}
 	errs := []error{}
@@ -178,7 +179,7 @@ func (request *Request) executeOnTarget(input *contextargs.Context, visited maps
 			continue
 		}
 		visited.Set(actualAddress, struct{}{})
-		if err = request.executeAddress(variables, actualAddress, address, input, kv.tls, previous, wrappedCallback); err != nil {
+		if err = request.executeAddress(variables, actualAddress, address, input, kv, previous, wrappedCallback); err != nil {
 			outputEvent := request.responseToDSLMap("", "", "", address, "")
 			callback(&output.InternalWrappedEvent{InternalEvent: outputEvent})
 			gologger.Warning().Msgf("[%v] Could not make network request for (%s) : %s\n", request.options.TemplateID, actualAddress, err)
@@ -191,7 +192,7 @@ func (request *Request) executeOnTarget(input *contextargs.Context, visited maps
 }
 
 // executeAddress executes the request for an address
-func (request *Request) executeAddress(variables map[string]interface{}, actualAddress, address string, input *contextargs.Context, shouldUseTLS bool, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (request *Request) executeAddress(variables map[string]interface{}, actualAddress, address string, input *contextargs.Context, kv addressKV, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	variables = generators.MergeMaps(variables, map[string]interface{}{"Hostname": address})
 	payloads := generators.BuildPayloadFromOptions(request.options.Options)
 
@@ -249,7 +250,7 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 					// skip on unresponsive address no need to continue
 					return
 				}
-				if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, vars, previous, callback); err != nil {
+				if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, kv, vars, previous, callback); err != nil {
 					m.Lock()
 					multiErr = multierr.Append(multiErr, err)
 					m.Unlock()
@@ -262,14 +263,14 @@ func (request *Request) executeAddress(variables map[string]interface{}, actualA
 		}
 	} else {
 		value := maps.Clone(payloads)
-		if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, shouldUseTLS, value, previous, callback); err != nil {
+		if err := request.executeRequestWithPayloads(variables, actualAddress, address, input, kv, value, previous, callback); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (request *Request) executeRequestWithPayloads(variables map[string]interface{}, actualAddress, address string, input *contextargs.Context, shouldUseTLS bool, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+func (request *Request) executeRequestWithPayloads(variables map[string]interface{}, actualAddress, address string, input *contextargs.Context, kv addressKV, payloads map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	var (
 		hostname string
 		conn     net.Conn
@@ -286,11 +287,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		return nil
 	}
 
-	if shouldUseTLS {
-		conn, err = request.dialer.DialTLS(input.Context(), "tcp", actualAddress)
-	} else {
-		conn, err = request.dialer.Dial(input.Context(), "tcp", actualAddress)
-	}
+	conn, err = request.dial(input.Context(), kv.network(), actualAddress, kv.tls)
 	if err != nil {
 		// adds it to unresponsive address list if applicable
 		request.markUnresponsiveAddress(updatedTarget, err)
@@ -353,7 +350,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		}
 
 		if input.Read > 0 {
-			buffer, err := ConnReadNWithTimeout(conn, int64(input.Read), request.options.Options.GetTimeouts().TcpReadTimeout)
+			buffer, err := request.read(conn, kv, int64(input.Read))
 			if err != nil {
 				return errorutil.NewWithErr(err).Msgf("could not read response from connection")
 			}
@@ -393,7 +390,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 	}
 
 	request.options.Output.Request(request.options.TemplatePath, actualAddress, request.Type().String(), err)
-	gologger.Verbose().Msgf("Sent TCP request to %s", actualAddress)
+	gologger.Verbose().Msgf("Sent %s request to %s", strings.ToUpper(kv.network()), actualAddress)
 
 	bufferSize := 1024
 	if request.ReadSize != 0 {
@@ -403,7 +400,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 		bufferSize = -1
 	}
 
-	final, err := ConnReadNWithTimeout(conn, int64(bufferSize), request.options.Options.GetTimeouts().TcpReadTimeout)
+	final, err := request.read(conn, kv, int64(bufferSize))
 	if err != nil {
 		request.options.Output.Request(request.options.TemplatePath, address, request.Type().String(), err)
 		gologger.Verbose().Msgf("could not read more data from %s: %s", actualAddress, err)
@@ -448,6 +445,7 @@ func (request *Request) executeRequestWithPayloads(variables map[string]interfac
 			Operators:      request.CompiledOperators,
 			MatchFunc:      request.Match,
 			ExtractFunc:    request.Extract,
+			ResultCallback: interactsh.ResultCallbackFromContext(input.Context()),
 		})
 	}
 	if len(interactshURLs) > 0 {
@@ -491,6 +489,16 @@ func displayCompactHexView(event *output.InternalWrappedEvent, response string,
 	}
 }
 
+// read reads up to n bytes of response from the connection. For udp whole
+// datagrams are read, collecting further datagrams until n bytes are read
+// or the server stops sending. n is ignored by udp inputs reading one datagram.
+func (request *Request) read(conn net.Conn, kv addressKV, n int64) ([]byte, error) {
+	if !kv.udp {
+		return ConnReadNWithTimeout(conn, n, request.options.Options.GetTimeouts().TcpReadTimeout)
+	}
+	return ReadDatagramsWithTimeout(conn, n, request.options.Options.GetTimeouts().UdpReadTimeout)
+}
+
 // getAddress returns the address of the host to make request to
 func getAddress(toTest string) (string, error) {
 	if strings.Contains(toTest, "://") {
@@ -524,6 +532,53 @@ func ConnReadNWithTimeout(conn net.Conn, n int64, timeout time.Duration) ([]byte
 	return b[:count], nil
 }
 
+// maxDatagramSize is the maximum size of an udp datagram
+const maxDatagramSize = 65535
+
+// udpReadGap is the maximum time to wait for a datagram following a
+// received one, since the end of the response cannot be detected.
+const udpReadGap = 500 * time.Millisecond
+
+// ReadDatagramsWithTimeout reads datagrams from conn until at least n bytes
+// are read. n of -1 reads datagrams until none arrive in time and n of 0
+// reads a single datagram. timeout is the time to wait for the first datagram.
+func ReadDatagramsWithTimeout(conn net.Conn, n int64, timeout time.Duration) ([]byte, error) {
+	defer func() {
+		_ = conn.SetReadDeadline(time.Time{})
+	}()
+
+	var data []byte
+	buffer := make([]byte, maxDatagramSize)
+	for {
+		wait := timeout
+		if len(data) > 0 && udpReadGap < wait {
+			wait = udpReadGap
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wait))
+		count, err := conn.Read(buffer)
+		if err != nil {
+			if len(data) > 0 && os.IsTimeout(err) {
+				return data, nil
+			}
+			return data, err
+		}
+		data = append(data, buffer[:count]...)
+		if n == 0 || (n > 0 && int64(len(data)) >= n) {
+			return data, nil
+		}
+	}
+}
+
+// isUDPOnly returns true if all the addresses of the request are udp
+func (request *Request) isUDPOnly() bool {
+	for _, kv := range request.addresses {
+		if !kv.udp {
+			return false
+		}
+	}
+	return len(request.addresses) > 0
+}
+
 // markUnresponsiveAddress checks if the error is a unreponsive host error and marks it
 func (request *Request) markUnresponsiveAddress(input *contextargs.Context, err error) {
 	if err == nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -109,6 +110,66 @@ func TestNetworkExecuteWithResults(t *testing.T) {
 	require.Equal(t, "<h1>Example Domain</h1>", finalEvent.Results[0].ExtractedResults[0], "could not get correct extracted results")
 }
 
+func TestNetworkUDPExecuteWithResults(t *testing.T) {
+	options := testutils.DefaultOptions
+
+	testutils.Init(options)
+	templateID := "testing-network-udp"
+	request := &Request{
+		ID:       templateID,
+		Address:  []string{"udp://{{Hostname}}"},
+		ReadSize: 2048,
+		Inputs:   []*Input{{Data: "ping"}},
+		Operators: operators.Operators{
+			Matchers: []*matchers.Matcher{{
+				Name:      "test",
+				Part:      "data",
+				Type:      matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+				Words:     []string{"pong:ping", "done"},
+				Condition: "and",
+			}},
+		},
+	}
+
+	// reply to every datagram with two datagrams to check all are read
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen on udp")
+	defer conn.Close()
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			_, _ = conn.WriteTo([]byte("pong:"+string(buffer[:n])), addr)
+			_, _ = conn.WriteTo([]byte("done"), addr)
+		}
+	}()
+
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	err = request.Compile(executerOpts)
+	require.Nil(t, err, "could not compile network request")
+	require.True(t, request.addresses[0].udp, "could not parse udp address")
+
+	var finalEvent *output.InternalWrappedEvent
+	metadata := make(output.InternalEvent)
+	previous := make(output.InternalEvent)
+	ctxArgs := contextargs.NewWithInput(context.Background(), conn.LocalAddr().String())
+	err = request.ExecuteWithResults(ctxArgs, metadata, previous, func(event *output.InternalWrappedEvent) {
+		finalEvent = event
+	})
+	require.Nil(t, err, "could not execute network request")
+	require.NotNil(t, finalEvent, "could not get event output from request")
+	require.Equal(t, 1, len(finalEvent.Results), "could not get correct number of results")
+
+	request = &Request{Address: []string{"tls://udp://{{Hostname}}"}}
+	require.NotNil(t, request.Compile(executerOpts), "could compile tls udp address")
+}
+
 var exampleBody = `<!doctype html>
 <html>
 <head>
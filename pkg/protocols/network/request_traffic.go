@@ -0,0 +1,48 @@
+package network
+
+import (
+	"context"
+	"net"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
+)
+
+// dial connects to the address or replays a recorded connection to it
+// from the traffic archive. When recording, the returned connection
+// stores its conversation in the archive once closed.
+func (request *Request) dial(ctx context.Context, network, address string, shouldUseTLS bool) (net.Conn, error) {
+	archive := request.options.TrafficArchive
+	key := traffic.Key{Protocol: request.Type().String(), TemplateID: request.options.TemplateID, Target: address}
+	if archive.Replaying() {
+		exchange, err := archive.Replay(key, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := exchange.Err(); err != nil {
+			return nil, err
+		}
+		return traffic.NewReplayConn(exchange)
+	}
+
+	var conn net.Conn
+	var err error
+	endCall := telemetry.StartCall(ctx, request.options.TemplateID, request.Type().String(), address)
+	if shouldUseTLS {
+		conn, err = request.dialer.DialTLS(ctx, network, address)
+	} else {
+		conn, err = request.dialer.Dial(ctx, network, address)
+	}
+	endCall(err)
+	if !archive.Recording() {
+		return conn, err
+	}
+	if err != nil {
+		if recordErr := archive.Record(key, nil, nil, err); recordErr != nil {
+			gologger.Warning().Msgf("[%s] Could not record connection in traffic archive: %s\n", request.options.TemplateID, recordErr)
+		}
+		return nil, err
+	}
+	return traffic.NewRecordingConn(conn, archive, key), nil
+}
@@ -0,0 +1,181 @@
+package offlinehttp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// capture formats processed in addition to raw http response text files
+const (
+	formatHAR  = "har"
+	formatWARC = "warc"
+	formatPCAP = "pcap"
+)
+
+// captureExtensions contains the capture format of the supported file extensions
+var captureExtensions = map[string]string{
+	".har":    formatHAR,
+	".warc":   formatWARC,
+	".pcap":   formatPCAP,
+	".pcapng": formatPCAP,
+	".cap":    formatPCAP,
+}
+
+// captureFormat returns the capture format of the file at path or
+// an empty string if it is not a capture. Gzip compressed captures
+// are identified by the extension before the .gz suffix.
+func captureFormat(path string) string {
+	name := strings.TrimSuffix(strings.ToLower(path), ".gz")
+	return captureExtensions[filepath.Ext(name)]
+}
+
+// isInputFile returns true if the file at path is a raw response or a capture
+func isInputFile(path string) bool {
+	return filepath.Ext(path) == ".txt" || captureFormat(path) != ""
+}
+
+// errExceededMaxSize is the error of exchanges skipped as their response
+// is larger than the maximum size of bodies read.
+var errExceededMaxSize = errors.New("exceeded max size")
+
+// exchange is a http request and response pair read from a capture
+type exchange struct {
+	// request is the raw request, empty if it was not captured
+	request []byte
+	// response is the raw response
+	response []byte
+	// position is the position of the response in the capture
+	position string
+	// url is the url of the request if known
+	url string
+	// ip is the address of the server if known
+	ip string
+	// err is the reason the exchange could not be read from the capture
+	err error
+}
+
+// readCapture reads the http exchanges of the capture file at path.
+//
+// Captures are read in memory, so responses, streams and decoded bodies
+// larger than maxSize bytes are skipped.
+func readCapture(path string, maxSize int, callback func(*exchange)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var input io.Reader = reader
+	if magic, _ := reader.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return errors.Wrap(err, "could not decompress capture")
+		}
+		defer gzipReader.Close()
+		input = gzipReader
+	}
+
+	switch captureFormat(path) {
+	case formatHAR:
+		return readHAR(input, maxSize, callback)
+	case formatWARC:
+		return readWARC(input, maxSize, callback)
+	case formatPCAP:
+		return readPCAP(input, maxSize, callback)
+	}
+	return errors.Errorf("unsupported capture format: %s", path)
+}
+
+// parse parses the raw response of the exchange decoding its body.
+//
+// The response body is read in full and replaced so that the response
+// can be dumped again with the decoded body. Bodies larger than maxSize
+// bytes once decoded are not read.
+func (item *exchange) parse(maxSize int) (*http.Response, error) {
+	var req *http.Request
+	if len(item.request) > 0 {
+		if parsed, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(item.request))); err == nil {
+			req = parsed
+		}
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(item.response)), req)
+	if err != nil {
+		if resp, err = readResponseFromString(string(item.response)); err != nil {
+			return nil, err
+		}
+	}
+	// captures may be truncated so partial bodies are still processed
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxSize)+1))
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, errors.Wrap(err, "could not read response body")
+	}
+	_ = resp.Body.Close()
+	if len(body) > maxSize {
+		return nil, errExceededMaxSize
+	}
+
+	decoded, ok, err := decodeBody(resp.Header.Get("Content-Encoding"), body, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		body = decoded
+		resp.Header.Del("Content-Encoding")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.TransferEncoding = nil
+	resp.Header.Del("Content-Length")
+
+	if item.url == "" && req != nil {
+		item.url = requestURL(req)
+	}
+	return resp, nil
+}
+
+// decodeBody decodes a body compressed with the content encoding, returning
+// false if it is not compressed or can't be decoded. Bodies decoding to more
+// than maxSize bytes are not decoded and return an error.
+func decodeBody(encoding string, body []byte, maxSize int) ([]byte, bool, error) {
+	var reader io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, false, nil
+		}
+		reader = gzipReader
+	case "deflate":
+		reader = flate.NewReader(bytes.NewReader(body))
+	default:
+		return nil, false, nil
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, false, nil
+	}
+	if len(decoded) > maxSize {
+		return nil, false, errExceededMaxSize
+	}
+	return decoded, true, nil
+}
+
+// requestURL returns the absolute url of a request read from the wire
+func requestURL(req *http.Request) string {
+	if req.URL.IsAbs() {
+		return req.URL.String()
+	}
+	return "http://" + req.Host + req.URL.RequestURI()
+}
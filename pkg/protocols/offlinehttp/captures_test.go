@@ -0,0 +1,174 @@
+package offlinehttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/stretchr/testify/require"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+)
+
+// testMaxSize is the max size of the responses read from test captures
+const testMaxSize = 1024
+
+func readCaptureFile(t *testing.T, name string, data []byte) []*exchange {
+	return readCaptureFileWithMaxSize(t, name, data, testMaxSize)
+}
+
+func readCaptureFileWithMaxSize(t *testing.T, name string, data []byte, maxSize int) []*exchange {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, data, permissionutil.TempFilePermission), "could not write capture")
+
+	var exchanges []*exchange
+	err := readCapture(path, maxSize, func(item *exchange) {
+		exchanges = append(exchanges, item)
+	})
+	require.Nil(t, err, "could not read capture")
+	return exchanges
+}
+
+func TestReadHAR(t *testing.T) {
+	har := `{"log":{"entries":[
+{"serverIPAddress":"[::1]","request":{"method":"POST","url":"https://example.com/login?next=%2F","headers":[{"name":":authority","value":"example.com"},{"name":"Content-Type","value":"application/json"}],"postData":{"text":"{\"user\":\"admin\"}"}},
+ "response":{"status":200,"statusText":"","headers":[{"name":"content-encoding","value":"gzip"},{"name":"X-Powered-By","value":"nuclei"}],"content":{"text":"welcome admin"}}},
+{"request":{"method":"GET","url":"https://example.com/blocked","headers":[]},"response":{"status":0,"headers":[],"content":{}}},
+{"request":{"method":"GET","url":"https://example.com/logo.png","headers":[]},"response":{"status":404,"headers":[],"content":{"text":"bm90IGZvdW5k","encoding":"base64"}}}
+]}}`
+	exchanges := readCaptureFile(t, "capture.har", []byte(har))
+	require.Len(t, exchanges, 2, "could not skip entry without response")
+
+	require.Equal(t, "entry=0", exchanges[0].position)
+	require.Equal(t, "::1", exchanges[0].ip)
+	require.Contains(t, string(exchanges[0].request), "POST /login?next=%2F HTTP/1.1\r\nHost: example.com\r\n")
+	resp, err := exchanges[0].parse(testMaxSize)
+	require.Nil(t, err, "could not parse har response")
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, "nuclei", resp.Header.Get("X-Powered-By"))
+	body, _ := io.ReadAll(resp.Body)
+	require.Equal(t, "welcome admin", string(body))
+
+	require.Equal(t, "entry=2", exchanges[1].position)
+	resp, err = exchanges[1].parse(testMaxSize)
+	require.Nil(t, err, "could not parse har response")
+	body, _ = io.ReadAll(resp.Body)
+	require.Equal(t, "not found", string(body))
+}
+
+func TestReadWARC(t *testing.T) {
+	record := func(fields, block string) string {
+		return "WARC/1.0\r\n" + fields + "Content-Length: " + strconv.Itoa(len(block)) + "\r\n\r\n" + block + "\r\n\r\n"
+	}
+	request := "GET /admin HTTP/1.1\r\nHost: example.com\r\n\r\n"
+	response := "HTTP/1.1 200 OK\r\nContent-Length: 11\r\n\r\nadmin panel"
+	orphan := "HTTP/1.1 404 Not Found\r\nContent-Length: 0\r\n\r\n"
+	warc := record("WARC-Type: warcinfo\r\nWARC-Record-ID: <urn:uuid:0>\r\nContent-Type: application/warc-fields\r\n", "software: test\r\n") +
+		// response records written before their request record
+		record("WARC-Type: response\r\nWARC-Record-ID: <urn:uuid:1>\r\nWARC-Target-URI: http://example.com/admin\r\nWARC-IP-Address: 10.0.0.1\r\nContent-Type: application/http; msgtype=response\r\n", response) +
+		record("WARC-Type: request\r\nWARC-Record-ID: <urn:uuid:2>\r\nWARC-Concurrent-To: <urn:uuid:1>\r\nContent-Type: application/http; msgtype=request\r\n", request) +
+		record("WARC-Type: response\r\nWARC-Record-ID: <urn:uuid:3>\r\nWARC-Target-URI: http://example.com/missing\r\nContent-Type: application/http; msgtype=response\r\n", orphan)
+
+	compressed := &bytes.Buffer{}
+	writer := gzip.NewWriter(compressed)
+	_, _ = writer.Write([]byte(warc))
+	require.Nil(t, writer.Close())
+
+	exchanges := readCaptureFile(t, "crawl.warc.gz", compressed.Bytes())
+	require.Len(t, exchanges, 2, "could not read warc responses")
+
+	require.Equal(t, "record=1", exchanges[0].position)
+	require.Equal(t, request, string(exchanges[0].request))
+	require.Equal(t, "10.0.0.1", exchanges[0].ip)
+	require.Equal(t, "http://example.com/admin", exchanges[0].url)
+
+	require.Equal(t, "record=3", exchanges[1].position)
+	require.Empty(t, exchanges[1].request)
+	resp, err := exchanges[1].parse(testMaxSize)
+	require.Nil(t, err, "could not parse warc response")
+	require.Equal(t, 404, resp.StatusCode)
+}
+
+func TestReadPCAP(t *testing.T) {
+	client, server := net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 1)
+	buffer := &bytes.Buffer{}
+	writer := pcapgo.NewWriter(buffer)
+	require.Nil(t, writer.WriteFileHeader(65535, layers.LinkTypeEthernet))
+
+	writePacket := func(src, dst net.IP, srcPort, dstPort int, seq uint32, syn bool, payload string) {
+		ethernet := &layers.Ethernet{SrcMAC: net.HardwareAddr{0, 0, 0, 0, 0, 1}, DstMAC: net.HardwareAddr{0, 0, 0, 0, 0, 2}, EthernetType: layers.EthernetTypeIPv4}
+		ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolTCP, SrcIP: src, DstIP: dst}
+		tcp := &layers.TCP{SrcPort: layers.TCPPort(srcPort), DstPort: layers.TCPPort(dstPort), Seq: seq, SYN: syn, ACK: !syn, PSH: payload != "", Window: 65535}
+		_ = tcp.SetNetworkLayerForChecksum(ip)
+		packet := gopacket.NewSerializeBuffer()
+		err := gopacket.SerializeLayers(packet, gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}, ethernet, ip, tcp, gopacket.Payload(payload))
+		require.Nil(t, err, "could not serialize packet")
+		data := packet.Bytes()
+		err = writer.WritePacket(gopacket.CaptureInfo{Timestamp: time.Now(), CaptureLength: len(data), Length: len(data)}, data)
+		require.Nil(t, err, "could not write packet")
+	}
+
+	request := "GET /.git/config HTTP/1.1\r\nHost: example.com\r\n\r\n"
+	response := "HTTP/1.1 200 OK\r\nContent-Length: 20\r\n\r\n[core]\n\tbare = false"
+	writePacket(client, server, 40000, 80, 1000, true, "")
+	writePacket(server, client, 80, 40000, 5000, true, "")
+	writePacket(client, server, 40000, 80, 1001, false, request)
+	// response split in two segments captured out of order with a retransmission
+	writePacket(server, client, 80, 40000, 5001+30, false, response[30:])
+	writePacket(server, client, 80, 40000, 5001, false, response[:30])
+	writePacket(server, client, 80, 40000, 5001, false, response[:30])
+
+	exchanges := readCaptureFile(t, "traffic.pcap", buffer.Bytes())
+	require.Len(t, exchanges, 1, "could not read pcap exchanges")
+	require.Equal(t, "packet=5", exchanges[0].position)
+	require.Equal(t, "10.0.0.1", exchanges[0].ip)
+	require.Equal(t, request, string(exchanges[0].request))
+	require.Equal(t, response, string(exchanges[0].response))
+
+	resp, err := exchanges[0].parse(testMaxSize)
+	require.Nil(t, err, "could not parse pcap response")
+	require.Equal(t, "http://example.com/.git/config", exchanges[0].url)
+	body, _ := io.ReadAll(resp.Body)
+	require.Equal(t, "[core]\n\tbare = false", string(body))
+
+	// streams exceeding the max size are skipped
+	exchanges = readCaptureFileWithMaxSize(t, "large.pcap", buffer.Bytes(), 64)
+	require.Len(t, exchanges, 1, "could not report oversized stream")
+	require.Equal(t, "stream=10.0.0.1:80>10.0.0.2:40000", exchanges[0].position)
+	require.ErrorIs(t, exchanges[0].err, errExceededMaxSize)
+}
+
+func TestCaptureMaxSize(t *testing.T) {
+	large := strings.Repeat("A", testMaxSize+1)
+	har := `{"log":{"entries":[
+{"request":{"method":"GET","url":"https://example.com/large","headers":[]},"response":{"status":200,"headers":[],"content":{"text":"` + large + `"}}}
+]}}`
+	exchanges := readCaptureFile(t, "large.har", []byte(har))
+	require.Len(t, exchanges, 1)
+	require.ErrorIs(t, exchanges[0].err, errExceededMaxSize, "could not skip oversized har entry")
+
+	record := "WARC/1.0\r\nWARC-Type: response\r\nContent-Type: application/http; msgtype=response\r\n" +
+		"Content-Length: " + strconv.Itoa(len(large)) + "\r\n\r\n" + large + "\r\n\r\n"
+	exchanges = readCaptureFile(t, "large.warc", []byte(record))
+	require.Len(t, exchanges, 1)
+	require.ErrorIs(t, exchanges[0].err, errExceededMaxSize, "could not skip oversized warc record")
+
+	// compressed bodies decoding to more than the max size are not decoded
+	compressed := &bytes.Buffer{}
+	writer := gzip.NewWriter(compressed)
+	_, _ = writer.Write(bytes.Repeat([]byte{0}, 100*testMaxSize))
+	require.Nil(t, writer.Close())
+	bomb := &exchange{response: append([]byte("HTTP/1.1 200 OK\r\nContent-Encoding: gzip\r\nContent-Length: "+strconv.Itoa(compressed.Len())+"\r\n\r\n"), compressed.Bytes()...)}
+	_, err := bomb.parse(testMaxSize)
+	require.ErrorIs(t, err, errExceededMaxSize, "could not skip oversized decoded body")
+}
@@ -47,8 +47,8 @@ func (request *Request) findGlobPathMatches(absPath string, processed map[string
 		return errors.Errorf("wildcard found, but unable to glob: %s\n", err)
 	}
 	for _, match := range matches {
-		if filepath.Ext(match) != ".txt" {
-			continue // only process .txt files
+		if !isInputFile(match) {
+			continue // only process .txt files and captures
 		}
 		if _, ok := processed[match]; !ok {
 			processed[match] = struct{}{}
@@ -68,8 +68,8 @@ func (request *Request) findFileMatches(absPath string, processed map[string]str
 	if !info.Mode().IsRegular() {
 		return false, nil
 	}
-	if filepath.Ext(absPath) != ".txt" {
-		return false, nil // only process .txt files
+	if !isInputFile(absPath) {
+		return false, nil // only process .txt files and captures
 	}
 	if _, ok := processed[absPath]; !ok {
 		processed[absPath] = struct{}{}
@@ -90,8 +90,8 @@ func (request *Request) findDirectoryMatches(absPath string, processed map[strin
 			if d.IsDir() {
 				return nil
 			}
-			if filepath.Ext(p) != ".txt" {
-				return nil // only process .txt files
+			if !isInputFile(p) {
+				return nil // only process .txt files and captures
 			}
 			if _, ok := processed[p]; !ok {
 				callback(p)
@@ -38,12 +38,15 @@ func TestFindResponses(t *testing.T) {
 		"final.txt":         "TEST",
 		"image_ignored.png": "TEST",
 		"test.txt":          "TEST",
+		"capture.har":       "TEST",
+		"crawl.warc.gz":     "TEST",
+		"traffic.pcapng":    "TEST",
 	}
 	for k, v := range files {
 		err = os.WriteFile(filepath.Join(tempDir, k), []byte(v), permissionutil.TempFilePermission)
 		require.Nil(t, err, "could not write temporary file")
 	}
-	expected := []string{"config.txt", "final.txt", "test.txt"}
+	expected := []string{"config.txt", "final.txt", "test.txt", "capture.har", "crawl.warc.gz", "traffic.pcapng"}
 	got := []string{}
 	err = request.getInputPaths(tempDir+"/*", func(item string) {
 		base := filepath.Base(item)
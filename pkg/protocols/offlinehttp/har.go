@@ -0,0 +1,135 @@
+package offlinehttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// harFile is a HTTP Archive (HAR) exported by browsers and proxies
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	ServerIPAddress string      `json:"serverIPAddress"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+}
+
+type harRequest struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  []harHeader `json:"headers"`
+	PostData *struct {
+		Text string `json:"text"`
+	} `json:"postData"`
+}
+
+type harResponse struct {
+	Status     int         `json:"status"`
+	StatusText string      `json:"statusText"`
+	Headers    []harHeader `json:"headers"`
+	Content    struct {
+		Text     string `json:"text"`
+		Encoding string `json:"encoding"`
+	} `json:"content"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// readHAR reads the http exchanges of the entries of a HAR file.
+// Entries without a response such as blocked requests are skipped.
+func readHAR(reader io.Reader, maxSize int, callback func(*exchange)) error {
+	har := &harFile{}
+	if err := jsoniter.NewDecoder(reader).Decode(har); err != nil {
+		return errors.Wrap(err, "could not decode har file")
+	}
+	for i, entry := range har.Log.Entries {
+		if entry.Response.Status == 0 {
+			continue
+		}
+		if len(entry.Response.Content.Text) > maxSize {
+			callback(&exchange{position: fmt.Sprintf("entry=%d", i), url: entry.Request.URL, err: errExceededMaxSize})
+			continue
+		}
+		body := []byte(entry.Response.Content.Text)
+		if entry.Response.Content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Response.Content.Text)
+			if err != nil {
+				continue
+			}
+			body = decoded
+		}
+		callback(&exchange{
+			request:  entry.Request.raw(),
+			response: entry.Response.raw(body),
+			position: fmt.Sprintf("entry=%d", i),
+			url:      entry.Request.URL,
+			ip:       strings.Trim(entry.ServerIPAddress, "[]"),
+		})
+	}
+	return nil
+}
+
+// raw returns the request as sent over HTTP/1.1
+func (request *harRequest) raw() []byte {
+	parsed, err := url.Parse(request.URL)
+	if err != nil {
+		return nil
+	}
+	var body string
+	if request.PostData != nil {
+		body = request.PostData.Text
+	}
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "%s %s HTTP/1.1\r\nHost: %s\r\n", request.Method, parsed.RequestURI(), parsed.Host)
+	writeHARHeaders(buffer, request.Headers, "Host")
+	if body != "" {
+		fmt.Fprintf(buffer, "Content-Length: %d\r\n", len(body))
+	}
+	buffer.WriteString("\r\n")
+	buffer.WriteString(body)
+	return buffer.Bytes()
+}
+
+// raw returns the response as sent over HTTP/1.1 with the body.
+//
+// HAR files store the decoded content so the content encoding is not kept.
+func (response *harResponse) raw(body []byte) []byte {
+	statusText := response.StatusText
+	if statusText == "" {
+		statusText = http.StatusText(response.Status)
+	}
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "HTTP/1.1 %s %s\r\n", strconv.Itoa(response.Status), statusText)
+	writeHARHeaders(buffer, response.Headers, "Content-Encoding")
+	fmt.Fprintf(buffer, "Content-Length: %d\r\n\r\n", len(body))
+	buffer.Write(body)
+	return buffer.Bytes()
+}
+
+// writeHARHeaders writes the headers skipping HTTP/2 pseudo headers, the
+// framing headers recomputed for the body and the excluded header
+func writeHARHeaders(buffer *bytes.Buffer, headers []harHeader, exclude string) {
+	for _, header := range headers {
+		if strings.HasPrefix(header.Name, ":") || strings.EqualFold(header.Name, exclude) ||
+			strings.EqualFold(header.Name, "Content-Length") || strings.EqualFold(header.Name, "Transfer-Encoding") {
+			continue
+		}
+		fmt.Fprintf(buffer, "%s: %s\r\n", header.Name, header.Value)
+	}
+}
@@ -33,6 +33,8 @@ var RequestPartDefinitions = map[string]string{
 	"all":                   "HTTP response body + headers",
 	"cookies_from_response": "HTTP response cookies in name:value format",
 	"headers_from_response": "HTTP response headers in name:value format",
+	"position":              "Position of the response in a har (entry), warc (record) or pcap (packet) capture",
+	"url":                   "URL of the request captured in a har, warc or pcap capture",
 }
 
 // GetID returns the unique ID of the request if any.
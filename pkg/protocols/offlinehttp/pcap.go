@@ -0,0 +1,249 @@
+package offlinehttp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/pkg/errors"
+)
+
+// pcapngMagic is the block type of the section header starting pcapng files
+var pcapngMagic = []byte{0x0a, 0x0d, 0x0d, 0x0a}
+
+// packetReader reads the packets of a pcap or pcapng file
+type packetReader interface {
+	ReadPacketData() ([]byte, gopacket.CaptureInfo, error)
+	LinkType() layers.LinkType
+}
+
+// tcpSegment is a tcp segment carrying data
+type tcpSegment struct {
+	seq     uint32
+	payload []byte
+	packet  int
+}
+
+// tcpStream is one direction of a tcp connection
+type tcpStream struct {
+	src, dst string
+	isn      uint32
+	hasISN   bool
+	segments []tcpSegment
+
+	data []byte
+	// offsets contains the offset in data at which the segment of each packet starts
+	offsets []int
+	packets []int
+
+	// size is the size of the captured payloads of the stream
+	size int
+	// oversized is true if the payloads exceeded the max size and were dropped
+	oversized bool
+	// head is the start of the payload with the lowest sequence number
+	head    []byte
+	headSeq uint32
+}
+
+// readPCAP reads the http exchanges of the tcp connections of a pcap or
+// pcapng file. Connections are reassembled in memory and the HTTP/1.x
+// requests sent by the client are paired in order with the responses.
+// Streams with more than maxSize bytes of payloads are skipped.
+func readPCAP(reader io.Reader, maxSize int, callback func(*exchange)) error {
+	bufReader := bufio.NewReader(reader)
+	var packets packetReader
+	if magic, _ := bufReader.Peek(4); bytes.Equal(magic, pcapngMagic) {
+		ngReader, err := pcapgo.NewNgReader(bufReader, pcapgo.DefaultNgReaderOptions)
+		if err != nil {
+			return errors.Wrap(err, "could not read pcapng file")
+		}
+		packets = ngReader
+	} else {
+		pcapReader, err := pcapgo.NewReader(bufReader)
+		if err != nil {
+			return errors.Wrap(err, "could not read pcap file")
+		}
+		packets = pcapReader
+	}
+
+	streams := make(map[string]*tcpStream)
+	var order []*tcpStream
+	for number := 1; ; number++ {
+		data, _, err := packets.ReadPacketData()
+		if err != nil {
+			// captures cut while writing are processed up to the last packet
+			if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return errors.Wrapf(err, "could not read packet %d", number)
+		}
+		packet := gopacket.NewPacket(data, packets.LinkType(), gopacket.DecodeOptions{Lazy: true, NoCopy: true})
+		network := packet.NetworkLayer()
+		tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP)
+		if network == nil || !ok {
+			continue
+		}
+		flow := network.NetworkFlow()
+		src := net.JoinHostPort(flow.Src().String(), strconv.Itoa(int(tcp.SrcPort)))
+		dst := net.JoinHostPort(flow.Dst().String(), strconv.Itoa(int(tcp.DstPort)))
+
+		stream, ok := streams[src+">"+dst]
+		if !ok {
+			stream = &tcpStream{src: src, dst: dst}
+			streams[src+">"+dst] = stream
+			order = append(order, stream)
+		}
+		if tcp.SYN {
+			stream.isn, stream.hasISN = tcp.Seq+1, true
+		}
+		if len(tcp.Payload) == 0 || stream.oversized {
+			continue
+		}
+		if stream.size == 0 || int32(tcp.Seq-stream.headSeq) < 0 {
+			stream.head = append([]byte(nil), tcp.Payload[:min(len(tcp.Payload), 5)]...)
+			stream.headSeq = tcp.Seq
+		}
+		if stream.size += len(tcp.Payload); stream.size > maxSize {
+			stream.oversized, stream.segments = true, nil
+			continue
+		}
+		stream.segments = append(stream.segments, tcpSegment{seq: tcp.Seq, payload: append([]byte(nil), tcp.Payload...), packet: number})
+	}
+
+	for _, stream := range order {
+		stream.reassemble()
+	}
+	for _, server := range order {
+		if server.oversized {
+			if bytes.HasPrefix(server.head, []byte("HTTP/")) {
+				callback(&exchange{position: fmt.Sprintf("stream=%s>%s", server.src, server.dst), err: errExceededMaxSize})
+			}
+			continue
+		}
+		if !bytes.HasPrefix(server.data, []byte("HTTP/")) {
+			continue
+		}
+		client := streams[server.dst+">"+server.src]
+		readTCPExchanges(client, server, callback)
+	}
+	return nil
+}
+
+// reassemble orders the segments of the stream by sequence number into its
+// data, dropping retransmissions. Data following a missing segment is dropped.
+func (stream *tcpStream) reassemble() {
+	if len(stream.segments) == 0 {
+		return
+	}
+	base := stream.isn
+	if !stream.hasISN {
+		base = stream.segments[0].seq
+		for _, segment := range stream.segments[1:] {
+			// sequence numbers wrap around so they are compared by their distance
+			if int32(segment.seq-base) < 0 {
+				base = segment.seq
+			}
+		}
+	}
+	sort.SliceStable(stream.segments, func(i, j int) bool {
+		return stream.segments[i].seq-base < stream.segments[j].seq-base
+	})
+	for _, segment := range stream.segments {
+		offset := int(segment.seq - base)
+		end := offset + len(segment.payload)
+		if offset > len(stream.data) {
+			break
+		}
+		if end <= len(stream.data) {
+			continue
+		}
+		stream.offsets = append(stream.offsets, len(stream.data))
+		stream.packets = append(stream.packets, segment.packet)
+		stream.data = append(stream.data, segment.payload[len(stream.data)-offset:]...)
+	}
+	stream.segments = nil
+}
+
+// packetAt returns the number of the packet carrying the data at offset
+func (stream *tcpStream) packetAt(offset int) int {
+	index := sort.Search(len(stream.offsets), func(i int) bool { return stream.offsets[i] > offset }) - 1
+	if index < 0 {
+		return 0
+	}
+	return stream.packets[index]
+}
+
+// streamReader reads http messages from a stream keeping track of their offsets
+type streamReader struct {
+	data   []byte
+	source *bytes.Reader
+	*bufio.Reader
+}
+
+func newStreamReader(data []byte) *streamReader {
+	source := bytes.NewReader(data)
+	return &streamReader{data: data, source: source, Reader: bufio.NewReader(source)}
+}
+
+// offset returns the offset in data of the next unread byte
+func (reader *streamReader) offset() int {
+	return len(reader.data) - reader.source.Len() - reader.Buffered()
+}
+
+// readTCPExchanges reads the responses sent by the server paired with the
+// requests sent by the client, if the client side was captured.
+func readTCPExchanges(client, server *tcpStream, callback func(*exchange)) {
+	serverIP, _, _ := net.SplitHostPort(server.src)
+	responses := newStreamReader(server.data)
+	var requests *streamReader
+	if client != nil {
+		requests = newStreamReader(client.data)
+	}
+
+	for {
+		var req *http.Request
+		var rawRequest []byte
+		if requests != nil {
+			start := requests.offset()
+			var err error
+			if req, err = http.ReadRequest(requests.Reader); err != nil {
+				// responses to requests missing from the capture are read alone
+				requests = nil
+			} else {
+				_, _ = io.Copy(io.Discard, req.Body)
+				rawRequest = client.data[start:requests.offset()]
+			}
+		}
+
+		start := responses.offset()
+		resp, err := http.ReadResponse(responses.Reader, req)
+		// informational responses precede the final response to the request
+		for err == nil && resp.StatusCode >= 100 && resp.StatusCode < 200 && resp.StatusCode != http.StatusSwitchingProtocols {
+			start = responses.offset()
+			resp, err = http.ReadResponse(responses.Reader, req)
+		}
+		if err != nil {
+			return
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		callback(&exchange{
+			request:  rawRequest,
+			response: server.data[start:responses.offset()],
+			position: fmt.Sprintf("packet=%d", server.packetAt(start)),
+			ip:       serverIP,
+		})
+		if resp.StatusCode == http.StatusSwitchingProtocols {
+			return
+		}
+	}
+}
@@ -2,6 +2,7 @@ package offlinehttp
 
 import (
 	"io"
+	"net/http"
 	"net/http/httputil"
 	"os"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
+	httpProtocol "github.com/projectdiscovery/nuclei/v3/pkg/protocols/http"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
 	"github.com/projectdiscovery/utils/conversion"
@@ -42,6 +44,11 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 		go func(data string) {
 			defer wg.Done()
 
+			if captureFormat(data) != "" {
+				request.executeCapture(input, data, previous, callback)
+				return
+			}
+
 			file, err := os.Open(data)
 			if err != nil {
 				gologger.Error().Msgf("Could not open file path %s: %s\n", data, err)
@@ -78,31 +85,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 			}
 			gologger.Verbose().Msgf("[%s] Sent OFFLINE-HTTP request to %s", request.options.TemplateID, data)
 
-			dumpedResponse, err := httputil.DumpResponse(resp, true)
-			if err != nil {
-				gologger.Error().Msgf("Could not dump raw http response %s: %s\n", data, err)
-				return
-			}
-
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				gologger.Error().Msgf("Could not read raw http response body %s: %s\n", data, err)
-				return
-			}
-
-			outputEvent := request.responseToDSLMap(resp, data, data, data, conversion.String(dumpedResponse), conversion.String(body), utils.HeadersToString(resp.Header), 0, nil)
-			// add response fields to template context and merge templatectx variables to output event
-			request.options.AddTemplateVars(input.MetaInput, request.Type(), request.GetID(), outputEvent)
-			if request.options.HasTemplateCtx(input.MetaInput) {
-				outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
-			}
-			outputEvent["ip"] = ""
-			for k, v := range previous {
-				outputEvent[k] = v
-			}
-
-			event := eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
-			callback(event)
+			request.executeResponse(input, resp, data, data, data, "", nil, previous, callback)
 		}(data)
 	})
 	wg.Wait()
@@ -114,3 +97,75 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, metadata,
 	request.options.Progress.IncrementRequests()
 	return nil
 }
+
+// executeCapture runs the operators on the http exchanges of a capture file.
+// Results are matched at the position of the response in the capture.
+func (request *Request) executeCapture(input *contextargs.Context, path string, previous output.InternalEvent, callback protocols.OutputEventCallback) {
+	maxSize := request.maxBodyRead()
+	err := readCapture(path, maxSize, func(item *exchange) {
+		if item.err != nil {
+			gologger.Warning().Msgf("Skipping response at %s#%s: %s\n", path, item.position, item.err)
+			return
+		}
+		resp, err := item.parse(maxSize)
+		if errors.Is(err, errExceededMaxSize) {
+			gologger.Warning().Msgf("Skipping response at %s#%s: %s\n", path, item.position, err)
+			return
+		}
+		if err != nil {
+			gologger.Verbose().Msgf("Could not read response at %s#%s: %s\n", path, item.position, err)
+			return
+		}
+		matched := path + "#" + item.position
+
+		if request.options.Options.Debug || request.options.Options.DebugRequests {
+			gologger.Info().Msgf("[%s] Dumped offline-http request for %s", request.options.TemplateID, matched)
+			gologger.Print().Msgf("%s", item.request)
+		}
+		gologger.Verbose().Msgf("[%s] Sent OFFLINE-HTTP request to %s", request.options.TemplateID, matched)
+
+		extra := map[string]interface{}{"position": item.position, "url": item.url}
+		request.executeResponse(input, resp, path, matched, conversion.String(item.request), item.ip, extra, previous, callback)
+	})
+	if err != nil {
+		gologger.Error().Msgf("Could not read capture %s: %s\n", path, err)
+	}
+}
+
+// maxBodyRead returns the maximum size of the responses read from captures,
+// the same as the one of responses of live http requests
+func (request *Request) maxBodyRead() int {
+	if request.options.Options.ResponseReadSize != 0 {
+		return request.options.Options.ResponseReadSize
+	}
+	return httpProtocol.MaxBodyRead
+}
+
+// executeResponse runs the operators on the response read from path
+func (request *Request) executeResponse(input *contextargs.Context, resp *http.Response, path, matched, rawRequest, ip string, extra map[string]interface{}, previous output.InternalEvent, callback protocols.OutputEventCallback) {
+	dumpedResponse, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		gologger.Error().Msgf("Could not dump raw http response %s: %s\n", matched, err)
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		gologger.Error().Msgf("Could not read raw http response body %s: %s\n", matched, err)
+		return
+	}
+
+	outputEvent := request.responseToDSLMap(resp, path, matched, rawRequest, conversion.String(dumpedResponse), conversion.String(body), utils.HeadersToString(resp.Header), 0, extra)
+	// add response fields to template context and merge templatectx variables to output event
+	request.options.AddTemplateVars(input.MetaInput, request.Type(), request.GetID(), outputEvent)
+	if request.options.HasTemplateCtx(input.MetaInput) {
+		outputEvent = generators.MergeMaps(outputEvent, request.options.GetTemplateCtx(input.MetaInput).GetAll())
+	}
+	outputEvent["ip"] = ip
+	for k, v := range previous {
+		outputEvent[k] = v
+	}
+
+	event := eventcreator.CreateEvent(request, outputEvent, request.options.Options.Debug || request.options.Options.DebugResponse)
+	callback(event)
+}
@@ -0,0 +1,154 @@
+package offlinehttp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// warcRecord is a record of a WARC (Web ARChive) file
+type warcRecord struct {
+	header textproto.MIMEHeader
+	block  []byte
+	// oversized is true if the block was skipped as it exceeded the max size
+	oversized bool
+}
+
+// ids returns the record id and the ids of the records concurrent to it
+func (record *warcRecord) ids() []string {
+	return append([]string{record.header.Get("WARC-Record-ID")}, record.header.Values("WARC-Concurrent-To")...)
+}
+
+// warcPending is a response record waiting for its request record
+type warcPending struct {
+	exchange *exchange
+	emitted  bool
+}
+
+// readWARC reads the http exchanges of the response records of a WARC file.
+//
+// Request and response records are paired through their WARC-Concurrent-To
+// header in either order. Responses without a request record are read once
+// the whole file has been processed.
+func readWARC(reader io.Reader, maxSize int, callback func(*exchange)) error {
+	bufReader := bufio.NewReader(reader)
+	requests := make(map[string][]byte)
+	responses := make(map[string]*warcPending)
+	var pending []*warcPending
+
+	for index := 0; ; index++ {
+		record, err := readWARCRecord(bufReader, maxSize)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not read warc record %d", index)
+		}
+		if !strings.HasPrefix(record.header.Get("Content-Type"), "application/http") {
+			continue
+		}
+		if record.oversized && record.header.Get("WARC-Type") == "response" {
+			callback(&exchange{position: fmt.Sprintf("record=%d", index), url: record.header.Get("WARC-Target-URI"), err: errExceededMaxSize})
+			continue
+		}
+		if record.block == nil {
+			continue
+		}
+
+		switch record.header.Get("WARC-Type") {
+		case "request":
+			for _, id := range record.ids() {
+				if id == "" {
+					continue
+				}
+				requests[id] = record.block
+				if response, ok := responses[id]; ok && !response.emitted {
+					response.exchange.request = record.block
+					response.emitted = true
+					callback(response.exchange)
+				}
+			}
+		case "response":
+			item := &exchange{
+				response: record.block,
+				position: fmt.Sprintf("record=%d", index),
+				url:      record.header.Get("WARC-Target-URI"),
+				ip:       record.header.Get("WARC-IP-Address"),
+			}
+			if request, ok := findWARCRequest(requests, record.ids()); ok {
+				item.request = request
+				callback(item)
+				continue
+			}
+			response := &warcPending{exchange: item}
+			for _, id := range record.ids() {
+				if id != "" {
+					responses[id] = response
+				}
+			}
+			pending = append(pending, response)
+		}
+	}
+	for _, response := range pending {
+		if !response.emitted {
+			callback(response.exchange)
+		}
+	}
+	return nil
+}
+
+// findWARCRequest returns the request record concurrent to one of the ids
+func findWARCRequest(requests map[string][]byte, ids []string) ([]byte, bool) {
+	for _, id := range ids {
+		if request, ok := requests[id]; ok && id != "" {
+			return request, true
+		}
+	}
+	return nil, false
+}
+
+// readWARCRecord reads the next record skipping the blank lines separating records
+func readWARCRecord(reader *bufio.Reader, maxSize int) (*warcRecord, error) {
+	var version string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && strings.TrimSpace(line) == "" {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		if version = strings.TrimSpace(line); version != "" {
+			break
+		}
+	}
+	if !strings.HasPrefix(version, "WARC/") {
+		return nil, errors.Errorf("invalid warc version line %q", version)
+	}
+
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read warc headers")
+	}
+	length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil || length < 0 {
+		return nil, errors.New("invalid warc content length")
+	}
+	// blocks exceeding the max response size are skipped
+	if length > int64(maxSize) {
+		if _, err := io.CopyN(io.Discard, reader, length); err != nil {
+			return nil, errors.Wrap(err, "could not read warc record block")
+		}
+		return &warcRecord{header: header, oversized: true}, nil
+	}
+	block := make([]byte, length)
+	if _, err := io.ReadFull(reader, block); err != nil {
+		return nil, errors.Wrap(err, "could not read warc record block")
+	}
+	return &warcRecord{header: header, block: block}, nil
+}
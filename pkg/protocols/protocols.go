@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"sync/atomic"
+	"time"
 
 	"github.com/projectdiscovery/ratelimit"
 	mapsutil "github.com/projectdiscovery/utils/maps"
@@ -27,13 +28,16 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/globalmatchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hostratelimit"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/variables"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/headless/engine"
 	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	unitutils "github.com/projectdiscovery/utils/unit"
 )
@@ -76,10 +80,14 @@ type ExecutorOptions struct {
 	Progress progress.Progress
 	// RateLimiter is a rate-limiter for limiting sent number of requests.
 	RateLimiter *ratelimit.Limiter
+	// HostRateLimiter is an optional per-host rate-limiter of http requests adapting to throttling responses
+	HostRateLimiter *hostratelimit.Limiter
 	// Catalog is a template catalog implementation for nuclei
 	Catalog catalog.Catalog
 	// ProjectFile is the project file for nuclei
 	ProjectFile *projectfile.ProjectFile
+	// TrafficArchive is the archive protocol exchanges are recorded into or replayed from
+	TrafficArchive *traffic.Archive
 	// Browser is a browser engine for running headless templates
 	Browser *engine.Browser
 	// Interactsh is a client for interactsh oob polling server
@@ -99,6 +107,9 @@ type ExecutorOptions struct {
 	InputHelper *input.Helper
 	// FuzzParamsFrequency is a cache for parameter frequency
 	FuzzParamsFrequency *frequency.Tracker
+	// AltSvcCache is a cache of the HTTP/3 endpoints advertised by hosts
+	// with the Alt-Svc header (see httpclientpool.AltSvcCache)
+	AltSvcCache *mapsutil.SyncLockMap[string, string]
 
 	Operators []*operators.Operators // only used by offlinehttp module
 
@@ -139,7 +150,9 @@ func (eo *ExecutorOptions) RateLimitTake() {
 		eo.RateLimiter.SetLimit(uint(eo.Options.RateLimit))
 		eo.RateLimiter.SetDuration(eo.Options.RateLimitDuration)
 	}
+	started := time.Now()
 	eo.RateLimiter.Take()
+	telemetry.ObserveRateLimitWait("global", time.Since(started))
 }
 
 // GetThreadsForPayloadRequests returns the number of threads to use as default for
@@ -331,6 +344,8 @@ func MakeDefaultExtractFunc(data map[string]interface{}, extractor *extractors.E
 	switch extractor.GetType() {
 	case extractors.RegexExtractor:
 		return extractor.ExtractRegex(itemStr)
+	case extractors.EntropyExtractor:
+		return extractor.ExtractEntropy(itemStr)
 	case extractors.KValExtractor:
 		return extractor.ExtractKval(data)
 	case extractors.JSONExtractor:
@@ -0,0 +1,270 @@
+package ssl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	gojarm "github.com/hdm/jarm-go"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+)
+
+const (
+	recordTypeHandshake      = 22
+	handshakeTypeServerHello = 2
+
+	extensionALPN              = 0x0010
+	extensionSupportedVersions = 0x002b
+)
+
+// ja4sVersions contains the JA4S codes of tls versions
+var ja4sVersions = map[uint16]string{
+	tls.VersionTLS13: "13",
+	tls.VersionTLS12: "12",
+	tls.VersionTLS11: "11",
+	tls.VersionTLS10: "10",
+	0x0300:           "s3",
+}
+
+// fingerprintJA4S sends a TLS 1.3 client hello with ALPN to the host and
+// returns the JA4S fingerprint of the server hello, or replays the recorded
+// server hello from the traffic archive.
+func (request *Request) fingerprintJA4S(host, hostIp, port string) (string, error) {
+	archive := request.options.TrafficArchive
+	address := net.JoinHostPort(host, port)
+	key := traffic.Key{Protocol: request.Type().String(), TemplateID: request.options.TemplateID, Target: address}
+	probeRequest := []byte("ja4s " + net.JoinHostPort(hostIp, port))
+	if archive.Replaying() {
+		exchange, err := archive.Replay(key, probeRequest)
+		if err != nil {
+			return "", err
+		}
+		if err := exchange.Err(); err != nil {
+			return "", err
+		}
+		return JA4S(exchange.Response)
+	}
+
+	serverHello, err := request.probeServerHello(host, hostIp, port)
+	if archive.Recording() {
+		if recordErr := archive.Record(key, probeRequest, serverHello, err); recordErr != nil {
+			gologger.Warning().Msgf("[%s] Could not record response in traffic archive: %s\n", request.options.TemplateID, recordErr)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return JA4S(serverHello)
+}
+
+// probeServerHello returns the raw tls record containing the server hello
+// sent by the host in response to a TLS 1.3 client hello with ALPN.
+func (request *Request) probeServerHello(host, hostIp, port string) ([]byte, error) {
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid port")
+	}
+	timeout := time.Duration(request.options.Options.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := request.dialer.Dial(ctx, "tcp", net.JoinHostPort(hostIp, port))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to server")
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	probe := gojarm.JarmProbeOptions{Hostname: host, Port: portNumber, Version: tls.VersionTLS13, Ciphers: "ALL", CipherOrder: "FORWARD", Grease: "NO_GREASE", ALPN: "ALPN", V13Mode: "1.3_SUPPORT", ExtensionOrder: "FORWARD"}
+	if _, err := conn.Write(gojarm.BuildProbe(probe)); err != nil {
+		return nil, errors.Wrap(err, "could not write client hello")
+	}
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, errors.Wrap(err, "could not read server hello")
+	}
+	record := make([]byte, 5+int(binary.BigEndian.Uint16(header[3:5])))
+	copy(record, header)
+	if _, err := io.ReadFull(conn, record[5:]); err != nil {
+		return nil, errors.Wrap(err, "could not read server hello")
+	}
+	return record, nil
+}
+
+// JA4S returns the JA4S fingerprint of the server hello in a raw tls record.
+//
+// The fingerprint has the format t<version><extension count><alpn>_<cipher>_<extensions hash>
+// where the hash is the truncated sha256 of the extensions in the order sent by the server.
+func JA4S(record []byte) (string, error) {
+	if len(record) < 5 || record[0] != recordTypeHandshake {
+		return "", errors.New("server did not respond with a handshake")
+	}
+	body := record[5:]
+	if len(body) < 4 || body[0] != handshakeTypeServerHello {
+		return "", errors.New("server did not respond with a server hello")
+	}
+	length := int(body[1])<<16 | int(body[2])<<8 | int(body[3])
+	if len(body) < 4+length {
+		return "", errors.New("truncated server hello")
+	}
+	message := body[4 : 4+length]
+
+	// version (2) + random (32) + session id length (1)
+	if len(message) < 35 {
+		return "", errors.New("truncated server hello")
+	}
+	version := binary.BigEndian.Uint16(message[0:2])
+	offset := 35 + int(message[34])
+	// cipher suite (2) + compression method (1)
+	if len(message) < offset+3 {
+		return "", errors.New("truncated server hello")
+	}
+	cipher := message[offset : offset+2]
+	offset += 3
+
+	var extensions []string
+	var alpn string
+	if len(message) >= offset+2 {
+		extensionsEnd := offset + 2 + int(binary.BigEndian.Uint16(message[offset:offset+2]))
+		offset += 2
+		for offset+4 <= extensionsEnd && offset+4 <= len(message) {
+			extensionType := binary.BigEndian.Uint16(message[offset : offset+2])
+			extensionLength := int(binary.BigEndian.Uint16(message[offset+2 : offset+4]))
+			offset += 4
+			if offset+extensionLength > len(message) {
+				return "", errors.New("truncated server hello extension")
+			}
+			data := message[offset : offset+extensionLength]
+			offset += extensionLength
+
+			extensions = append(extensions, fmt.Sprintf("%04x", extensionType))
+			switch extensionType {
+			case extensionSupportedVersions:
+				if len(data) == 2 {
+					version = binary.BigEndian.Uint16(data)
+				}
+			case extensionALPN:
+				// protocol name list length (2) + protocol length (1)
+				if len(data) > 3 && len(data) >= 3+int(data[2]) {
+					alpn = string(data[3 : 3+int(data[2])])
+				}
+			}
+		}
+	}
+
+	versionCode, ok := ja4sVersions[version]
+	if !ok {
+		versionCode = "00"
+	}
+	alpnCode := "00"
+	if alpn != "" {
+		alpnCode = string(alpn[0]) + string(alpn[len(alpn)-1])
+	}
+	extensionsCount := len(extensions)
+	if extensionsCount > 99 {
+		extensionsCount = 99
+	}
+	hash := sha256.Sum256([]byte(strings.Join(extensions, ",")))
+	return fmt.Sprintf("t%s%02d%s_%s_%s", versionCode, extensionsCount, alpnCode, hex.EncodeToString(cipher), hex.EncodeToString(hash[:])[:12]), nil
+}
+
+// ChainValidation contains the validation details of a certificate chain
+type ChainValidation struct {
+	// Valid is true if the chain is trusted and valid for the hostname
+	Valid bool
+	// Error is the error returned by the chain verification if any
+	Error string
+	// UntrustedRoot is true if the chain does not lead to a trusted root
+	UntrustedRoot bool
+	// HostnameMismatch is true if the leaf certificate is not valid for the hostname
+	HostnameMismatch bool
+	// WeakSignature is true if a certificate of the chain is signed with md5 or sha1
+	WeakSignature bool
+	// SignatureAlgorithms are the signature algorithms of the chain starting at the leaf
+	SignatureAlgorithms []string
+}
+
+// weakSignatureAlgorithms contains the signature algorithms considered weak
+var weakSignatureAlgorithms = map[x509.SignatureAlgorithm]struct{}{
+	x509.MD2WithRSA:    {},
+	x509.MD5WithRSA:    {},
+	x509.SHA1WithRSA:   {},
+	x509.DSAWithSHA1:   {},
+	x509.ECDSAWithSHA1: {},
+}
+
+// ValidateChain validates the certificate chain of the response for hostname
+// against the system roots.
+func ValidateChain(hostname string, response *clients.Response) (*ChainValidation, error) {
+	if response.CertificateResponse == nil || response.Certificate == "" {
+		return nil, errors.New("no certificate in response")
+	}
+	leaf, err := parseCertificate(response.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	certificates := []*x509.Certificate{leaf}
+	intermediates := x509.NewCertPool()
+	for _, item := range response.Chain {
+		if item == nil || item.Certificate == "" {
+			continue
+		}
+		certificate, err := parseCertificate(item.Certificate)
+		if err != nil {
+			continue
+		}
+		certificates = append(certificates, certificate)
+		intermediates.AddCert(certificate)
+	}
+
+	validation := &ChainValidation{}
+	for _, certificate := range certificates {
+		validation.SignatureAlgorithms = append(validation.SignatureAlgorithms, certificate.SignatureAlgorithm.String())
+		// signatures of self-signed roots are not verified by clients
+		if _, ok := weakSignatureAlgorithms[certificate.SignatureAlgorithm]; ok && !isSelfSigned(certificate) {
+			validation.WeakSignature = true
+		}
+	}
+	validation.HostnameMismatch = leaf.VerifyHostname(hostname) != nil
+
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: hostname, Intermediates: intermediates})
+	if err != nil {
+		validation.Error = err.Error()
+		var unknownAuthorityErr x509.UnknownAuthorityError
+		if errors.As(err, &unknownAuthorityErr) {
+			validation.UntrustedRoot = true
+		}
+	}
+	validation.Valid = err == nil
+	return validation, nil
+}
+
+// parseCertificate parses a pem encoded certificate
+func parseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("could not decode certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// isSelfSigned returns true if the certificate is issued by itself
+func isSelfSigned(certificate *x509.Certificate) bool {
+	return bytes.Equal(certificate.RawSubject, certificate.RawIssuer)
+}
@@ -93,6 +93,16 @@ type Request struct {
 	//   - "secure"
 	//   - "all"
 	TLSCipherTypes []string `yaml:"tls_cipher_types,omitempty" json:"tls_cipher_types,omitempty" jsonschema:"title=TLS Cipher Types,description=TLS Cipher Types to enumerate,enum=weak,enum=secure,enum=insecure,enum=all"`
+	// description: |
+	//   Fingerprint - false if not specified
+	//   Performs the probe handshakes to compute the JARM (jarm_hash)
+	//   and JA4S (ja4s) fingerprints of the server
+	Fingerprint bool `yaml:"fingerprint,omitempty" json:"fingerprint,omitempty" jsonschema:"title=Fingerprint Server,description=Compute JARM and JA4S server fingerprints - false if not specified"`
+	// description: |
+	//   Validate Chain - false if not specified
+	//   Validates the certificate chain against the system roots, reporting
+	//   untrusted roots, hostname mismatches and weak signatures
+	ValidateChain bool `yaml:"validate_chain,omitempty" json:"validate_chain,omitempty" jsonschema:"title=Validate Certificate Chain,description=Validate the certificate chain - false if not specified"`
 
 	// cache any variables that may be needed for operation.
 	dialer  *fastdialer.Dialer
@@ -103,7 +113,7 @@ type Request struct {
 // TmplClusterKey generates a unique key for the request
 // to be used in the clustering process.
 func (request *Request) TmplClusterKey() uint64 {
-	inp := fmt.Sprintf("%s-%s-%t-%t-%s", request.Address, request.ScanMode, request.TLSCiphersEnum, request.TLSVersionsEnum, strings.Join(request.TLSCipherTypes, ","))
+	inp := fmt.Sprintf("%s-%s-%t-%t-%s-%t-%t", request.Address, request.ScanMode, request.TLSCiphersEnum, request.TLSVersionsEnum, strings.Join(request.TLSCipherTypes, ","), request.Fingerprint, request.ValidateChain)
 	return xxhash.Sum64String(inp)
 }
 
@@ -161,6 +171,10 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		TlsVersionsEnum:   request.TLSVersionsEnum,
 		TlsCiphersEnum:    request.TLSCiphersEnum,
 		TLsCipherLevel:    request.TLSCipherTypes,
+		Jarm:              request.Fingerprint,
+		Ja3s:              request.Fingerprint,
+		Cert:              request.ValidateChain,
+		TLSChain:          request.ValidateChain,
 	}
 
 	tlsxService, err := tlsx.New(tlsxOptions)
@@ -244,7 +258,7 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		hostIp = host
 	}
 
-	response, err := request.tlsx.Connect(host, hostIp, port)
+	response, err := request.connect(input.Context(), host, hostIp, port)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input.MetaInput.Input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
@@ -324,6 +338,33 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		data[tag] = f.Value()
 	}
 
+	if request.Fingerprint {
+		if ja4s, err := request.fingerprintJA4S(host, hostIp, port); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not compute ja4s fingerprint for %s: %s\n", request.options.TemplateID, addressToDial, err)
+		} else {
+			request.options.AddTemplateVar(input.MetaInput, request.Type(), request.ID, "ja4s", ja4s)
+			data["ja4s"] = ja4s
+		}
+	}
+	if request.ValidateChain {
+		if validation, err := ValidateChain(host, response); err != nil {
+			gologger.Verbose().Msgf("[%s] Could not validate certificate chain for %s: %s\n", request.options.TemplateID, addressToDial, err)
+		} else {
+			validationData := map[string]interface{}{
+				"chain_valid":          validation.Valid,
+				"chain_error":          validation.Error,
+				"untrusted_root":       validation.UntrustedRoot,
+				"hostname_mismatch":    validation.HostnameMismatch,
+				"weak_signature":       validation.WeakSignature,
+				"signature_algorithms": validation.SignatureAlgorithms,
+			}
+			for k, v := range validationData {
+				request.options.AddTemplateVar(input.MetaInput, request.Type(), request.ID, k, v)
+				data[k] = v
+			}
+		}
+	}
+
 	// add response fields ^ to template context and merge templatectx variables to output event
 	if request.options.HasTemplateCtx(input.MetaInput) {
 		data = generators.MergeMaps(data, request.options.GetTemplateCtx(input.MetaInput).GetAll())
@@ -347,33 +388,42 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"template-id":      "ID of the template executed",
-	"template-info":    "Info Block of the template executed",
-	"template-path":    "Path of the template executed",
-	"host":             "Host is the input to the template",
-	"port":             "Port is the port of the host",
-	"matched":          "Matched is the input which was matched upon",
-	"type":             "Type is the type of request made",
-	"timestamp":        "Timestamp is the time when the request was made",
-	"response":         "JSON SSL protocol handshake details",
-	"cipher":           "Cipher is the encryption algorithm used",
-	"domains":          "Domains are the list of domain names in the certificate",
-	"fingerprint_hash": "Fingerprint hash is the unique identifier of the certificate",
-	"ip":               "IP is the IP address of the server",
-	"issuer_cn":        "Issuer CN is the common name of the certificate issuer",
-	"issuer_dn":        "Issuer DN is the distinguished name of the certificate issuer",
-	"issuer_org":       "Issuer organization is the organization of the certificate issuer",
-	"not_after":        "Timestamp after which the remote cert expires",
-	"not_before":       "Timestamp before which the certificate is not valid",
-	"probe_status":     "Probe status indicates if the probe was successful",
-	"serial":           "Serial is the serial number of the certificate",
-	"sni":              "SNI is the server name indication used in the handshake",
-	"subject_an":       "Subject AN is the list of subject alternative names",
-	"subject_cn":       "Subject CN is the common name of the certificate subject",
-	"subject_dn":       "Subject DN is the distinguished name of the certificate subject",
-	"subject_org":      "Subject organization is the organization of the certificate subject",
-	"tls_connection":   "TLS connection is the type of TLS connection used",
-	"tls_version":      "TLS version is the version of the TLS protocol used",
+	"template-id":          "ID of the template executed",
+	"template-info":        "Info Block of the template executed",
+	"template-path":        "Path of the template executed",
+	"host":                 "Host is the input to the template",
+	"port":                 "Port is the port of the host",
+	"matched":              "Matched is the input which was matched upon",
+	"type":                 "Type is the type of request made",
+	"timestamp":            "Timestamp is the time when the request was made",
+	"response":             "JSON SSL protocol handshake details",
+	"cipher":               "Cipher is the encryption algorithm used",
+	"domains":              "Domains are the list of domain names in the certificate",
+	"fingerprint_hash":     "Fingerprint hash is the unique identifier of the certificate",
+	"ip":                   "IP is the IP address of the server",
+	"issuer_cn":            "Issuer CN is the common name of the certificate issuer",
+	"issuer_dn":            "Issuer DN is the distinguished name of the certificate issuer",
+	"issuer_org":           "Issuer organization is the organization of the certificate issuer",
+	"not_after":            "Timestamp after which the remote cert expires",
+	"not_before":           "Timestamp before which the certificate is not valid",
+	"probe_status":         "Probe status indicates if the probe was successful",
+	"serial":               "Serial is the serial number of the certificate",
+	"sni":                  "SNI is the server name indication used in the handshake",
+	"subject_an":           "Subject AN is the list of subject alternative names",
+	"subject_cn":           "Subject CN is the common name of the certificate subject",
+	"subject_dn":           "Subject DN is the distinguished name of the certificate subject",
+	"subject_org":          "Subject organization is the organization of the certificate subject",
+	"tls_connection":       "TLS connection is the type of TLS connection used",
+	"tls_version":          "TLS version is the version of the TLS protocol used",
+	"jarm_hash":            "JARM hash is the JARM fingerprint of the server (fingerprint)",
+	"ja3s_hash":            "JA3S hash is the JA3S fingerprint of the server hello (fingerprint, ztls only)",
+	"ja4s":                 "JA4S is the JA4S fingerprint of the server hello (fingerprint)",
+	"chain_valid":          "Chain valid is true if the certificate chain is trusted and valid for the host (validate_chain)",
+	"chain_error":          "Chain error is the error of the certificate chain verification (validate_chain)",
+	"untrusted_root":       "Untrusted root is true if the certificate chain does not lead to a trusted root (validate_chain)",
+	"hostname_mismatch":    "Hostname mismatch is true if the certificate is not valid for the host (validate_chain)",
+	"weak_signature":       "Weak signature is true if a certificate of the chain is signed with md5 or sha1 (validate_chain)",
+	"signature_algorithms": "Signature algorithms of the certificate chain starting at the leaf (validate_chain)",
 }
 
 // Match performs matching operation for a matcher on model and returns:
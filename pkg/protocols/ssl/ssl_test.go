@@ -2,7 +2,15 @@ package ssl
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -11,6 +19,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
 	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
 )
 
 func TestSSLProtocol(t *testing.T) {
@@ -36,3 +45,51 @@ func TestSSLProtocol(t *testing.T) {
 	require.Nil(t, err, "could not run ssl request")
 	require.NotEmpty(t, gotEvent, "could not get event items")
 }
+
+func TestJA4S(t *testing.T) {
+	// tls 1.3 server hello with the key_share and supported_versions extensions
+	message := []byte{0x03, 0x03}
+	message = append(message, make([]byte, 32)...)
+	message = append(message, 0x00, 0x13, 0x01, 0x00)
+	extensions := []byte{0x00, 0x33, 0x00, 0x02, 0x00, 0x1d, 0x00, 0x2b, 0x00, 0x02, 0x03, 0x04}
+	message = append(message, 0x00, byte(len(extensions)))
+	message = append(message, extensions...)
+	handshake := append([]byte{0x02, 0x00, 0x00, byte(len(message))}, message...)
+	record := append([]byte{0x16, 0x03, 0x03, 0x00, byte(len(handshake))}, handshake...)
+
+	ja4s, err := JA4S(record)
+	require.Nil(t, err, "could not compute ja4s")
+	require.Equal(t, "t130200_1301_234ea6891581", ja4s, "could not get correct ja4s")
+
+	_, err = JA4S([]byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28})
+	require.NotNil(t, err, "could compute ja4s of alert")
+}
+
+func TestValidateChain(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, "could not generate key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err, "could not create certificate")
+	response := &clients.Response{CertificateResponse: &clients.CertificateResponse{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}}
+
+	validation, err := ValidateChain("example.com", response)
+	require.Nil(t, err, "could not validate chain")
+	require.False(t, validation.Valid, "self-signed chain is valid")
+	require.True(t, validation.UntrustedRoot, "could not detect untrusted root")
+	require.False(t, validation.HostnameMismatch, "could not match hostname")
+	require.False(t, validation.WeakSignature, "sha256 signature is weak")
+	require.Equal(t, []string{"ECDSA-SHA256"}, validation.SignatureAlgorithms)
+
+	validation, err = ValidateChain("other.com", response)
+	require.Nil(t, err, "could not validate chain")
+	require.True(t, validation.HostnameMismatch, "could not detect hostname mismatch")
+}
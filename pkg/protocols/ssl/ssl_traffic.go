@@ -0,0 +1,50 @@
+package ssl
+
+import (
+	"context"
+	"net"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
+	"github.com/projectdiscovery/nuclei/v3/pkg/traffic"
+	"github.com/projectdiscovery/tlsx/pkg/tlsx/clients"
+)
+
+// connect performs the tls handshake with the host or replays its
+// recorded response from the traffic archive.
+func (request *Request) connect(ctx context.Context, host, hostIp, port string) (*clients.Response, error) {
+	archive := request.options.TrafficArchive
+	address := net.JoinHostPort(host, port)
+	key := traffic.Key{Protocol: request.Type().String(), TemplateID: request.options.TemplateID, Target: address}
+	if archive.Replaying() {
+		exchange, err := archive.Replay(key, []byte(net.JoinHostPort(hostIp, port)))
+		if err != nil {
+			return nil, err
+		}
+		if err := exchange.Err(); err != nil {
+			return nil, err
+		}
+		response := &clients.Response{}
+		if err := jsoniter.Unmarshal(exchange.Response, response); err != nil {
+			return nil, errors.Wrap(err, "could not parse recorded ssl response")
+		}
+		return response, nil
+	}
+
+	endCall := telemetry.StartCall(ctx, request.options.TemplateID, request.Type().String(), address)
+	response, err := request.tlsx.Connect(host, hostIp, port)
+	endCall(err)
+	if archive.Recording() {
+		var data []byte
+		if response != nil {
+			data, _ = jsoniter.Marshal(response)
+		}
+		if recordErr := archive.Record(key, []byte(net.JoinHostPort(hostIp, port)), data, err); recordErr != nil {
+			gologger.Warning().Msgf("[%s] Could not record response in traffic archive: %s\n", request.options.TemplateID, recordErr)
+		}
+	}
+	return response, err
+}
@@ -0,0 +1,221 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SocketIOFraming frames messages as socket.io (engine.io v4) packets
+	SocketIOFraming = "socketio"
+	// STOMPFraming frames messages as STOMP frames
+	STOMPFraming = "stomp"
+)
+
+const (
+	// TextInput sends the input as a text frame
+	TextInput = "text"
+	// BinaryInput sends the input as a binary frame
+	BinaryInput = "binary"
+	// PingInput sends the input as a ping frame and reads the pong
+	PingInput = "ping"
+	// CloseInput sends the input as a close frame
+	CloseInput = "close"
+)
+
+var inputOpCodes = map[string]ws.OpCode{
+	TextInput:   ws.OpText,
+	BinaryInput: ws.OpBinary,
+	PingInput:   ws.OpPing,
+	CloseInput:  ws.OpClose,
+}
+
+const (
+	// socket.io packets are prefixed with an engine.io packet type and,
+	// for message packets, a socket.io packet type
+	socketIOOpen    = "0"
+	socketIOPing    = "2"
+	socketIOPong    = "3"
+	socketIOConnect = "40"
+	socketIOError   = "44"
+	socketIOEvent   = "42"
+)
+
+// messageConn reads and writes messages on a client websocket
+// connection handling control frames and the framing protocol.
+type messageConn struct {
+	conn    net.Conn
+	reader  io.Reader
+	framing string
+	timeout time.Duration
+	pending []wsutil.Message
+}
+
+// write writes a message to the server
+func (c *messageConn) write(opCode ws.OpCode, data []byte) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return wsutil.WriteClientMessage(c.conn, opCode, data)
+}
+
+// read returns the next data, pong or close message from the server.
+//
+// Pings and socket.io heartbeats are answered transparently.
+func (c *messageConn) read() (wsutil.Message, error) {
+	for {
+		if len(c.pending) == 0 {
+			_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+			messages, err := wsutil.ReadServerMessage(c.reader, nil)
+			if err != nil {
+				return wsutil.Message{}, err
+			}
+			c.pending = messages
+		}
+		message := c.pending[0]
+		c.pending = c.pending[1:]
+
+		switch message.OpCode {
+		case ws.OpPing:
+			if err := wsutil.HandleServerControlMessage(c.conn, message); err != nil {
+				return wsutil.Message{}, err
+			}
+			continue
+		case ws.OpClose:
+			// reply to the close frame, the connection is done afterwards
+			_ = wsutil.HandleServerControlMessage(c.conn, message)
+			return message, nil
+		case ws.OpText:
+			if c.framing == SocketIOFraming && string(message.Payload) == socketIOPing {
+				if err := c.write(ws.OpText, []byte(socketIOPong)); err != nil {
+					return wsutil.Message{}, err
+				}
+				continue
+			}
+		}
+		return message, nil
+	}
+}
+
+// handshake performs the framing protocol handshake after the
+// websocket connection is established.
+func (c *messageConn) handshake(host string) error {
+	switch c.framing {
+	case SocketIOFraming:
+		if _, err := c.readPrefixed(socketIOOpen); err != nil {
+			return errors.Wrap(err, "could not read socket.io open packet")
+		}
+		if err := c.write(ws.OpText, []byte(socketIOConnect)); err != nil {
+			return err
+		}
+		message, err := c.readPrefixed(socketIOConnect, socketIOError)
+		if err != nil {
+			return errors.Wrap(err, "could not read socket.io connect packet")
+		}
+		if strings.HasPrefix(message, socketIOError) {
+			return errors.Errorf("socket.io connect error: %s", strings.TrimPrefix(message, socketIOError))
+		}
+	case STOMPFraming:
+		frame := stompFrame("CONNECT", map[string]string{"accept-version": "1.0,1.1,1.2", "host": host, "heart-beat": "0,0"}, nil)
+		if err := c.write(ws.OpText, frame); err != nil {
+			return err
+		}
+		message, err := c.readPrefixed("CONNECTED", "ERROR")
+		if err != nil {
+			return errors.Wrap(err, "could not read stomp connected frame")
+		}
+		if strings.HasPrefix(message, "ERROR") {
+			return errors.Errorf("stomp connect error: %s", unframe(STOMPFraming, []byte(message)))
+		}
+	}
+	return nil
+}
+
+// readPrefixed reads messages until one starting with any of the prefixes is received
+func (c *messageConn) readPrefixed(prefixes ...string) (string, error) {
+	for {
+		message, err := c.read()
+		if err != nil {
+			return "", err
+		}
+		if message.OpCode == ws.OpClose {
+			return "", io.EOF
+		}
+		for _, prefix := range prefixes {
+			if bytes.HasPrefix(message.Payload, []byte(prefix)) {
+				return string(message.Payload), nil
+			}
+		}
+	}
+}
+
+// frame frames the data of an input according to the framing protocol
+func frame(framing string, input *Input, headers map[string]string, data []byte) []byte {
+	switch framing {
+	case SocketIOFraming:
+		if input.Event == "" {
+			return append([]byte(socketIOEvent), data...)
+		}
+		if !json.Valid(data) {
+			data, _ = json.Marshal(string(data))
+		}
+		event, _ := json.Marshal(input.Event)
+		framed := append([]byte(socketIOEvent+"["), event...)
+		framed = append(framed, ',')
+		framed = append(framed, data...)
+		return append(framed, ']')
+	case STOMPFraming:
+		command := input.Command
+		if command == "" {
+			command = "SEND"
+		}
+		return stompFrame(command, headers, data)
+	}
+	return data
+}
+
+// stompFrame builds a STOMP frame with sorted headers
+func stompFrame(command string, headers map[string]string, body []byte) []byte {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	builder := &bytes.Buffer{}
+	builder.WriteString(command)
+	builder.WriteByte('\n')
+	for _, key := range keys {
+		builder.WriteString(key)
+		builder.WriteByte(':')
+		builder.WriteString(headers[key])
+		builder.WriteByte('\n')
+	}
+	builder.WriteByte('\n')
+	builder.Write(body)
+	builder.WriteByte(0)
+	return builder.Bytes()
+}
+
+// unframe strips the framing protocol from a message received from the server
+func unframe(framing string, message []byte) string {
+	switch framing {
+	case SocketIOFraming:
+		// strip engine.io and socket.io packet types (ex: 42["event",{}])
+		index := 0
+		for index < len(message) && index < 2 && message[index] >= '0' && message[index] <= '9' {
+			index++
+		}
+		return string(message[index:])
+	case STOMPFraming:
+		return string(bytes.TrimRight(message, "\x00\r\n"))
+	}
+	return string(message)
+}
@@ -0,0 +1,35 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrame(t *testing.T) {
+	t.Run("socketio", func(t *testing.T) {
+		framed := frame(SocketIOFraming, &Input{}, nil, []byte(`["chat","hello"]`))
+		require.Equal(t, `42["chat","hello"]`, string(framed), "could not frame socket.io message")
+
+		framed = frame(SocketIOFraming, &Input{Event: "chat"}, nil, []byte(`{"msg":"hello"}`))
+		require.Equal(t, `42["chat",{"msg":"hello"}]`, string(framed), "could not frame socket.io json event")
+
+		framed = frame(SocketIOFraming, &Input{Event: "chat"}, nil, []byte(`hello`))
+		require.Equal(t, `42["chat","hello"]`, string(framed), "could not frame socket.io string event")
+
+		require.Equal(t, `["chat","hello"]`, unframe(SocketIOFraming, framed), "could not unframe socket.io message")
+	})
+	t.Run("stomp", func(t *testing.T) {
+		framed := frame(STOMPFraming, &Input{}, map[string]string{"destination": "/queue/a", "content-type": "text/plain"}, []byte("hello"))
+		require.Equal(t, "SEND\ncontent-type:text/plain\ndestination:/queue/a\n\nhello\x00", string(framed), "could not frame stomp message")
+
+		framed = frame(STOMPFraming, &Input{Command: "SUBSCRIBE"}, map[string]string{"id": "0"}, nil)
+		require.Equal(t, "SUBSCRIBE\nid:0\n\n\x00", string(framed), "could not frame stomp command")
+
+		require.Equal(t, "MESSAGE\n\nhello", unframe(STOMPFraming, []byte("MESSAGE\n\nhello\x00\n")), "could not unframe stomp message")
+	})
+	t.Run("none", func(t *testing.T) {
+		require.Equal(t, "hello", string(frame("", &Input{Event: "chat"}, nil, []byte("hello"))), "could not send unframed message")
+		require.Equal(t, "42hello", unframe("", []byte("42hello")), "could not read unframed message")
+	})
+}
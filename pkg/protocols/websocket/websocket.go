@@ -3,21 +3,21 @@ package websocket
 import (
 	"crypto/tls"
 	"fmt"
-	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
 	"time"
 
+	"github.com/Knetic/govaluate"
 	"github.com/gobwas/ws"
-	"github.com/gobwas/ws/wsutil"
 	"github.com/pkg/errors"
 
 	"github.com/projectdiscovery/fastdialer/fastdialer"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/extractors"
 	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
@@ -27,6 +27,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/generators"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/eventcreator"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/responsehighlighter"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/interactsh"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/vardump"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/network/networkclientpool"
 	protocolutils "github.com/projectdiscovery/nuclei/v3/pkg/protocols/utils"
@@ -52,6 +53,24 @@ type Request struct {
 	// description: |
 	//   Headers contains headers for the request.
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=headers contains the request headers,description=Headers contains headers for the request"`
+	// description: |
+	//   Subprotocols is the list of subprotocols to negotiate with the server.
+	//
+	//   The subprotocol selected by the server is available in the subprotocol variable.
+	// examples:
+	//   - value: >
+	//       []string{"graphql-transport-ws", "graphql-ws"}
+	Subprotocols []string `yaml:"subprotocols,omitempty" json:"subprotocols,omitempty" jsonschema:"title=subprotocols to negotiate,description=Subprotocols is the list of subprotocols to negotiate with the server"`
+	// description: |
+	//   Framing is the message framing protocol to use for inputs and responses.
+	//
+	//   socketio performs the socket.io (engine.io v4) handshake, answers heartbeats
+	//   and sends inputs as socket.io events. stomp connects to the STOMP broker and
+	//   sends inputs as STOMP frames. Responses are matched without the framing.
+	// values:
+	//   - "socketio"
+	//   - "stomp"
+	Framing string `yaml:"framing,omitempty" json:"framing,omitempty" jsonschema:"title=message framing protocol,description=Framing is the message framing protocol to use for inputs and responses,enum=socketio,enum=stomp"`
 
 	// description: |
 	//   Attack is the type of payload combinations to perform.
@@ -66,6 +85,12 @@ type Request struct {
 	//   of payloads is provided, or optionally a single file can also
 	//   be provided as payload which will be read on run-time.
 	Payloads map[string]interface{} `yaml:"payloads,omitempty" json:"payloads,omitempty" jsonschema:"title=payloads for the websocket request,description=Payloads contains any payloads for the current request"`
+	// description: |
+	//   Fuzzing describes rules to fuzz the fields of input messages.
+	//
+	//   Input data is decoded with the fuzzing dataformats (json, xml) and
+	//   the matching fields are mutated, sending each mutation on a new connection.
+	Fuzzing []*fuzz.Rule `yaml:"fuzzing,omitempty" json:"fuzzing,omitempty" jsonschema:"title=fuzzing rules for websocket messages,description=Fuzzing describes rules to fuzz the fields of input messages"`
 
 	generator *generators.PayloadGenerator
 
@@ -89,11 +114,56 @@ type Input struct {
 	// examples:
 	//   - value: "\"prefix\""
 	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"title=optional name for data read,description=Optional name of the data read to provide matching on"`
+	// description: |
+	//   Type is the type of frame to send the input as.
+	//
+	//   ping sends a ping frame and reads the pong, close sends a close frame.
+	//   By default, inputs are sent as text frames.
+	// values:
+	//   - "text"
+	//   - "binary"
+	//   - "ping"
+	//   - "close"
+	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=type of frame,description=Type is the type of frame to send the input as,enum=text,enum=binary,enum=ping,enum=close"`
+	// description: |
+	//   Event is the socket.io event name to send the data with when using socketio framing.
+	// examples:
+	//   - value: "\"message\""
+	Event string `yaml:"event,omitempty" json:"event,omitempty" jsonschema:"title=socket.io event name,description=Event is the socket.io event name to send the data with"`
+	// description: |
+	//   Command is the STOMP frame command when using stomp framing.
+	//
+	//   By default, inputs are sent as SEND frames.
+	// examples:
+	//   - value: "\"SUBSCRIBE\""
+	Command string `yaml:"command,omitempty" json:"command,omitempty" jsonschema:"title=stomp frame command,description=Command is the STOMP frame command"`
+	// description: |
+	//   Headers contains the STOMP frame headers when using stomp framing.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=stomp frame headers,description=Headers contains the STOMP frame headers"`
+	// description: |
+	//   Read is the number of messages to read after sending the input.
+	//
+	//   By default, one message is read.
+	// examples:
+	//   - value: "2"
+	Read int `yaml:"read,omitempty" json:"read,omitempty" jsonschema:"title=number of messages to read,description=Read is the number of messages to read after sending the input"`
+	// description: |
+	//   ReadUntil is a DSL condition, messages are read until it evaluates to true.
+	//
+	//   The last message read is available in the message variable and all
+	//   the messages read for the input in the messages variable.
+	// examples:
+	//   - value: "\"contains(message, 'complete')\""
+	ReadUntil string `yaml:"read-until,omitempty" json:"read-until,omitempty" jsonschema:"title=condition to read messages until,description=ReadUntil is a DSL condition to read messages until it evaluates to true"`
+
+	readUntil *govaluate.EvaluableExpression
 }
 
 const (
 	parseUrlErrorMessage                   = "could not parse input url"
 	evaluateTemplateExpressionErrorMessage = "could not evaluate template expressions"
+	// maxReadUntilMessages is the maximum number of messages read for a read-until condition
+	maxReadUntilMessages = 100
 )
 
 // Compile compiles the request generators preparing any requests possible.
@@ -106,6 +176,22 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 	}
 	request.dialer = client
 
+	if request.Framing != "" && request.Framing != SocketIOFraming && request.Framing != STOMPFraming {
+		return errors.Errorf("invalid framing specified: %s", request.Framing)
+	}
+	for _, input := range request.Inputs {
+		if _, ok := inputOpCodes[input.Type]; input.Type != "" && !ok {
+			return errors.Errorf("invalid input type specified: %s", input.Type)
+		}
+		if input.ReadUntil != "" {
+			compiled, err := govaluate.NewEvaluableExpressionWithFunctions(input.ReadUntil, dsl.HelperFunctions)
+			if err != nil {
+				return &dsl.CompilationError{DslSignature: input.ReadUntil, WrappedError: err}
+			}
+			input.readUntil = compiled
+		}
+	}
+
 	if len(request.Payloads) > 0 {
 		request.generator, err = generators.New(request.Payloads, request.AttackType.Value, request.options.TemplatePath, options.Catalog, options.Options.AttackType, types.DefaultOptions())
 		if err != nil {
@@ -113,6 +199,21 @@ func (request *Request) Compile(options *protocols.ExecutorOptions) error {
 		}
 	}
 
+	for _, rule := range request.Fuzzing {
+		if rule.Part == "" && len(rule.Parts) == 0 {
+			rule.Part = fuzz.MessagePart
+		}
+		if fuzzingMode := options.Options.FuzzingMode; fuzzingMode != "" {
+			rule.Mode = fuzzingMode
+		}
+		if fuzzingType := options.Options.FuzzingType; fuzzingType != "" {
+			rule.Type = fuzzingType
+		}
+		if err := rule.Compile(request.generator, request.options); err != nil {
+			return errors.Wrap(err, "could not compile fuzzing rule")
+		}
+	}
+
 	if len(request.Matchers) > 0 || len(request.Extractors) > 0 {
 		compiled := &request.Operators
 		compiled.ExcludeMatchers = options.ExcludeMatchers
@@ -145,7 +246,8 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 		return err
 	}
 
-	if request.generator != nil {
+	// payloads of fuzzing templates are used as fuzz values by the rules
+	if request.generator != nil && len(request.Fuzzing) == 0 {
 		iterator := request.generator.NewIterator()
 
 		for {
@@ -166,6 +268,17 @@ func (request *Request) ExecuteWithResults(input *contextargs.Context, dynamicVa
 	return nil
 }
 
+// generatedRequest is a single websocket connection to make with its messages
+type generatedRequest struct {
+	address       string
+	header        http.Header
+	payloadValues map[string]interface{}
+	// messages contains the evaluated data of each input
+	messages [][]byte
+	// fuzzMessage is the fuzzed message if the request is generated by a fuzzing rule
+	fuzzMessage *fuzz.GeneratedMessage
+}
+
 // ExecuteWithResults executes the protocol requests and returns results instead of writing them.
 func (request *Request) executeRequestWithPayloads(target *contextargs.Context, hostname string, dynamicValues, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
 	header := http.Header{}
@@ -191,20 +304,6 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 		}
 		header.Set(key, string(finalData))
 	}
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: true,
-		ServerName:         hostname,
-		MinVersion:         tls.VersionTLS10,
-	}
-	if requestOptions.Options.SNI != "" {
-		tlsConfig.ServerName = requestOptions.Options.SNI
-	}
-	websocketDialer := ws.Dialer{
-		Header:    ws.HandshakeHeaderHTTP(header),
-		Timeout:   time.Duration(requestOptions.Options.Timeout) * time.Second,
-		NetDial:   request.dialer.Dial,
-		TLSConfig: tlsConfig,
-	}
 
 	if vardump.EnableVarDump {
 		gologger.Debug().Msgf("WebSocket Protocol request variables: %s\n", vardump.DumpVariables(payloadValues))
@@ -227,24 +326,124 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	parsedAddress.Path = path.Join(parsedAddress.Path, parsed.Path)
 	addressToDial = parsedAddress.String()
 
-	conn, readBuffer, _, err := websocketDialer.Dial(target.Context(), addressToDial)
+	messages := make([][]byte, 0, len(request.Inputs))
+	for _, req := range request.Inputs {
+		finalData, dataErr := expressions.EvaluateByte([]byte(req.Data), payloadValues)
+		if dataErr != nil {
+			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
+			requestOptions.Progress.IncrementFailedRequestsBy(1)
+			return errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+		}
+		messages = append(messages, finalData)
+	}
+
+	generated := &generatedRequest{address: addressToDial, header: header, payloadValues: payloadValues, messages: messages}
+	if len(request.Fuzzing) > 0 {
+		return request.executeFuzzingRules(target, hostname, generated, previous, callback)
+	}
+	_, err = request.executeGeneratedRequest(target, hostname, generated, previous, callback)
+	return err
+}
+
+// executeFuzzingRules executes the fuzzing rules on each text input of the request
+func (request *Request) executeFuzzingRules(target *contextargs.Context, hostname string, base *generatedRequest, previous output.InternalEvent, callback protocols.OutputEventCallback) error {
+	stopAtFirstMatch := request.options.Options.StopAtFirstMatch || request.options.StopAtFirstMatch
+	for index, input := range request.Inputs {
+		if input.Type != "" && input.Type != TextInput && input.Type != BinaryInput {
+			continue
+		}
+		for _, rule := range request.Fuzzing {
+			err := rule.ExecuteMessage(&fuzz.ExecuteMessageInput{
+				Message: string(base.messages[index]),
+				Values:  base.payloadValues,
+				Callback: func(gm fuzz.GeneratedMessage) bool {
+					select {
+					case <-target.Context().Done():
+						return false
+					default:
+					}
+					messages := make([][]byte, len(base.messages))
+					copy(messages, base.messages)
+					messages[index] = []byte(gm.Message)
+
+					generated := &generatedRequest{
+						address:       base.address,
+						header:        base.header,
+						payloadValues: generators.MergeMaps(base.payloadValues, gm.DynamicValues),
+						messages:      messages,
+						fuzzMessage:   &gm,
+					}
+					matched, err := request.executeGeneratedRequest(target, hostname, generated, previous, callback)
+					if err != nil {
+						gologger.Verbose().Msgf("[%s] Error occurred in fuzzing request: %s\n", request.options.TemplateID, err)
+					}
+					return !(stopAtFirstMatch && matched)
+				},
+			})
+			if err == nil {
+				continue
+			}
+			if fuzz.IsErrRuleNotApplicable(err) {
+				gologger.Verbose().Msgf("[%s] fuzz: rule not applicable : %s\n", request.options.TemplateID, err)
+				continue
+			}
+			if err == types.ErrNoMoreRequests {
+				return nil
+			}
+			return errors.Wrap(err, "could not execute rule")
+		}
+	}
+	return nil
+}
+
+// executeGeneratedRequest makes a websocket connection sending the generated
+// messages and returns whether the operators matched the response.
+func (request *Request) executeGeneratedRequest(target *contextargs.Context, hostname string, generated *generatedRequest, previous output.InternalEvent, callback protocols.OutputEventCallback) (bool, error) {
+	input := target.MetaInput.Input
+	requestOptions := request.options
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         hostname,
+		MinVersion:         tls.VersionTLS10,
+	}
+	if requestOptions.Options.SNI != "" {
+		tlsConfig.ServerName = requestOptions.Options.SNI
+	}
+	websocketDialer := ws.Dialer{
+		Header:    ws.HandshakeHeaderHTTP(generated.header),
+		Protocols: request.Subprotocols,
+		Timeout:   time.Duration(requestOptions.Options.Timeout) * time.Second,
+		NetDial:   request.dialer.Dial,
+		TLSConfig: tlsConfig,
+	}
+
+	conn, readBuffer, handshake, err := websocketDialer.Dial(target.Context(), generated.address)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
-		return errors.Wrap(err, "could not connect to server")
+		return false, errors.Wrap(err, "could not connect to server")
 	}
 	defer conn.Close()
 
-	responseBuilder := &strings.Builder{}
+	messageConn := &messageConn{conn: conn, reader: conn, framing: request.Framing, timeout: websocketDialer.Timeout}
 	if readBuffer != nil {
-		_, _ = io.Copy(responseBuilder, readBuffer) // Copy initial response
+		// frames sent right after the handshake are buffered by the dialer
+		messageConn.reader = readBuffer
+		defer ws.PutReader(readBuffer)
+	}
+	if err := messageConn.handshake(hostname); err != nil {
+		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
+		requestOptions.Progress.IncrementFailedRequestsBy(1)
+		return false, errors.Wrap(err, "could not perform framing handshake")
 	}
 
-	events, requestOutput, err := request.readWriteInputWebsocket(conn, payloadValues, input, responseBuilder)
+	responseBuilder := &strings.Builder{}
+	events, requestOutput, err := request.readWriteInputWebsocket(messageConn, generated, input, responseBuilder)
 	if err != nil {
 		requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 		requestOptions.Progress.IncrementFailedRequestsBy(1)
-		return errors.Wrap(err, "could not read write response")
+		return false, errors.Wrap(err, "could not read write response")
 	}
 	requestOptions.Progress.IncrementRequests()
 
@@ -262,8 +461,9 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 	data["success"] = "true"
 	data["request"] = requestOutput
 	data["response"] = responseBuilder.String()
+	data["subprotocol"] = handshake.Protocol
 	data["host"] = input
-	data["matched"] = addressToDial
+	data["matched"] = generated.address
 	data["ip"] = request.dialer.GetDialedIP(hostname)
 
 	// add response fields to template context and merge templatectx variables to output event
@@ -277,57 +477,96 @@ func (request *Request) executeRequestWithPayloads(target *contextargs.Context,
 		data[k] = v
 	}
 
+	var interactshURLs []string
+	if generated.fuzzMessage != nil {
+		interactshURLs = generated.fuzzMessage.InteractURLs
+	}
+	if requestOptions.Interactsh != nil {
+		requestOptions.Interactsh.MakePlaceholders(interactshURLs, data)
+	}
+
 	event := eventcreator.CreateEventWithAdditionalOptions(request, data, requestOptions.Options.Debug || requestOptions.Options.DebugResponse, func(internalWrappedEvent *output.InternalWrappedEvent) {
-		internalWrappedEvent.OperatorsResult.PayloadValues = payloadValues
+		internalWrappedEvent.OperatorsResult.PayloadValues = generated.payloadValues
 	})
+	if generated.fuzzMessage != nil {
+		for _, result := range event.Results {
+			result.IsFuzzingResult = true
+			result.FuzzingParameter = generated.fuzzMessage.Parameter
+			result.FuzzingPosition = fuzz.MessagePart
+		}
+	}
 	if requestOptions.Options.Debug || requestOptions.Options.DebugResponse {
 		responseOutput := responseBuilder.String()
 		gologger.Debug().Msgf("[%s] Dumped Websocket response for %s", requestOptions.TemplateID, input)
 		gologger.Print().Msgf("%s", responsehighlighter.Highlight(event.OperatorsResult, responseOutput, requestOptions.Options.NoColor, false))
 	}
 
+	if len(interactshURLs) > 0 && requestOptions.Interactsh != nil && interactsh.HasMatchers(request.CompiledOperators) {
+		event.UsesInteractsh = true
+		requestOptions.Interactsh.RequestEvent(interactshURLs, &interactsh.RequestData{
+			MakeResultFunc: request.MakeResultEvent,
+			Event:          event,
+			Operators:      request.CompiledOperators,
+			MatchFunc:      request.Match,
+			ExtractFunc:    request.Extract,
+			ResultCallback: interactsh.ResultCallbackFromContext(target.Context()),
+			Parameter:      generated.fuzzMessage.Parameter,
+		})
+		return false, nil
+	}
+
 	callback(event)
-	return nil
+	return event.OperatorsResult != nil && event.OperatorsResult.Matched, nil
 }
 
-func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map[string]interface{}, input string, respBuilder *strings.Builder) (events map[string]interface{}, req string, err error) {
+func (request *Request) readWriteInputWebsocket(conn *messageConn, generated *generatedRequest, input string, respBuilder *strings.Builder) (events map[string]interface{}, req string, err error) {
 	reqBuilder := &strings.Builder{}
 	inputEvents := make(map[string]interface{})
 
 	requestOptions := request.options
-	for _, req := range request.Inputs {
-		reqBuilder.Grow(len(req.Data))
-
-		finalData, dataErr := expressions.EvaluateByte([]byte(req.Data), payloadValues)
-		if dataErr != nil {
-			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
-			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+	for index, req := range request.Inputs {
+		inputType := req.Type
+		if inputType == "" {
+			inputType = TextInput
 		}
-		reqBuilder.WriteString(string(finalData))
+		finalData := generated.messages[index]
+		if inputType == TextInput || inputType == BinaryInput {
+			headers := make(map[string]string, len(req.Headers))
+			for key, value := range req.Headers {
+				finalValue, dataErr := expressions.Evaluate(value, generated.payloadValues)
+				if dataErr != nil {
+					requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), dataErr)
+					requestOptions.Progress.IncrementFailedRequestsBy(1)
+					return nil, "", errors.Wrap(dataErr, evaluateTemplateExpressionErrorMessage)
+				}
+				headers[key] = finalValue
+			}
+			finalData = frame(request.Framing, req, headers, finalData)
+		}
+		reqBuilder.Grow(len(finalData))
+		reqBuilder.Write(finalData)
 
-		err = wsutil.WriteClientMessage(conn, ws.OpText, finalData)
+		err = conn.write(inputOpCodes[inputType], finalData)
 		if err != nil {
 			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 			requestOptions.Progress.IncrementFailedRequestsBy(1)
 			return nil, "", errors.Wrap(err, "could not write request to server")
 		}
+		if inputType == CloseInput {
+			break
+		}
 
-		msg, opCode, err := wsutil.ReadServerData(conn)
+		messages, err := request.readInputMessages(conn, req, inputType, generated.payloadValues)
 		if err != nil {
 			requestOptions.Output.Request(requestOptions.TemplateID, input, request.Type().String(), err)
 			requestOptions.Progress.IncrementFailedRequestsBy(1)
-			return nil, "", errors.Wrap(err, "could not write request to server")
+			return nil, "", errors.Wrap(err, "could not read response from server")
 		}
-		// Only perform matching and writes in case we receive
-		// text or binary opcode from the websocket server.
-		if opCode != ws.OpText && opCode != ws.OpBinary {
-			continue
+		for _, msg := range messages {
+			respBuilder.WriteString(msg)
 		}
-
-		respBuilder.Write(msg)
 		if req.Name != "" {
-			bufferStr := string(msg)
+			bufferStr := strings.Join(messages, "\n")
 			inputEvents[req.Name] = bufferStr
 
 			// Run any internal extractors for the request here and add found values to map.
@@ -342,6 +581,50 @@ func (request *Request) readWriteInputWebsocket(conn net.Conn, payloadValues map
 	return inputEvents, reqBuilder.String(), nil
 }
 
+// readInputMessages reads the messages sent by the server in response to an input
+func (request *Request) readInputMessages(conn *messageConn, input *Input, inputType string, payloadValues map[string]interface{}) ([]string, error) {
+	count := input.Read
+	if count <= 0 {
+		count = 1
+	}
+	if input.readUntil != nil {
+		count = maxReadUntilMessages
+	}
+
+	var messages []string
+	for len(messages) < count {
+		msg, err := conn.read()
+		if err != nil {
+			// read-until stops at the first read failure (ex: timeout)
+			if input.readUntil != nil && len(messages) > 0 {
+				break
+			}
+			return messages, err
+		}
+		if msg.OpCode == ws.OpClose {
+			break
+		}
+		// only pongs are read in response to a ping
+		if (inputType == PingInput) != (msg.OpCode == ws.OpPong) {
+			continue
+		}
+		message := unframe(request.Framing, msg.Payload)
+		messages = append(messages, message)
+
+		if input.readUntil != nil {
+			values := generators.MergeMaps(payloadValues, map[string]interface{}{"message": message, "messages": strings.Join(messages, "\n")})
+			result, err := input.readUntil.Evaluate(values)
+			if err != nil {
+				return messages, errors.Wrap(err, "could not evaluate read-until condition")
+			}
+			if matched, ok := result.(bool); ok && matched {
+				break
+			}
+		}
+	}
+	return messages, nil
+}
+
 // getAddress returns the address of the host to make request to
 func getAddress(toTest string) (string, error) {
 	parsed, err := url.Parse(toTest)
@@ -385,12 +668,13 @@ func (request *Request) GetCompiledOperators() []*operators.Operators {
 // description. Multiple definitions are separated by commas.
 // Definitions not having a name (generated on runtime) are prefixed & suffixed by <>.
 var RequestPartDefinitions = map[string]string{
-	"type":     "Type is the type of request made",
-	"success":  "Success specifies whether websocket connection was successful",
-	"request":  "Websocket request made to the server",
-	"response": "Websocket response received from the server",
-	"host":     "Host is the input to the template",
-	"matched":  "Matched is the input which was matched upon",
+	"type":        "Type is the type of request made",
+	"success":     "Success specifies whether websocket connection was successful",
+	"request":     "Websocket request made to the server",
+	"response":    "Websocket response received from the server",
+	"subprotocol": "Subprotocol is the subprotocol selected by the server",
+	"host":        "Host is the input to the template",
+	"matched":     "Matched is the input which was matched upon",
 }
 
 func (request *Request) MakeResultEventItem(wrapped *output.InternalWrappedEvent) *output.ResultEvent {
@@ -0,0 +1,332 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/fuzz"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/matchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+// serverConn is the server side of a test websocket connection
+type serverConn struct {
+	t       *testing.T
+	conn    net.Conn
+	pending []wsutil.Message
+}
+
+// read returns the next data message of the client, answering pings
+// and recording pongs in pongs if not nil
+func (c *serverConn) read(pongs *[]string) (string, bool) {
+	for {
+		if len(c.pending) == 0 {
+			messages, err := wsutil.ReadClientMessage(c.conn, nil)
+			if err != nil {
+				return "", false
+			}
+			c.pending = messages
+		}
+		message := c.pending[0]
+		c.pending = c.pending[1:]
+
+		switch message.OpCode {
+		case ws.OpPing:
+			c.write(ws.OpPong, string(message.Payload))
+			continue
+		case ws.OpPong:
+			if pongs != nil {
+				*pongs = append(*pongs, string(message.Payload))
+			}
+			continue
+		case ws.OpClose:
+			return "", false
+		}
+		return string(message.Payload), true
+	}
+}
+
+func (c *serverConn) write(opCode ws.OpCode, data string) {
+	require.Nil(c.t, wsutil.WriteServerMessage(c.conn, opCode, []byte(data)), "could not write server message")
+}
+
+// newServer returns a websocket server calling handler on each connection
+// and selecting the subprotocol with protocol if not nil
+func newServer(t *testing.T, protocol func(string) bool, handler func(conn *serverConn)) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := ws.HTTPUpgrader{Protocol: protocol}
+		conn, _, _, err := upgrader.Upgrade(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler(&serverConn{t: t, conn: conn})
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// execute compiles the request and executes it against the target returning its events
+func execute(t *testing.T, request *Request, target string) ([]*output.InternalWrappedEvent, error) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+	templateID := "testing-websocket"
+	request.Address = "{{BaseURL}}"
+	executerOpts := testutils.NewMockExecuterOptions(options, &testutils.TemplateInfo{
+		ID:   templateID,
+		Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}, Name: "test"},
+	})
+	require.Nil(t, request.Compile(executerOpts), "could not compile websocket request")
+
+	var events []*output.InternalWrappedEvent
+	err := request.ExecuteWithResults(contextargs.NewWithInput(context.Background(), target), make(output.InternalEvent), make(output.InternalEvent), func(event *output.InternalWrappedEvent) {
+		events = append(events, event)
+	})
+	return events, err
+}
+
+func wordMatcher(part string, words ...string) operators.Operators {
+	return operators.Operators{
+		Matchers: []*matchers.Matcher{{
+			Part:  part,
+			Type:  matchers.MatcherTypeHolder{MatcherType: matchers.WordsMatcher},
+			Words: words,
+		}},
+	}
+}
+
+func TestWebsocketReadInputMessages(t *testing.T) {
+	var pongs []string
+	done := make(chan struct{})
+	target := newServer(t, nil, func(conn *serverConn) {
+		defer close(done)
+		for {
+			message, ok := conn.read(&pongs)
+			if !ok {
+				return
+			}
+			if message == "start" {
+				// server pings are answered while reading messages
+				conn.write(ws.OpPing, "heartbeat")
+				for _, update := range []string{"progress 1", "progress 2", "complete", "extra"} {
+					conn.write(ws.OpText, update)
+				}
+			}
+		}
+	})
+
+	request := &Request{
+		Inputs: []*Input{
+			{Data: "start", Name: "updates", ReadUntil: "contains(message, 'complete')"},
+			{Data: "probe", Name: "pong", Type: PingInput},
+		},
+		Operators: wordMatcher("updates", "progress 2"),
+	}
+	events, err := execute(t, request, target)
+	require.Nil(t, err, "could not execute websocket request")
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, "progress 1\nprogress 2\ncomplete", event.InternalEvent["updates"], "could not read until condition")
+	// the message left after read-until is skipped while waiting for the pong
+	require.Equal(t, "probe", event.InternalEvent["pong"], "could not read pong of ping input")
+	require.True(t, event.OperatorsResult != nil && event.OperatorsResult.Matched, "could not match messages read")
+
+	// the connection is closed once the request is executed
+	<-done
+	require.Equal(t, []string{"heartbeat"}, pongs, "could not answer server ping")
+}
+
+func TestWebsocketFramingHandshake(t *testing.T) {
+	t.Run("socketio", func(t *testing.T) {
+		var heartbeat string
+		target := newServer(t, nil, func(conn *serverConn) {
+			conn.write(ws.OpText, `0{"sid":"test","pingInterval":25000}`)
+			if message, ok := conn.read(nil); !ok || message != "40" {
+				return
+			}
+			conn.write(ws.OpText, `40{"sid":"test"}`)
+			message, ok := conn.read(nil)
+			if !ok {
+				return
+			}
+			// heartbeats are answered before the reply is read
+			conn.write(ws.OpText, "2")
+			heartbeat, _ = conn.read(nil)
+			if message == `42["chat","hello"]` {
+				conn.write(ws.OpText, `42["reply","hi"]`)
+			}
+		})
+
+		request := &Request{
+			Framing:   SocketIOFraming,
+			Inputs:    []*Input{{Data: "hello", Event: "chat", Name: "reply"}},
+			Operators: wordMatcher("reply", `["reply","hi"]`),
+		}
+		events, err := execute(t, request, target)
+		require.Nil(t, err, "could not execute socket.io request")
+		require.Len(t, events, 1)
+		require.Equal(t, `["reply","hi"]`, events[0].InternalEvent["reply"], "could not unframe socket.io reply")
+		require.True(t, events[0].OperatorsResult.Matched)
+		require.Equal(t, "3", heartbeat, "could not answer socket.io heartbeat")
+	})
+
+	t.Run("socketio-connect-error", func(t *testing.T) {
+		target := newServer(t, nil, func(conn *serverConn) {
+			conn.write(ws.OpText, `0{"sid":"test"}`)
+			_, _ = conn.read(nil)
+			conn.write(ws.OpText, `44{"message":"unauthorized"}`)
+			_, _ = conn.read(nil)
+		})
+
+		request := &Request{Framing: SocketIOFraming, Inputs: []*Input{{Data: "hello"}}}
+		_, err := execute(t, request, target)
+		require.ErrorContains(t, err, `socket.io connect error: {"message":"unauthorized"}`)
+	})
+
+	t.Run("stomp", func(t *testing.T) {
+		var connect string
+		target := newServer(t, nil, func(conn *serverConn) {
+			connect, _ = conn.read(nil)
+			conn.write(ws.OpText, "CONNECTED\nversion:1.2\n\n\x00")
+			message, ok := conn.read(nil)
+			if !ok {
+				return
+			}
+			if strings.HasPrefix(message, "SEND\ndestination:/queue/test\n") {
+				conn.write(ws.OpText, "MESSAGE\ndestination:/queue/test\n\nreceived\x00")
+			}
+		})
+
+		request := &Request{
+			Framing:   STOMPFraming,
+			Inputs:    []*Input{{Data: "hello", Headers: map[string]string{"destination": "/queue/test"}, Name: "message"}},
+			Operators: wordMatcher("message", "received"),
+		}
+		events, err := execute(t, request, target)
+		require.Nil(t, err, "could not execute stomp request")
+		require.Len(t, events, 1)
+		require.Equal(t, "MESSAGE\ndestination:/queue/test\n\nreceived", events[0].InternalEvent["message"], "could not unframe stomp message")
+		require.True(t, strings.HasPrefix(connect, "CONNECT\naccept-version:1.0,1.1,1.2\n"), "could not send stomp connect frame")
+	})
+
+	t.Run("stomp-connect-error", func(t *testing.T) {
+		target := newServer(t, nil, func(conn *serverConn) {
+			_, _ = conn.read(nil)
+			conn.write(ws.OpText, "ERROR\nmessage:bad credentials\n\n\x00")
+			_, _ = conn.read(nil)
+		})
+
+		request := &Request{Framing: STOMPFraming, Inputs: []*Input{{Data: "hello"}}}
+		_, err := execute(t, request, target)
+		require.ErrorContains(t, err, "stomp connect error: ERROR\nmessage:bad credentials")
+	})
+}
+
+func TestWebsocketSubprotocol(t *testing.T) {
+	var offered []string
+	target := newServer(t, func(protocol string) bool {
+		offered = append(offered, protocol)
+		return protocol == "graphql-transport-ws"
+	}, func(conn *serverConn) {
+		if _, ok := conn.read(nil); ok {
+			conn.write(ws.OpText, `{"type":"connection_ack"}`)
+		}
+	})
+
+	request := &Request{
+		Subprotocols: []string{"graphql-ws", "graphql-transport-ws"},
+		Inputs:       []*Input{{Data: `{"type":"connection_init"}`}},
+		Operators:    wordMatcher("subprotocol", "graphql-transport-ws"),
+	}
+	events, err := execute(t, request, target)
+	require.Nil(t, err, "could not execute websocket request")
+	require.Len(t, events, 1)
+	require.Equal(t, []string{"graphql-ws", "graphql-transport-ws"}, offered, "could not offer subprotocols in order")
+	require.Equal(t, "graphql-transport-ws", events[0].InternalEvent["subprotocol"], "could not get negotiated subprotocol")
+	require.True(t, events[0].OperatorsResult.Matched)
+}
+
+func TestWebsocketMessageFuzzing(t *testing.T) {
+	var mu sync.Mutex
+	var received []map[string]interface{}
+	var messages int
+	target := newServer(t, nil, func(conn *serverConn) {
+		message, ok := conn.read(nil)
+		if !ok {
+			return
+		}
+		mu.Lock()
+		messages++
+		mu.Unlock()
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(message), &decoded); err != nil {
+			return
+		}
+		mu.Lock()
+		received = append(received, decoded)
+		mu.Unlock()
+
+		if decoded["user"] == "admin'" {
+			conn.write(ws.OpText, "SQL syntax error")
+			return
+		}
+		conn.write(ws.OpText, "ok")
+	})
+	receivedMessages := func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}{}, received...)
+	}
+
+	request := &Request{
+		Inputs: []*Input{{Data: `{"user":"admin","role":"viewer","id":1}`}},
+		Fuzzing: []*fuzz.Rule{{
+			Type: "postfix",
+			Mode: "single",
+			Keys: []string{"user", "role"},
+			Fuzz: fuzz.SliceOrMapSlice{Value: []string{"'"}},
+		}},
+		Operators: wordMatcher("response", "SQL syntax"),
+	}
+	events, err := execute(t, request, target)
+	require.Nil(t, err, "could not execute websocket fuzzing request")
+
+	require.Len(t, receivedMessages(), 2, "could not send one message per fuzzed field")
+	require.ElementsMatch(t, []map[string]interface{}{
+		{"user": "admin'", "role": "viewer", "id": float64(1)},
+		{"user": "admin", "role": "viewer'", "id": float64(1)},
+	}, receivedMessages(), "could not fuzz message fields")
+
+	var matched []*output.ResultEvent
+	for _, event := range events {
+		matched = append(matched, event.Results...)
+	}
+	require.Len(t, matched, 1, "could not match fuzzed message")
+	require.True(t, matched[0].IsFuzzingResult)
+	require.Equal(t, "user", matched[0].FuzzingParameter)
+	require.Equal(t, fuzz.MessagePart, matched[0].FuzzingPosition)
+
+	// messages which can not be decoded are not fuzzed
+	request.Inputs[0].Data = "not a structured message"
+	_, err = execute(t, request, target)
+	require.Nil(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 2, messages, "could fuzz undecodable message")
+}
@@ -0,0 +1,65 @@
+package redact
+
+import (
+	"regexp"
+	"sort"
+)
+
+// detector is a built-in rule finding a kind of sensitive value
+type detector struct {
+	regex *regexp.Regexp
+	// valid optionally verifies a match to reduce false positives
+	valid func(value string) bool
+}
+
+// detectors are the built-in detectors keyed by name
+var detectors = map[string]*detector{
+	"jwt": {
+		regex: regexp.MustCompile(`eyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`),
+	},
+	"aws-access-key": {
+		regex: regexp.MustCompile(`\b(?:AKIA|ASIA|AIDA|AROA)[0-9A-Z]{16}\b`),
+	},
+	"aws-secret-key": {
+		regex: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`),
+	},
+	"credit-card": {
+		regex: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid: luhn,
+	},
+	"email": {
+		regex: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	},
+}
+
+// Detectors returns the sorted names of the built-in detectors
+func Detectors() []string {
+	var names []string
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// luhn returns true if the digits of value pass the luhn checksum of card numbers
+func luhn(value string) bool {
+	var sum, digits int
+	double := false
+	for i := len(value) - 1; i >= 0; i-- {
+		char := value[i]
+		if char < '0' || char > '9' {
+			continue
+		}
+		digit := int(char - '0')
+		if double {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		digits++
+		double = !double
+	}
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,292 @@
+// Package redact implements redaction policies removing secrets and
+// personal data from the evidence of results before they are written,
+// exported or reported to issue trackers.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+const (
+	// ModeMask replaces redacted values with the name of the rule
+	ModeMask = "mask"
+	// ModeHash replaces redacted values with the name of the rule and a
+	// hash of the value so that equal values can still be correlated
+	ModeHash = "hash"
+
+	// defaultEntropyLength is the default minimum length of tokens checked by entropy rules
+	defaultEntropyLength = 20
+)
+
+// tokenRegex matches the tokens checked by entropy only rules
+var tokenRegex = regexp.MustCompile(`[A-Za-z0-9+/=_-]+`)
+
+// Policy is a redaction policy
+type Policy struct {
+	// Mode is the masking mode, mask (default) or hash
+	Mode string `yaml:"mode,omitempty" validate:"omitempty,oneof=mask hash"`
+	// HashKey is the optional secret key of hashes in hash mode,
+	// without it low entropy values like emails can be guessed from their hash
+	HashKey string `yaml:"hash-key,omitempty"`
+	// Detectors are the enabled built-in detectors, all by default or none with [none]
+	Detectors []string `yaml:"detectors,omitempty"`
+	// Keys are the keys whose values are redacted in query parameters, headers and bodies
+	Keys []string `yaml:"keys,omitempty"`
+	// Rules are the custom redaction rules
+	Rules []*Rule `yaml:"rules,omitempty" validate:"dive"`
+}
+
+// Rule redacts the matches of a regex and/or high entropy tokens.
+//
+// If the regex has a capture group only the first group is redacted.
+// With an entropy, only matches (or tokens without regex) whose shannon
+// entropy per character is at least the entropy are redacted.
+type Rule struct {
+	// Name is the name of the rule used in redacted values
+	Name string `yaml:"name" validate:"required"`
+	// Regex is the regex matching the values to redact
+	Regex string `yaml:"regex,omitempty"`
+	// Entropy is the minimum shannon entropy of redacted values
+	Entropy float64 `yaml:"entropy,omitempty"`
+	// MinLength is the minimum length of redacted values of entropy rules (default 20)
+	MinLength int `yaml:"min-length,omitempty"`
+}
+
+// rule is a compiled redaction rule
+type rule struct {
+	name      string
+	regex     *regexp.Regexp
+	entropy   float64
+	minLength int
+	valid     func(value string) bool
+}
+
+// Redactor redacts the evidence of results with a policy
+type Redactor struct {
+	rules   []*rule
+	mode    string
+	hashKey []byte
+}
+
+// New returns a redactor for the policy file at path with the additional
+// keys to redact or nil if path is empty
+func New(path string, keys []string) (*Redactor, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open redaction policy file")
+	}
+	defer file.Close()
+
+	policy := &Policy{}
+	if err := yaml.DecodeAndValidate(file, policy); err != nil {
+		return nil, errors.Wrap(err, "could not parse redaction policy file")
+	}
+	policy.Keys = append(policy.Keys, keys...)
+	return NewRedactor(policy)
+}
+
+// NewRedactor returns a redactor for the policy
+func NewRedactor(policy *Policy) (*Redactor, error) {
+	redactor := &Redactor{mode: policy.Mode, hashKey: []byte(policy.HashKey)}
+	if redactor.mode == "" {
+		redactor.mode = ModeMask
+	}
+	if redactor.mode != ModeMask && redactor.mode != ModeHash {
+		return nil, errors.Errorf("invalid redaction mode %s", policy.Mode)
+	}
+
+	enabled := policy.Detectors
+	if len(enabled) == 0 {
+		enabled = Detectors()
+	}
+	for _, name := range enabled {
+		if name == "none" {
+			continue
+		}
+		detector, ok := detectors[name]
+		if !ok {
+			return nil, errors.Errorf("unknown redaction detector %s, available detectors: %s", name, strings.Join(Detectors(), ", "))
+		}
+		redactor.rules = append(redactor.rules, &rule{name: name, regex: detector.regex, valid: detector.valid})
+	}
+
+	for _, key := range sliceutil.Dedupe(policy.Keys) {
+		if key == "" {
+			continue
+		}
+		// values are redacted up to the end of the line, quote or parameter
+		// like the legacy redaction so that multi-word values are not leaked
+		regex := regexp.MustCompile(fmt.Sprintf(`(?i)%s["']?\s*[:=]\s*["']?([^"'\r\n&]+)`, regexp.QuoteMeta(key)))
+		redactor.rules = append(redactor.rules, &rule{name: key, regex: regex})
+	}
+
+	for _, custom := range policy.Rules {
+		compiled := &rule{name: custom.Name, entropy: custom.Entropy, minLength: custom.MinLength}
+		switch {
+		case custom.Regex != "":
+			regex, err := regexp.Compile(custom.Regex)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regex of redaction rule %s", custom.Name)
+			}
+			compiled.regex = regex
+		case custom.Entropy > 0:
+			compiled.regex = tokenRegex
+			if compiled.minLength == 0 {
+				compiled.minLength = defaultEntropyLength
+			}
+		default:
+			return nil, errors.Errorf("redaction rule %s has neither regex nor entropy", custom.Name)
+		}
+		redactor.rules = append(redactor.rules, compiled)
+	}
+	return redactor, nil
+}
+
+// Redact returns the data with the values matched by the rules redacted
+func (r *Redactor) Redact(data string) string {
+	if r == nil || data == "" {
+		return data
+	}
+	for _, rule := range r.rules {
+		data = r.redactRule(rule, data)
+	}
+	return data
+}
+
+// redactRule replaces the values matched by a rule in data
+func (r *Redactor) redactRule(rule *rule, data string) string {
+	matches := rule.regex.FindAllStringSubmatchIndex(data, -1)
+	if len(matches) == 0 {
+		return data
+	}
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		// only redact the first capture group if the regex has one
+		if len(match) >= 4 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+		value := data[start:end]
+		if !rule.match(value) {
+			continue
+		}
+		builder.WriteString(data[last:start])
+		builder.WriteString(r.replacement(rule.name, value))
+		last = end
+	}
+	builder.WriteString(data[last:])
+	return builder.String()
+}
+
+// match returns true if the value matched by the regex of the rule has to be redacted
+func (r *rule) match(value string) bool {
+	if value == "" || len(value) < r.minLength {
+		return false
+	}
+	if r.valid != nil && !r.valid(value) {
+		return false
+	}
+	return r.entropy == 0 || Entropy(value) >= r.entropy
+}
+
+// replacement returns the replacement of a value redacted by a rule
+func (r *Redactor) replacement(name, value string) string {
+	if r.mode != ModeHash {
+		return fmt.Sprintf("[REDACTED:%s]", name)
+	}
+	mac := hmac.New(sha256.New, r.hashKey)
+	_, _ = mac.Write([]byte(value))
+	return fmt.Sprintf("[REDACTED:%s:%s]", name, hex.EncodeToString(mac.Sum(nil))[:12])
+}
+
+// Event returns a copy of the event with its evidence redacted,
+// the event itself is left untouched.
+func (r *Redactor) Event(event *output.ResultEvent) *output.ResultEvent {
+	if r == nil {
+		return event
+	}
+	redacted := *event
+	redacted.Request = r.Redact(event.Request)
+	redacted.Response = r.Redact(event.Response)
+	redacted.CURLCommand = r.Redact(event.CURLCommand)
+	redacted.Matched = r.Redact(event.Matched)
+	redacted.URL = r.Redact(event.URL)
+	if event.ExtractedResults != nil {
+		redacted.ExtractedResults = make([]string, len(event.ExtractedResults))
+		for i, value := range event.ExtractedResults {
+			redacted.ExtractedResults[i] = r.Redact(value)
+		}
+	}
+	if event.Metadata != nil {
+		redacted.Metadata = make(map[string]interface{}, len(event.Metadata))
+		for key, value := range event.Metadata {
+			if str, ok := value.(string); ok {
+				value = r.Redact(str)
+			}
+			redacted.Metadata[key] = value
+		}
+	}
+	if event.Interaction != nil {
+		interaction := *event.Interaction
+		interaction.RawRequest = r.Redact(interaction.RawRequest)
+		interaction.RawResponse = r.Redact(interaction.RawResponse)
+		redacted.Interaction = &interaction
+	}
+	return &redacted
+}
+
+// FailureEvent returns a copy of the failure event with its evidence
+// and results redacted, the event itself is left untouched.
+func (r *Redactor) FailureEvent(event *output.InternalWrappedEvent) *output.InternalWrappedEvent {
+	if r == nil {
+		return event
+	}
+	redacted := event.CloneShallow()
+	redacted.OperatorsResult = event.OperatorsResult
+	// failure events without results are built from the internal event
+	for _, key := range []string{"request", "response", "matched", "curl-command"} {
+		if value, ok := redacted.InternalEvent[key].(string); ok {
+			redacted.InternalEvent[key] = r.Redact(value)
+		}
+	}
+	for _, result := range event.Results {
+		redacted.Results = append(redacted.Results, r.Event(result))
+	}
+	return redacted
+}
+
+// Entropy returns the shannon entropy per character of value in bits
+func Entropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	var total int
+	for _, char := range value {
+		counts[char]++
+		total++
+	}
+	var entropy float64
+	for _, count := range counts {
+		frequency := float64(count) / float64(total)
+		entropy -= frequency * math.Log2(frequency)
+	}
+	return entropy
+}
@@ -51,6 +51,9 @@ func CreateReportDescription(event *output.ResultEvent, formatter ResultFormatte
 	attributes.Set("Protocol", strings.ToUpper(event.Type))
 	attributes.Set("Full URL", event.Matched)
 	attributes.Set("Timestamp", event.Timestamp.Format("Mon Jan 2 15:04:05 -0700 MST 2006"))
+	if event.Risk != nil {
+		attributes.Set("Risk", fmt.Sprintf("%.1f (%s)", event.Risk.Score, event.Risk.Priority.Severity))
+	}
 	attributes.ForEach(func(key string, data interface{}) {
 		builder.WriteString(fmt.Sprintf("%s: %s\n\n", formatter.MakeBold(key), types.ToString(data)))
 	})
@@ -20,11 +20,15 @@ type CreateIssueResponse struct {
 type Filter struct {
 	Severities severity.Severities     `yaml:"severity"`
 	Tags       stringslice.StringSlice `yaml:"tags"`
+	// Priorities are the risk priorities of results computed from the asset context
+	Priorities severity.Severities `yaml:"priority"`
+	// MinRisk is the minimum risk score of results computed from the asset context
+	MinRisk float64 `yaml:"min-risk"`
 }
 
 // GetMatch returns true if a filter matches result event
 func (filter *Filter) GetMatch(event *output.ResultEvent) bool {
-	return isSeverityMatch(event, filter) && isTagMatch(event, filter) // TODO revisit this
+	return isSeverityMatch(event, filter) && isTagMatch(event, filter) && isRiskMatch(event, filter) // TODO revisit this
 }
 
 func isTagMatch(event *output.ResultEvent, filter *Filter) bool {
@@ -52,3 +56,18 @@ func isSeverityMatch(event *output.ResultEvent, filter *Filter) bool {
 
 	return sliceutil.Contains(filter.Severities, resultEventSeverity)
 }
+
+// isRiskMatch returns true if the risk of the event matches the filter,
+// events without a computed risk never match risk criteria
+func isRiskMatch(event *output.ResultEvent, filter *Filter) bool {
+	if len(filter.Priorities) == 0 && filter.MinRisk == 0 {
+		return true
+	}
+	if event.Risk == nil {
+		return false
+	}
+	if len(filter.Priorities) > 0 && !sliceutil.Contains(filter.Priorities, event.Risk.Priority.Severity) {
+		return false
+	}
+	return event.Risk.Score >= filter.MinRisk
+}
@@ -0,0 +1,230 @@
+// Package risk computes risk scores of results by combining the severity,
+// CVSS and EPSS scores of templates with the context of the scanned assets.
+package risk
+
+import (
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
+)
+
+// Context is the asset context file containing the assets and the scoring policy
+type Context struct {
+	// Assets are the assets of the context, the first asset matching a result is used
+	Assets []*Asset `yaml:"assets"`
+	// Policy overrides the default scoring policy
+	Policy *Policy `yaml:"policy,omitempty"`
+}
+
+// Asset is a group of hosts sharing the same context
+type Asset struct {
+	// Hosts are the hostname patterns of the asset (e.g. *.prod.example.com)
+	Hosts []string `yaml:"hosts,omitempty"`
+	// CIDRs are the networks of the asset
+	CIDRs []string `yaml:"cidrs,omitempty"`
+	// Environment is the environment of the asset (e.g. production, staging, development)
+	Environment string `yaml:"environment,omitempty"`
+	// Exposure is the exposure of the asset (e.g. internet, internal)
+	Exposure string `yaml:"exposure,omitempty"`
+	// Criticality is the business criticality of the asset (e.g. critical, high, medium, low)
+	Criticality string `yaml:"criticality,omitempty"`
+	// Tags are additional tags of the asset
+	Tags []string `yaml:"tags,omitempty"`
+
+	networks []*net.IPNet
+}
+
+// Policy is the scoring policy of results.
+//
+// The base score of a result is the CVSS score of its template or the score of its
+// severity, which is increased by the EPSS score and multiplied by the asset context.
+type Policy struct {
+	// Severity are the base scores of template severities used without CVSS score
+	Severity map[string]float64 `yaml:"severity,omitempty"`
+	// EPSSWeight is the increase of the score of a template with an EPSS score of 1
+	EPSSWeight *float64 `yaml:"epss-weight,omitempty"`
+	// Environment are the multipliers of the asset environments
+	Environment map[string]float64 `yaml:"environment,omitempty"`
+	// Exposure are the multipliers of the asset exposures
+	Exposure map[string]float64 `yaml:"exposure,omitempty"`
+	// Criticality are the multipliers of the asset criticalities
+	Criticality map[string]float64 `yaml:"criticality,omitempty"`
+	// Priority are the minimum scores of each priority
+	Priority map[string]float64 `yaml:"priority,omitempty"`
+}
+
+// DefaultPolicy returns the default scoring policy
+func DefaultPolicy() *Policy {
+	epssWeight := 0.5
+	return &Policy{
+		Severity:    map[string]float64{"info": 0, "low": 3, "medium": 5.5, "high": 8, "critical": 9.5},
+		EPSSWeight:  &epssWeight,
+		Environment: map[string]float64{"production": 1, "staging": 0.8, "development": 0.6, "test": 0.6},
+		Exposure:    map[string]float64{"internet": 1.2, "internal": 0.8},
+		Criticality: map[string]float64{"critical": 1.25, "high": 1.1, "medium": 1, "low": 0.8},
+		Priority:    map[string]float64{"critical": 9, "high": 7, "medium": 4, "low": 0.1},
+	}
+}
+
+// merge overrides the values of the policy with the ones set in other
+func (p *Policy) merge(other *Policy) {
+	if other == nil {
+		return
+	}
+	for _, values := range []struct{ to, from map[string]float64 }{
+		{p.Severity, other.Severity},
+		{p.Environment, other.Environment},
+		{p.Exposure, other.Exposure},
+		{p.Criticality, other.Criticality},
+		{p.Priority, other.Priority},
+	} {
+		for key, value := range values.from {
+			values.to[strings.ToLower(key)] = value
+		}
+	}
+	if other.EPSSWeight != nil {
+		p.EPSSWeight = other.EPSSWeight
+	}
+}
+
+// Scorer computes the risk of results
+type Scorer struct {
+	assets []*Asset
+	policy *Policy
+}
+
+// New returns a scorer for the asset context file at path or nil if path is empty
+func New(path string) (*Scorer, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open asset context file")
+	}
+	defer file.Close()
+
+	context := &Context{}
+	if err := yaml.DecodeAndValidate(file, context); err != nil {
+		return nil, errors.Wrap(err, "could not parse asset context file")
+	}
+	return NewScorer(context)
+}
+
+// NewScorer returns a scorer for the asset context
+func NewScorer(context *Context) (*Scorer, error) {
+	policy := DefaultPolicy()
+	policy.merge(context.Policy)
+	for _, asset := range context.Assets {
+		for _, cidr := range asset.CIDRs {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid asset cidr %s", cidr)
+			}
+			asset.networks = append(asset.networks, network)
+		}
+		for _, host := range asset.Hosts {
+			if _, err := path.Match(host, ""); err != nil {
+				return nil, errors.Wrapf(err, "invalid asset host pattern %s", host)
+			}
+		}
+	}
+	return &Scorer{assets: context.Assets, policy: policy}, nil
+}
+
+// Apply sets the risk of the result if it was not computed yet
+func (s *Scorer) Apply(event *output.ResultEvent) {
+	if s == nil || event.Risk != nil {
+		return
+	}
+	event.Risk = s.Score(event)
+}
+
+// Score returns the risk of the result
+func (s *Scorer) Score(event *output.ResultEvent) *output.Risk {
+	info := event.Info
+	score := s.policy.Severity[info.SeverityHolder.Severity.String()]
+	if classification := info.Classification; classification != nil {
+		if classification.CVSSScore > 0 {
+			score = classification.CVSSScore
+		}
+		score *= 1 + *s.policy.EPSSWeight*classification.EPSSScore
+	}
+
+	risk := &output.Risk{}
+	if asset := s.match(event); asset != nil {
+		risk.Environment = asset.Environment
+		risk.Exposure = asset.Exposure
+		risk.Criticality = asset.Criticality
+		risk.AssetTags = asset.Tags
+		score *= multiplier(s.policy.Environment, asset.Environment)
+		score *= multiplier(s.policy.Exposure, asset.Exposure)
+		score *= multiplier(s.policy.Criticality, asset.Criticality)
+	}
+	risk.Score = math.Round(math.Min(score, 10)*10) / 10
+	risk.Priority = severity.Holder{Severity: s.priority(risk.Score)}
+	return risk
+}
+
+// priority returns the highest priority whose minimum score is reached
+func (s *Scorer) priority(score float64) severity.Severity {
+	for _, priority := range []severity.Severity{severity.Critical, severity.High, severity.Medium, severity.Low} {
+		if minimum, ok := s.policy.Priority[priority.String()]; ok && score >= minimum {
+			return priority
+		}
+	}
+	return severity.Info
+}
+
+// match returns the first asset matching the host or ip of the result
+func (s *Scorer) match(event *output.ResultEvent) *Asset {
+	hostname := eventHostname(event.Host)
+	ip := net.ParseIP(event.IP)
+	if ip == nil {
+		ip = net.ParseIP(hostname)
+	}
+	for _, asset := range s.assets {
+		for _, pattern := range asset.Hosts {
+			if matched, _ := path.Match(strings.ToLower(pattern), hostname); matched {
+				return asset
+			}
+		}
+		for _, network := range asset.networks {
+			if ip != nil && network.Contains(ip) {
+				return asset
+			}
+		}
+	}
+	return nil
+}
+
+// multiplier returns the multiplier of a context value or 1 if it has none
+func multiplier(multipliers map[string]float64, value string) float64 {
+	if value, ok := multipliers[strings.ToLower(value)]; ok {
+		return value
+	}
+	return 1
+}
+
+// eventHostname returns the lowercase hostname of the host of a result
+// which can be a url, a host:port or a hostname
+func eventHostname(host string) string {
+	if strings.Contains(host, "://") {
+		if parsed, err := url.Parse(host); err == nil {
+			return strings.ToLower(parsed.Hostname())
+		}
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return strings.ToLower(hostname)
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
@@ -0,0 +1,109 @@
+package risk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting/trackers/filters"
+)
+
+const testContext = `
+assets:
+  - hosts: ["*.prod.example.com"]
+    environment: production
+    exposure: internet
+    criticality: critical
+    tags: [payments]
+  - cidrs: ["10.0.0.0/8"]
+    environment: development
+    exposure: internal
+policy:
+  criticality:
+    critical: 1.5
+`
+
+func newTestScorer(t *testing.T) *Scorer {
+	path := filepath.Join(t.TempDir(), "assets.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(testContext), 0644))
+	scorer, err := New(path)
+	require.Nil(t, err, "could not create scorer")
+	return scorer
+}
+
+func TestScore(t *testing.T) {
+	scorer := newTestScorer(t)
+
+	tests := []struct {
+		name     string
+		event    *output.ResultEvent
+		score    float64
+		priority severity.Severity
+	}{
+		{
+			name:     "severity without asset",
+			event:    &output.ResultEvent{Host: "https://other.example.com", Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Medium}}},
+			score:    5.5,
+			priority: severity.Medium,
+		},
+		{
+			name:     "critical internet asset",
+			event:    &output.ResultEvent{Host: "https://api.prod.example.com:8443/login", Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Medium}}},
+			score:    9.9,
+			priority: severity.Critical,
+		},
+		{
+			name: "cvss and epss on internal asset",
+			event: &output.ResultEvent{Host: "db.internal:5432", IP: "10.1.2.3", Info: model.Info{
+				SeverityHolder: severity.Holder{Severity: severity.Critical},
+				Classification: &model.Classification{CVSSScore: 8, EPSSScore: 0.5},
+			}},
+			score:    4.8,
+			priority: severity.Medium,
+		},
+		{
+			name:     "info severity",
+			event:    &output.ResultEvent{Host: "10.0.0.1", Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Info}}},
+			score:    0,
+			priority: severity.Info,
+		},
+	}
+	for _, test := range tests {
+		scorer.Apply(test.event)
+		require.NotNil(t, test.event.Risk, "could not compute risk for %s", test.name)
+		require.Equal(t, test.score, test.event.Risk.Score, "could not get correct score for %s", test.name)
+		require.Equal(t, test.priority, test.event.Risk.Priority.Severity, "could not get correct priority for %s", test.name)
+	}
+	require.Equal(t, []string{"payments"}, tests[1].event.Risk.AssetTags)
+	require.Equal(t, "development", tests[2].event.Risk.Environment)
+}
+
+func TestInvalidContext(t *testing.T) {
+	_, err := NewScorer(&Context{Assets: []*Asset{{CIDRs: []string{"10.0.0.0/33"}}}})
+	require.NotNil(t, err, "could create scorer with invalid cidr")
+
+	_, err = NewScorer(&Context{Assets: []*Asset{{Hosts: []string{"[a-"}}}})
+	require.NotNil(t, err, "could create scorer with invalid host pattern")
+
+	scorer, err := New("")
+	require.Nil(t, err)
+	require.Nil(t, scorer, "could create scorer without asset context")
+}
+
+func TestRiskFilter(t *testing.T) {
+	scorer := newTestScorer(t)
+	event := &output.ResultEvent{Host: "https://api.prod.example.com", Info: model.Info{SeverityHolder: severity.Holder{Severity: severity.Low}}}
+
+	filter := &filters.Filter{MinRisk: 5}
+	require.False(t, filter.GetMatch(event), "event without risk matched risk filter")
+
+	scorer.Apply(event)
+	require.True(t, filter.GetMatch(event), "could not match risk filter")
+	require.False(t, (&filters.Filter{Priorities: severity.Severities{severity.Critical}}).GetMatch(event), "matched wrong priority")
+	require.True(t, (&filters.Filter{Priorities: severity.Severities{severity.Medium}}).GetMatch(event), "could not match priority")
+}
@@ -29,18 +29,24 @@ type ScanContext struct {
 	OnError   func(error)
 	OnResult  func(e *output.InternalWrappedEvent)
 	OnWarning func(string)
+	// OnPivot receives the pivot targets instead of the context if set
+	OnPivot func(targets []string)
 
 	// unexported state fields
 	error    error
 	warnings []string
 	events   []*output.InternalWrappedEvent
 	results  []*output.ResultEvent
+	pivots   []string
 
 	// what to log
 	withEvents bool
 
 	// might not be required but better to sync
 	m sync.Mutex
+	// pivot targets are added from result callbacks
+	// which are called while m is held
+	pivotMutex sync.Mutex
 }
 
 // NewScanContext creates a new scan context using input
@@ -53,6 +59,11 @@ func (s *ScanContext) Context() context.Context {
 	return s.ctx
 }
 
+// SetContext sets the context of the scan
+func (s *ScanContext) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
 func (s *ScanContext) GenerateErrorMessage() error {
 	return s.error
 }
@@ -88,6 +99,26 @@ func (s *ScanContext) LogEvent(e *output.InternalWrappedEvent) {
 	s.results = append(s.results, e.Results...)
 }
 
+// AddPivotTargets adds targets to be scanned by the pivot of the template
+func (s *ScanContext) AddPivotTargets(targets ...string) {
+	if s.OnPivot != nil {
+		s.OnPivot(targets)
+		return
+	}
+	s.pivotMutex.Lock()
+	defer s.pivotMutex.Unlock()
+
+	s.pivots = append(s.pivots, targets...)
+}
+
+// PivotTargets returns the targets to be scanned by the pivot of the template
+func (s *ScanContext) PivotTargets() []string {
+	s.pivotMutex.Lock()
+	defer s.pivotMutex.Unlock()
+
+	return s.pivots
+}
+
 // LogError logs error to all events and triggeres any callbacks
 func (s *ScanContext) LogError(err error) {
 	s.m.Lock()
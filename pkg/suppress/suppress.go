@@ -0,0 +1,246 @@
+// Package suppress implements suppression of accepted risks and known false
+// positives from results with a justification, an owner and an expiry date.
+package suppress
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/utils/yaml"
+)
+
+// dateLayout is the layout of expiry dates, a suppression
+// is active until the end of its expiry day in UTC
+const dateLayout = "2006-01-02"
+
+// File is the suppression file containing the suppressions
+type File struct {
+	Suppressions []*Suppression `yaml:"suppressions" validate:"dive"`
+}
+
+// Suppression suppresses the results matching all of its criteria.
+//
+// Criteria support * wildcards matching any characters.
+type Suppression struct {
+	// TemplateID is the id of the template of suppressed results
+	TemplateID string `yaml:"template-id,omitempty"`
+	// Host is the host of suppressed results as hostname or input
+	Host string `yaml:"host,omitempty"`
+	// MatchedAt is the matched-at value of suppressed results
+	MatchedAt string `yaml:"matched-at,omitempty"`
+	// MatcherName is the matcher name of suppressed results
+	MatcherName string `yaml:"matcher-name,omitempty"`
+	// ExtractedValue is one of the extracted values of suppressed results
+	ExtractedValue string `yaml:"extracted-value,omitempty"`
+	// Justification is the reason of the suppression
+	Justification string `yaml:"justification" validate:"required"`
+	// Owner is the person or team responsible for the suppression
+	Owner string `yaml:"owner" validate:"required"`
+	// Expires is the expiry date of the suppression (e.g. 2024-12-31 or RFC3339)
+	Expires string `yaml:"expires" validate:"required"`
+
+	expiry   time.Time
+	patterns map[string]*regexp.Regexp
+	count    atomic.Int64
+}
+
+// Suppressor drops results matching active suppressions
+type Suppressor struct {
+	suppressions []*Suppression
+	suppressed   atomic.Int64
+}
+
+// New returns a suppressor for the suppression file at path or nil if path is empty
+func New(path string) (*Suppressor, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open suppression file")
+	}
+	defer file.Close()
+
+	suppressions := &File{}
+	if err := yaml.DecodeAndValidate(file, suppressions); err != nil {
+		return nil, errors.Wrap(err, "could not parse suppression file")
+	}
+	return NewSuppressor(suppressions.Suppressions)
+}
+
+// NewSuppressor returns a suppressor for the suppressions warning about expired ones
+func NewSuppressor(suppressions []*Suppression) (*Suppressor, error) {
+	now := time.Now()
+	for i, suppression := range suppressions {
+		if err := suppression.compile(); err != nil {
+			return nil, errors.Wrapf(err, "invalid suppression %d", i+1)
+		}
+		if suppression.Expired(now) {
+			gologger.Print().Msgf("[%v] Suppression %s owned by %s expired on %s, matching results are reported again", aurora.BrightYellow("WRN"), suppression, suppression.Owner, suppression.Expires)
+		}
+	}
+	return &Suppressor{suppressions: suppressions}, nil
+}
+
+// compile parses the expiry date and the criteria of the suppression
+func (s *Suppression) compile() error {
+	expiry, err := time.Parse(dateLayout, s.Expires)
+	if err == nil {
+		expiry = expiry.AddDate(0, 0, 1)
+	} else if expiry, err = time.Parse(time.RFC3339, s.Expires); err != nil {
+		return errors.Errorf("invalid expiry date %s", s.Expires)
+	}
+	s.expiry = expiry
+
+	s.patterns = make(map[string]*regexp.Regexp)
+	for field, value := range s.criteria() {
+		if value != "" {
+			s.patterns[field] = wildcard(value)
+		}
+	}
+	if len(s.patterns) == 0 {
+		return errors.New("no criteria specified")
+	}
+	return nil
+}
+
+// criteria returns the criteria of the suppression keyed by field name
+func (s *Suppression) criteria() map[string]string {
+	return map[string]string{
+		"template-id":     s.TemplateID,
+		"host":            s.Host,
+		"matched-at":      s.MatchedAt,
+		"matcher-name":    s.MatcherName,
+		"extracted-value": s.ExtractedValue,
+	}
+}
+
+// String returns the criteria of the suppression
+func (s *Suppression) String() string {
+	var parts []string
+	for _, field := range []string{"template-id", "host", "matched-at", "matcher-name", "extracted-value"} {
+		if value := s.criteria()[field]; value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%s", field, value))
+		}
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// Expired returns true if the suppression is expired at the given time
+func (s *Suppression) Expired(now time.Time) bool {
+	return !now.Before(s.expiry)
+}
+
+// Count returns the number of results suppressed by the suppression
+func (s *Suppression) Count() int64 {
+	return s.count.Load()
+}
+
+// Match returns true if the event matches all the criteria of the suppression
+func (s *Suppression) Match(event *output.ResultEvent) bool {
+	for field, pattern := range s.patterns {
+		var values []string
+		switch field {
+		case "template-id":
+			values = []string{event.TemplateID}
+		case "host":
+			values = []string{event.Host, hostname(event.Host)}
+		case "matched-at":
+			values = []string{event.Matched}
+		case "matcher-name":
+			values = []string{event.MatcherName}
+		case "extracted-value":
+			values = event.ExtractedResults
+		}
+		if !matchAny(pattern, values) {
+			return false
+		}
+	}
+	return true
+}
+
+// Suppression returns the first active suppression matching the event or nil
+func (s *Suppressor) Suppression(event *output.ResultEvent) *Suppression {
+	if s == nil {
+		return nil
+	}
+	now := time.Now()
+	for _, suppression := range s.suppressions {
+		if !suppression.Expired(now) && suppression.Match(event) {
+			return suppression
+		}
+	}
+	return nil
+}
+
+// Suppress returns true if the event is suppressed and counts it
+func (s *Suppressor) Suppress(event *output.ResultEvent) bool {
+	suppression := s.Suppression(event)
+	if suppression == nil {
+		return false
+	}
+	suppression.count.Add(1)
+	s.suppressed.Add(1)
+	return true
+}
+
+// Suppressed returns the number of suppressed results
+func (s *Suppressor) Suppressed() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.suppressed.Load()
+}
+
+// Summary logs the number of results suppressed by each suppression
+func (s *Suppressor) Summary() {
+	if s.Suppressed() == 0 {
+		return
+	}
+	gologger.Info().Msgf("Suppressed %d results matching suppressions", s.Suppressed())
+	for _, suppression := range s.suppressions {
+		if count := suppression.Count(); count > 0 {
+			gologger.Verbose().Msgf("Suppression %s owned by %s suppressed %d results: %s", suppression, suppression.Owner, count, suppression.Justification)
+		}
+	}
+}
+
+// wildcard returns a case-insensitive regex matching the whole value where * matches any characters
+func wildcard(value string) *regexp.Regexp {
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + pattern + "$")
+}
+
+func matchAny(pattern *regexp.Regexp, values []string) bool {
+	for _, value := range values {
+		if value != "" && pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostname returns the hostname of the host of a result
+// which can be a url, a host:port or a hostname
+func hostname(host string) string {
+	if strings.Contains(host, "://") {
+		if parsed, err := url.Parse(host); err == nil {
+			return parsed.Hostname()
+		}
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+	return host
+}
@@ -0,0 +1,67 @@
+package suppress
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+func TestSuppress(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(dateLayout)
+	suppressions := `
+suppressions:
+  - template-id: tech-detect
+    host: "*.example.com"
+    justification: accepted technology disclosure
+    owner: appsec
+    expires: ` + tomorrow + `
+  - matched-at: "https://legacy.example.org/*"
+    extracted-value: "1.2.*"
+    justification: legacy host with backported fixes
+    owner: infra
+    expires: ` + tomorrow + `
+  - template-id: git-config
+    justification: expired false positive
+    owner: appsec
+    expires: 2020-01-01
+`
+	path := filepath.Join(t.TempDir(), "suppressions.yaml")
+	require.Nil(t, os.WriteFile(path, []byte(suppressions), 0644))
+	suppressor, err := New(path)
+	require.Nil(t, err, "could not create suppressor")
+
+	tests := []struct {
+		event      *output.ResultEvent
+		suppressed bool
+	}{
+		{&output.ResultEvent{TemplateID: "tech-detect", Host: "https://www.example.com:8443"}, true},
+		{&output.ResultEvent{TemplateID: "TECH-DETECT", Host: "api.example.com:443"}, true},
+		{&output.ResultEvent{TemplateID: "tech-detect", Host: "https://example.org"}, false},
+		{&output.ResultEvent{TemplateID: "version", Matched: "https://legacy.example.org/about", ExtractedResults: []string{"1.2.3"}}, true},
+		{&output.ResultEvent{TemplateID: "version", Matched: "https://legacy.example.org/about", ExtractedResults: []string{"2.0.0"}}, false},
+		{&output.ResultEvent{TemplateID: "git-config", Host: "https://www.example.com"}, false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.suppressed, suppressor.Suppression(test.event) != nil, "could not get correct suppression for %+v", test.event)
+		require.Equal(t, test.suppressed, suppressor.Suppress(test.event))
+	}
+	require.Equal(t, int64(3), suppressor.Suppressed(), "could not count suppressed results")
+}
+
+func TestInvalidSuppression(t *testing.T) {
+	_, err := NewSuppressor([]*Suppression{{Justification: "no criteria", Owner: "appsec", Expires: "2030-01-01"}})
+	require.NotNil(t, err, "could create suppression without criteria")
+
+	_, err = NewSuppressor([]*Suppression{{TemplateID: "test", Justification: "bad date", Owner: "appsec", Expires: "01/01/2030"}})
+	require.NotNil(t, err, "could create suppression with invalid expiry")
+
+	path := filepath.Join(t.TempDir(), "suppressions.yaml")
+	require.Nil(t, os.WriteFile(path, []byte("suppressions:\n  - template-id: test\n    expires: 2030-01-01\n"), 0644))
+	_, err = New(path)
+	require.NotNil(t, err, "could create suppression without justification and owner")
+}
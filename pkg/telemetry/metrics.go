@@ -0,0 +1,162 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/types/nucleierr"
+	"github.com/projectdiscovery/utils/errkit"
+)
+
+const namespace = "nuclei"
+
+var (
+	metricsEnabled atomic.Bool
+	registry       = prometheus.NewRegistry()
+
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "Number of requests sent by templates",
+	}, []string{"template", "protocol", "host"})
+	requestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "request_errors_total",
+		Help:      "Number of failed requests by error kind",
+	}, []string{"template", "protocol", "host", "kind"})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the protocol calls made by templates",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"template", "protocol"})
+	matchesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "matches_total",
+		Help:      "Number of results matched by templates",
+	}, []string{"template", "protocol", "host"})
+	hostErrorSkipsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "host_error_skips_total",
+		Help:      "Number of executions skipped as the host was unresponsive",
+	}, []string{"protocol", "host"})
+	rateLimitWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ratelimit_wait_seconds",
+		Help:      "Time spent waiting on the rate limiters",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"limiter"})
+	jsRuntimesActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "js_runtimes_active",
+		Help:      "Number of javascript runtimes executing a script",
+	}, []string{"pool"})
+	jsPoolWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "js_pool_wait_seconds",
+		Help:      "Time spent waiting for a javascript runtime",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"pool"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestsTotal,
+		requestErrorsTotal,
+		requestDuration,
+		matchesTotal,
+		hostErrorSkipsTotal,
+		rateLimitWait,
+		jsRuntimesActive,
+		jsPoolWait,
+	)
+}
+
+// Handler returns the http handler serving the metrics in the prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a request of the template to target failing with err if not nil
+func ObserveRequest(templateID, protocol, target string, err error) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	host := hostLabel(target)
+	requestsTotal.WithLabelValues(templateID, protocol, host).Inc()
+	if err != nil {
+		kind := errkit.GetErrorKind(err, nucleierr.ErrTemplateLogic).String()
+		requestErrorsTotal.WithLabelValues(templateID, protocol, host, kind).Inc()
+	}
+}
+
+// ObserveMatch records a result of the template matched on target
+func ObserveMatch(templateID, protocol, target string) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	matchesTotal.WithLabelValues(templateID, protocol, hostLabel(target)).Inc()
+}
+
+// ObserveHostErrorSkip records an execution skipped as target was unresponsive
+func ObserveHostErrorSkip(protocol, target string) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	hostErrorSkipsTotal.WithLabelValues(protocol, hostLabel(target)).Inc()
+}
+
+// ObserveRateLimitWait records the time spent waiting on the limiter
+func ObserveRateLimitWait(limiter string, wait time.Duration) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	rateLimitWait.WithLabelValues(limiter).Observe(wait.Seconds())
+}
+
+// AcquireJSRuntime records a javascript runtime of the pool acquired after
+// waiting for wait. It must be followed by a call to ReleaseJSRuntime.
+func AcquireJSRuntime(pool string, wait time.Duration) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	jsPoolWait.WithLabelValues(pool).Observe(wait.Seconds())
+	jsRuntimesActive.WithLabelValues(pool).Inc()
+}
+
+// ReleaseJSRuntime records a javascript runtime of the pool released
+func ReleaseJSRuntime(pool string) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	jsRuntimesActive.WithLabelValues(pool).Dec()
+}
+
+// observeDuration records the latency of a protocol call of the template
+func observeDuration(templateID, protocol string, duration time.Duration) {
+	if !metricsEnabled.Load() {
+		return
+	}
+	requestDuration.WithLabelValues(templateID, protocol).Observe(duration.Seconds())
+}
+
+// hostLabel returns the host:port of a target url or the target itself
+func hostLabel(target string) string {
+	if !strings.Contains(target, "://") {
+		return target
+	}
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return target
+	}
+	return parsed.Host
+}
@@ -0,0 +1,92 @@
+// Package telemetry provides prometheus metrics and opentelemetry traces
+// of the scan engine.
+//
+// Metrics are labelled with the template, protocol and host they relate to
+// and served in the prometheus format. Traces span the execution of a template
+// on a target, its requests and the protocol calls made by them.
+//
+// All functions are no-op unless the telemetry was initialized with Init.
+package telemetry
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+var (
+	mu             sync.Mutex
+	metricsServer  *http.Server
+	tracerProvider *sdktrace.TracerProvider
+)
+
+// Init initializes the telemetry configured in options, serving the prometheus
+// metrics on the prometheus address and exporting traces to the otel endpoint.
+//
+// Calling Init again once the telemetry is initialized has no effect.
+func Init(options *types.Options) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if options.PrometheusAddr != "" && metricsServer == nil {
+		listener, err := net.Listen("tcp", options.PrometheusAddr)
+		if err != nil {
+			return errors.Wrap(err, "could not listen for prometheus metrics")
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", Handler())
+		metricsServer = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := metricsServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+				gologger.Warning().Msgf("Could not serve prometheus metrics: %s\n", err)
+			}
+		}()
+		metricsEnabled.Store(true)
+		gologger.Info().Msgf("Serving prometheus metrics on http://%s/metrics", listener.Addr())
+	}
+
+	if options.OTelEndpoint != "" && tracerProvider == nil {
+		provider, err := newTracerProvider(options.OTelEndpoint)
+		if err != nil {
+			return errors.Wrap(err, "could not create opentelemetry exporter")
+		}
+		tracerProvider = provider
+		tracingEnabled.Store(true)
+	}
+	return nil
+}
+
+// Close stops serving the metrics and flushes the pending traces
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if metricsServer != nil {
+		_ = metricsServer.Shutdown(ctx)
+		metricsServer = nil
+		metricsEnabled.Store(false)
+	}
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			gologger.Warning().Msgf("Could not flush opentelemetry traces: %s\n", err)
+		}
+		tracerProvider = nil
+		tracingEnabled.Store(false)
+	}
+}
+
+// Enabled returns true if metrics or traces are enabled
+func Enabled() bool {
+	return metricsEnabled.Load() || tracingEnabled.Load()
+}
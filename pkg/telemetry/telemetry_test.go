@@ -0,0 +1,43 @@
+package telemetry
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostLabel(t *testing.T) {
+	require.Equal(t, "example.com:8443", hostLabel("https://example.com:8443/path?x=1"))
+	require.Equal(t, "example.com", hostLabel("http://example.com"))
+	require.Equal(t, "example.com:53", hostLabel("example.com:53"))
+	require.Equal(t, "example.com", hostLabel("example.com"))
+}
+
+func TestMetrics(t *testing.T) {
+	metricsEnabled.Store(true)
+	defer metricsEnabled.Store(false)
+
+	ObserveRequest("test-template", "http", "https://example.com/login", nil)
+	ObserveRequest("test-template", "http", "https://example.com/admin", errors.New("connection refused"))
+	ObserveMatch("test-template", "http", "https://example.com/login")
+	ObserveHostErrorSkip("http", "example.com:443")
+	ObserveRateLimitWait("global", 10*time.Millisecond)
+	AcquireJSRuntime("pooled", time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	data, err := io.ReadAll(recorder.Body)
+	require.Nil(t, err)
+	body := string(data)
+
+	require.Contains(t, body, `nuclei_requests_total{host="example.com",protocol="http",template="test-template"} 2`)
+	require.Contains(t, body, `nuclei_request_errors_total{host="example.com",kind=`)
+	require.Contains(t, body, `nuclei_matches_total{host="example.com",protocol="http",template="test-template"} 1`)
+	require.Contains(t, body, `nuclei_host_error_skips_total{host="example.com:443",protocol="http"} 1`)
+	require.Contains(t, body, `nuclei_ratelimit_wait_seconds_count{limiter="global"} 1`)
+	require.Contains(t, body, `nuclei_js_runtimes_active{pool="pooled"} 1`)
+}
@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+)
+
+const tracerName = "github.com/projectdiscovery/nuclei/v3"
+
+var tracingEnabled atomic.Bool
+
+// TracingEnabled returns true if traces are exported
+func TracingEnabled() bool {
+	return tracingEnabled.Load()
+}
+
+// newTracerProvider returns a tracer provider exporting spans to the otlp/http
+// collector at endpoint and registers it as the global tracer provider.
+//
+// Endpoints without a scheme (e.g. localhost:4318) are sent to over plain http.
+func newTracerProvider(endpoint string) (*sdktrace.TracerProvider, error) {
+	var options []otlptracehttp.Option
+	if strings.Contains(endpoint, "://") {
+		options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+	} else {
+		options = append(options, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("nuclei"),
+			semconv.ServiceVersion(config.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider, nil
+}
+
+// StartSpan starts a span named name as a child of the span in ctx.
+// The returned context carries the new span.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !tracingEnabled.Load() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan ends the span recording err as its status if not nil
+func EndSpan(span trace.Span, err error) {
+	if !tracingEnabled.Load() {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartCall starts the span of a protocol call made by the template to target
+// and returns the function ending it, recording the latency of the call.
+func StartCall(ctx context.Context, templateID, protocol, target string) func(err error) {
+	if !Enabled() {
+		return func(error) {}
+	}
+	started := time.Now()
+	_, span := StartSpan(ctx, protocol+" call", TemplateAttributes(templateID, protocol, target)...)
+	return func(err error) {
+		observeDuration(templateID, protocol, time.Since(started))
+		EndSpan(span, err)
+	}
+}
+
+// TemplateAttributes returns the span attributes of the template, protocol and target
+func TemplateAttributes(templateID, protocol, target string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("nuclei.template", templateID),
+		attribute.String("nuclei.protocol", protocol),
+		attribute.String("nuclei.host", hostLabel(target)),
+	}
+}
@@ -0,0 +1,33 @@
+package telemetry
+
+import (
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// Writer is an output writer recording the requests and results written
+// to it as metrics before passing them to the underlying writer.
+type Writer struct {
+	output.Writer
+}
+
+var _ output.Writer = &Writer{}
+
+// NewWriter returns writer wrapped to record metrics if metrics are enabled
+func NewWriter(writer output.Writer) output.Writer {
+	if !metricsEnabled.Load() {
+		return writer
+	}
+	return &Writer{Writer: writer}
+}
+
+// Write records the match and writes the event to the underlying writer
+func (w *Writer) Write(event *output.ResultEvent) error {
+	ObserveMatch(event.TemplateID, event.Type, event.Host)
+	return w.Writer.Write(event)
+}
+
+// Request records the request and logs it with the underlying writer
+func (w *Writer) Request(templateID, url, requestType string, err error) {
+	ObserveRequest(templateID, requestType, url, err)
+	w.Writer.Request(templateID, url, requestType, err)
+}
@@ -102,6 +102,12 @@ func Parse(filePath string, preprocessor Preprocessor, options protocols.Executo
 		template.CompiledWorkflow = compiled
 		template.CompiledWorkflow.Options = &options
 	}
+	// Compile the templates pivoted to by the flow
+	if template.Pivot != nil && options.WorkflowLoader != nil {
+		if err := compilePivot(template.Pivot, preprocessor, &options, options.WorkflowLoader); err != nil {
+			return nil, errors.Wrap(err, "could not compile pivot")
+		}
+	}
 	template.Path = filePath
 	if !options.DoNotCache {
 		parser.compiledTemplatesCache.Store(filePath, template, nil, err)
@@ -382,6 +388,9 @@ func parseTemplate(data []byte, options protocols.ExecutorOptions) (*Template, e
 	if numberOfWorkflows > 0 && numberOfWorkflows != template.Requests() {
 		return nil, errors.New("workflows cannot have other protocols")
 	}
+	if template.Pivot != nil && template.Flow == "" {
+		return nil, errors.New("pivot can only be used with flow")
+	}
 
 	// use default unknown severity
 	if len(template.Workflows) == 0 {
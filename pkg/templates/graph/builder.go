@@ -0,0 +1,416 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/authprovider/authx"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/nuclei/v3/pkg/workflows"
+	fileutil "github.com/projectdiscovery/utils/file"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// template is the subset of a template needed to resolve references
+type template struct {
+	ID   string `yaml:"id"`
+	Info struct {
+		Tags stringslice.StringSlice `yaml:"tags"`
+	} `yaml:"info"`
+	Flow      string                        `yaml:"flow"`
+	Workflows []*workflows.WorkflowTemplate `yaml:"workflows"`
+	Pivot     *workflows.Pivot              `yaml:"pivot"`
+
+	RequestsHTTP       []*request `yaml:"requests"`
+	RequestsWithHTTP   []*request `yaml:"http"`
+	RequestsDNS        []*request `yaml:"dns"`
+	RequestsFile       []*request `yaml:"file"`
+	RequestsNetwork    []*request `yaml:"network"`
+	RequestsWithTCP    []*request `yaml:"tcp"`
+	RequestsHeadless   []*request `yaml:"headless"`
+	RequestsSSL        []*request `yaml:"ssl"`
+	RequestsWebsocket  []*request `yaml:"websocket"`
+	RequestsWHOIS      []*request `yaml:"whois"`
+	RequestsCode       []*request `yaml:"code"`
+	RequestsJavascript []*request `yaml:"javascript"`
+
+	path string
+	err  error
+}
+
+// request is the subset of a protocol request needed to resolve references
+type request struct {
+	ID         string                 `yaml:"id"`
+	Payloads   map[string]interface{} `yaml:"payloads"`
+	Matchers   []*operator            `yaml:"matchers"`
+	Extractors []*operator            `yaml:"extractors"`
+}
+
+type operator struct {
+	Name string `yaml:"name"`
+}
+
+// protocols returns the requests of the template keyed by the name of
+// the function calling them from a flow.
+func (t *template) protocols() map[string][]*request {
+	return map[string][]*request{
+		"http":       append(append([]*request{}, t.RequestsHTTP...), t.RequestsWithHTTP...),
+		"dns":        t.RequestsDNS,
+		"file":       t.RequestsFile,
+		"tcp":        append(append([]*request{}, t.RequestsNetwork...), t.RequestsWithTCP...),
+		"headless":   t.RequestsHeadless,
+		"ssl":        t.RequestsSSL,
+		"websocket":  t.RequestsWebsocket,
+		"whois":      t.RequestsWHOIS,
+		"code":       t.RequestsCode,
+		"javascript": t.RequestsJavascript,
+	}
+}
+
+// requests returns all protocol requests of the template
+func (t *template) requests() []*request {
+	var requests []*request
+	for _, protocol := range protocolNames {
+		requests = append(requests, t.protocols()[protocol]...)
+	}
+	return requests
+}
+
+// operatorNames returns the names of the matchers and extractors of the template
+func (t *template) operatorNames() map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, request := range t.requests() {
+		for _, operator := range append(append([]*operator{}, request.Matchers...), request.Extractors...) {
+			if operator.Name != "" {
+				names[operator.Name] = struct{}{}
+			}
+		}
+	}
+	return names
+}
+
+// extractorNames returns the names of the extractors of the template
+func (t *template) extractorNames() map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, request := range t.requests() {
+		for _, extractor := range request.Extractors {
+			if extractor.Name != "" {
+				names[extractor.Name] = struct{}{}
+			}
+		}
+	}
+	return names
+}
+
+// protocolNames are the flow function names in a stable order
+var protocolNames = []string{"http", "dns", "file", "tcp", "headless", "ssl", "websocket", "whois", "code", "javascript"}
+
+var (
+	flowCallRegex    = regexp.MustCompile(`(?:^|[^\w.$])(` + strings.Join(protocolNames, "|") + `)\s*\(([^()]*)\)`)
+	placeholderRegex = regexp.MustCompile(`{{\s*([A-Za-z0-9_-]+)\s*}}`)
+)
+
+// Builder builds the dependency graph of a template catalog
+type Builder struct {
+	options   *types.Options
+	catalog   catalog.Catalog
+	graph     *Graph
+	templates map[string]*template
+	paths     []string
+}
+
+// Build returns the dependency graph of the templates at templatePaths
+// and the dynamic secrets of secretFiles. Templates referenced from
+// outside of templatePaths are added to the graph as well.
+func Build(options *types.Options, catalog catalog.Catalog, templatePaths, secretFiles []string) (*Graph, error) {
+	builder := &Builder{
+		options:   options,
+		catalog:   catalog,
+		graph:     New(),
+		templates: make(map[string]*template),
+		paths:     sliceutil.Dedupe(templatePaths),
+	}
+	sort.Strings(builder.paths)
+
+	for _, path := range builder.paths {
+		builder.load(path)
+	}
+	for _, path := range builder.paths {
+		tpl := builder.templates[path]
+		if tpl.err != nil {
+			continue
+		}
+		builder.addPayloads(tpl)
+		builder.addFlow(tpl)
+		builder.addPivot(tpl, tpl.Pivot)
+		for _, workflow := range tpl.Workflows {
+			builder.addWorkflow(tpl, workflow)
+		}
+	}
+	for _, file := range secretFiles {
+		if err := builder.addSecrets(file); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(builder.graph.Nodes, func(i, j int) bool {
+		return builder.graph.Nodes[i].ID < builder.graph.Nodes[j].ID
+	})
+	return builder.graph, nil
+}
+
+// load parses the template at path and adds it to the graph
+func (b *Builder) load(path string) *template {
+	if tpl, ok := b.templates[path]; ok {
+		return tpl
+	}
+	tpl := &template{path: path}
+	b.templates[path] = tpl
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = yaml.Unmarshal(data, tpl)
+	}
+	if err != nil {
+		tpl.err = errors.Wrap(err, "could not parse template")
+		return tpl
+	}
+	kind := NodeTemplate
+	if len(tpl.Workflows) > 0 {
+		kind = NodeWorkflow
+	}
+	b.graph.AddNode(&Node{ID: path, Kind: kind, TemplateID: tpl.ID})
+	return tpl
+}
+
+// resolve returns the parsed templates at reference
+func (b *Builder) resolve(reference string) ([]*template, error) {
+	paths, err := b.catalog.GetTemplatePath(reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no templates found for path")
+	}
+	templates := make([]*template, 0, len(paths))
+	for _, path := range paths {
+		tpl := b.load(path)
+		if tpl.err != nil {
+			return nil, errors.Wrapf(tpl.err, "%s", path)
+		}
+		templates = append(templates, tpl)
+	}
+	return templates, nil
+}
+
+// byTags returns the templates of the catalog having any of the tags
+func (b *Builder) byTags(tags []string) []*template {
+	var templates []*template
+	for _, path := range b.paths {
+		tpl := b.templates[path]
+		if tpl.err != nil || len(tpl.Workflows) > 0 {
+			continue
+		}
+		for _, tag := range tpl.Info.Tags.ToSlice() {
+			if sliceutil.Contains(tags, tag) {
+				templates = append(templates, tpl)
+				break
+			}
+		}
+	}
+	return templates
+}
+
+func (b *Builder) addWorkflow(from *template, workflow *workflows.WorkflowTemplate) {
+	targets := b.addTemplateReference(from, EdgeWorkflow, workflow.Template, workflow.Tags)
+
+	for _, matcher := range workflow.Matchers {
+		for _, name := range matcher.Name.ToSlice() {
+			var found bool
+			for _, target := range targets {
+				if _, ok := target.operatorNames()[name]; ok {
+					found = true
+					b.graph.AddEdge(&Edge{From: from.path, To: target.path, Kind: EdgeMatcher, Reference: name})
+				}
+			}
+			// unresolved templates are already reported as dangling
+			if !found && len(targets) > 0 {
+				b.graph.AddEdge(&Edge{From: from.path, To: targets[0].path, Kind: EdgeMatcher, Reference: name, Dangling: true, Reason: fmt.Sprintf("no matcher or extractor named '%s'", name)})
+			}
+		}
+		for _, subtemplate := range matcher.Subtemplates {
+			b.addWorkflow(from, subtemplate)
+		}
+	}
+	for _, subtemplate := range workflow.Subtemplates {
+		b.addWorkflow(from, subtemplate)
+	}
+	b.addPivot(from, workflow.Pivot)
+}
+
+// addPivot adds the edges to the templates run on targets spawned by a pivot
+func (b *Builder) addPivot(from *template, pivot *workflows.Pivot) {
+	if pivot == nil {
+		return
+	}
+	_ = b.addTemplateReference(from, EdgePivot, pivot.Template, pivot.Tags)
+}
+
+// addTemplateReference adds the edges to templates referenced by path or tags returning the resolved templates
+func (b *Builder) addTemplateReference(from *template, kind EdgeKind, path string, tags stringslice.StringSlice) []*template {
+	var targets []*template
+	reference := path
+	if tags := tags.ToSlice(); len(tags) > 0 {
+		reference = "tags:" + strings.Join(tags, ",")
+		if targets = b.byTags(tags); len(targets) == 0 {
+			b.graph.AddEdge(&Edge{From: from.path, To: reference, Kind: kind, Reference: reference, Dangling: true, Reason: "no templates found for tags"})
+		}
+	} else if path != "" {
+		var err error
+		if targets, err = b.resolve(path); err != nil {
+			b.graph.AddEdge(&Edge{From: from.path, To: path, Kind: kind, Reference: path, Dangling: true, Reason: err.Error()})
+		}
+	}
+	for _, target := range targets {
+		b.graph.AddEdge(&Edge{From: from.path, To: target.path, Kind: kind, Reference: reference})
+	}
+	return targets
+}
+
+func (b *Builder) addFlow(tpl *template) {
+	if tpl.Flow == "" {
+		return
+	}
+	protocols := tpl.protocols()
+	for _, match := range flowCallRegex.FindAllStringSubmatch(tpl.Flow, -1) {
+		protocol := match[1]
+		requests := protocols[protocol]
+		if len(requests) == 0 {
+			b.graph.AddEdge(&Edge{From: tpl.path, To: tpl.path, Kind: EdgeFlow, Reference: protocol + "()", Dangling: true, Reason: fmt.Sprintf("template has no %s requests", protocol)})
+			continue
+		}
+		for _, argument := range strings.Split(match[2], ",") {
+			id, ok := literal(argument)
+			if !ok {
+				continue
+			}
+			reference := protocol + "(" + id + ")"
+			if !hasRequest(requests, id) {
+				b.graph.AddEdge(&Edge{From: tpl.path, To: tpl.path, Kind: EdgeFlow, Reference: reference, Dangling: true, Reason: fmt.Sprintf("template has no %s request with id or index '%s'", protocol, id)})
+				continue
+			}
+			b.graph.AddEdge(&Edge{From: tpl.path, To: tpl.path, Kind: EdgeFlow, Reference: reference})
+		}
+	}
+}
+
+// literal returns the value of a string or number literal argument.
+// Other arguments are only known at runtime and are not resolved.
+func literal(argument string) (string, bool) {
+	argument = strings.TrimSpace(argument)
+	if len(argument) >= 2 && strings.ContainsAny(argument[:1], "\"'`") && argument[len(argument)-1] == argument[0] {
+		return argument[1 : len(argument)-1], true
+	}
+	if _, err := strconv.Atoi(argument); err == nil {
+		return argument, true
+	}
+	return "", false
+}
+
+// hasRequest returns true if id is the id or 1-based index of a request
+func hasRequest(requests []*request, id string) bool {
+	if index, err := strconv.Atoi(id); err == nil && index >= 1 && index <= len(requests) {
+		return true
+	}
+	for _, request := range requests {
+		if request.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Builder) addPayloads(tpl *template) {
+	for _, request := range tpl.requests() {
+		names := make([]string, 0, len(request.Payloads))
+		for name := range request.Payloads {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			// only single line strings are loaded from files
+			value, ok := request.Payloads[name].(string)
+			if spec, isSpec := request.Payloads[name].(map[interface{}]interface{}); isSpec {
+				// wordlist of a programmable payload
+				value, ok = spec["file"].(string)
+			}
+			if !ok || strings.Contains(value, "\n") {
+				continue
+			}
+			path := value
+			if !b.options.AllowLocalFileAccess {
+				absPath, err := b.options.GetValidAbsPath(value, tpl.path)
+				if err != nil {
+					b.graph.AddEdge(&Edge{From: tpl.path, To: value, Kind: EdgePayload, Reference: name, Dangling: true, Reason: err.Error()})
+					continue
+				}
+				path = absPath
+			}
+			if !fileutil.FileExists(path) {
+				b.graph.AddEdge(&Edge{From: tpl.path, To: path, Kind: EdgePayload, Reference: name, Dangling: true, Reason: "payload file does not exist"})
+				continue
+			}
+			b.graph.AddNode(&Node{ID: path, Kind: NodeFile})
+			b.graph.AddEdge(&Edge{From: tpl.path, To: path, Kind: EdgePayload, Reference: name})
+		}
+	}
+}
+
+func (b *Builder) addSecrets(file string) error {
+	auth, err := authx.GetAuthDataFromFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "could not read secret file %s", file)
+	}
+	b.graph.AddNode(&Node{ID: file, Kind: NodeSecret, TemplateID: auth.ID})
+
+	for _, dynamic := range auth.Dynamic {
+		targets, err := b.resolve(dynamic.TemplatePath)
+		if err == nil && len(targets) > 1 {
+			err = errors.New("multiple templates found for path")
+		}
+		if err != nil {
+			b.graph.AddEdge(&Edge{From: file, To: dynamic.TemplatePath, Kind: EdgeSecret, Reference: dynamic.TemplatePath, Dangling: true, Reason: err.Error()})
+			continue
+		}
+		target := targets[0]
+		b.graph.AddEdge(&Edge{From: file, To: target.path, Kind: EdgeSecret, Reference: dynamic.TemplatePath})
+
+		// static secret values are filled with the extracted values of the template
+		data, err := json.Marshal(dynamic.Secret)
+		if err != nil {
+			continue
+		}
+		extractors := target.extractorNames()
+		reported := make(map[string]struct{})
+		for _, match := range placeholderRegex.FindAllStringSubmatch(string(data), -1) {
+			if _, ok := reported[match[1]]; ok {
+				continue
+			}
+			reported[match[1]] = struct{}{}
+			if _, ok := extractors[match[1]]; !ok {
+				b.graph.AddEdge(&Edge{From: file, To: target.path, Kind: EdgeSecret, Reference: match[1], Dangling: true, Reason: fmt.Sprintf("template has no extractor named '%s'", match[1])})
+			}
+		}
+	}
+	return nil
+}
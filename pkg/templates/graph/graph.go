@@ -0,0 +1,214 @@
+// Package graph builds the dependency graph between the templates of a
+// catalog, the workflows and flows referencing them, the dynamic secrets
+// running them and the payload files they import.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// NodeKind is the kind of a node of the graph
+type NodeKind string
+
+const (
+	NodeTemplate NodeKind = "template"
+	NodeWorkflow NodeKind = "workflow"
+	NodeSecret   NodeKind = "secret"
+	NodeFile     NodeKind = "file"
+)
+
+// EdgeKind is the kind of reference an edge represents
+type EdgeKind string
+
+const (
+	// EdgeWorkflow is a workflow running a template by path or tags
+	EdgeWorkflow EdgeKind = "workflow"
+	// EdgeMatcher is a workflow matcher referencing a matcher or extractor name of a template
+	EdgeMatcher EdgeKind = "matcher"
+	// EdgeFlow is a flow referencing a request of its template
+	EdgeFlow EdgeKind = "flow"
+	// EdgeSecret is a dynamic secret running a template
+	EdgeSecret EdgeKind = "secret"
+	// EdgePayload is a template importing a payload file
+	EdgePayload EdgeKind = "payload"
+	// EdgePivot is a pivot running a template by path or tags on spawned targets
+	EdgePivot EdgeKind = "pivot"
+)
+
+// Supported export formats of the graph
+const (
+	FormatJSON = "json"
+	FormatDOT  = "dot"
+)
+
+// Node is a file of the graph
+type Node struct {
+	// ID is the path of the file
+	ID         string   `json:"id"`
+	Kind       NodeKind `json:"kind"`
+	TemplateID string   `json:"template-id,omitempty"`
+}
+
+// Edge is a reference from one node to another. Dangling edges point
+// to a reference that could not be resolved.
+type Edge struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Kind      EdgeKind `json:"kind"`
+	Reference string   `json:"reference,omitempty"`
+	Dangling  bool     `json:"dangling,omitempty"`
+	Reason    string   `json:"reason,omitempty"`
+}
+
+// String returns a human readable description of the edge
+func (e *Edge) String() string {
+	value := fmt.Sprintf("%s -> %s (%s", e.From, e.To, e.Kind)
+	if e.Reference != "" {
+		value += " " + e.Reference
+	}
+	value += ")"
+	if e.Reason != "" {
+		value += ": " + e.Reason
+	}
+	return value
+}
+
+// Graph is the dependency graph of a template catalog
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+
+	nodes map[string]*Node
+}
+
+// New returns an empty graph
+func New() *Graph {
+	return &Graph{Nodes: []*Node{}, Edges: []*Edge{}, nodes: make(map[string]*Node)}
+}
+
+// Node returns the node with id or nil if it does not exist
+func (g *Graph) Node(id string) *Node {
+	return g.nodes[id]
+}
+
+// AddNode adds a node to the graph returning the existing one if
+// a node with the same id was already added.
+func (g *Graph) AddNode(node *Node) *Node {
+	if existing, ok := g.nodes[node.ID]; ok {
+		return existing
+	}
+	g.nodes[node.ID] = node
+	g.Nodes = append(g.Nodes, node)
+	return node
+}
+
+// AddEdge adds an edge to the graph
+func (g *Graph) AddEdge(edge *Edge) {
+	g.Edges = append(g.Edges, edge)
+}
+
+// Dangling returns the edges whose reference could not be resolved
+func (g *Graph) Dangling() []*Edge {
+	var dangling []*Edge
+	for _, edge := range g.Edges {
+		if edge.Dangling {
+			dangling = append(dangling, edge)
+		}
+	}
+	return dangling
+}
+
+// Dependents returns the nodes directly or transitively depending on the
+// node with id, i.e. the files impacted by a change of it.
+func (g *Graph) Dependents(id string) []*Node {
+	reverse := make(map[string][]string)
+	for _, edge := range g.Edges {
+		if edge.Dangling || edge.From == edge.To {
+			continue
+		}
+		reverse[edge.To] = append(reverse[edge.To], edge.From)
+	}
+
+	visited := map[string]struct{}{id: {}}
+	queue := []string{id}
+	var dependents []*Node
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, from := range reverse[current] {
+			if _, ok := visited[from]; ok {
+				continue
+			}
+			visited[from] = struct{}{}
+			queue = append(queue, from)
+			if node := g.nodes[from]; node != nil {
+				dependents = append(dependents, node)
+			}
+		}
+	}
+	sort.Slice(dependents, func(i, j int) bool {
+		return dependents[i].ID < dependents[j].ID
+	})
+	return dependents
+}
+
+// Write writes the graph to writer in the given format
+func (g *Graph) Write(writer io.Writer, format string) error {
+	switch format {
+	case FormatJSON, "":
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(g)
+	case FormatDOT:
+		return g.writeDOT(writer)
+	default:
+		return errors.Errorf("unsupported graph output format: %s", format)
+	}
+}
+
+var nodeShapes = map[NodeKind]string{
+	NodeTemplate: "box",
+	NodeWorkflow: "component",
+	NodeSecret:   "hexagon",
+	NodeFile:     "note",
+}
+
+func (g *Graph) writeDOT(writer io.Writer) error {
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(writer, format, args...)
+		}
+	}
+
+	printf("digraph templates {\n")
+	for _, node := range g.Nodes {
+		label := node.TemplateID
+		if label == "" {
+			label = node.ID
+		}
+		printf("  %s [label=%s, shape=%s];\n", strconv.Quote(node.ID), strconv.Quote(label), nodeShapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		label := string(edge.Kind)
+		if edge.Reference != "" {
+			label += ":" + edge.Reference
+		}
+		attributes := "label=" + strconv.Quote(label)
+		if edge.Dangling {
+			attributes += ", color=red, style=dashed"
+			if g.nodes[edge.To] == nil {
+				printf("  %s [shape=plaintext, fontcolor=red];\n", strconv.Quote(edge.To))
+			}
+		}
+		printf("  %s -> %s [%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), attributes)
+	}
+	printf("}\n")
+	return err
+}
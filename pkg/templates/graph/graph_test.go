@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
+
+const loginTemplate = `id: login
+info:
+  name: login
+  tags: auth,login
+flow: http(1) && http("check") && dns()
+http:
+  - path:
+      - "{{BaseURL}}/login"
+    payloads:
+      users: users.txt
+    extractors:
+      - type: regex
+        name: token
+        regex:
+          - "token=([a-z]+)"
+  - id: check
+    path:
+      - "{{BaseURL}}/check"
+    matchers:
+      - type: word
+        name: logged-in
+        words:
+          - "welcome"
+`
+
+const loginWorkflow = `id: login-workflow
+info:
+  name: login workflow
+workflows:
+  - template: login.yaml
+    matchers:
+      - name: logged-in
+        subtemplates:
+          - template: missing.yaml
+      - name: renamed-matcher
+  - tags: login
+`
+
+const secretFile = `id: secrets
+info:
+  name: secrets
+dynamic:
+  - template: login.yaml
+    variables:
+      - key: username
+        value: admin
+    type: header
+    domains:
+      - example.com
+    headers:
+      - key: Authorization
+        value: "Bearer {{token}} {{session}}"
+`
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	path := filepath.Join(dir, name)
+	require.Nil(t, os.WriteFile(path, []byte(data), 0o644))
+	return path
+}
+
+func buildGraph(t *testing.T) (*Graph, string) {
+	dir := t.TempDir()
+	login := writeFile(t, dir, "login.yaml", loginTemplate)
+	workflow := writeFile(t, dir, "login-workflow.yaml", loginWorkflow)
+	secrets := writeFile(t, dir, "secrets.yaml", secretFile)
+	writeFile(t, dir, "users.txt", "admin\n")
+
+	wd, err := os.Getwd()
+	require.Nil(t, err)
+	require.Nil(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(wd) }()
+
+	graph, err := Build(&types.Options{AllowLocalFileAccess: true}, disk.NewCatalog(dir), []string{login, workflow}, []string{secrets})
+	require.Nil(t, err, "could not build graph")
+	return graph, dir
+}
+
+func TestBuildGraph(t *testing.T) {
+	graph, dir := buildGraph(t)
+	login := filepath.Join(dir, "login.yaml")
+	workflow := filepath.Join(dir, "login-workflow.yaml")
+
+	require.Equal(t, NodeWorkflow, graph.Node(workflow).Kind)
+	require.Equal(t, "login", graph.Node(login).TemplateID)
+	require.NotNil(t, graph.Node("users.txt"), "could not get payload file node")
+
+	var dangling []string
+	for _, edge := range graph.Dangling() {
+		dangling = append(dangling, string(edge.Kind)+":"+edge.Reference)
+	}
+	require.ElementsMatch(t, []string{
+		"flow:dns()",
+		"workflow:missing.yaml",
+		"matcher:renamed-matcher",
+		"secret:session",
+	}, dangling)
+
+	var edges []string
+	for _, edge := range graph.Edges {
+		if !edge.Dangling && edge.From == workflow {
+			edges = append(edges, string(edge.Kind)+":"+edge.Reference)
+		}
+	}
+	require.ElementsMatch(t, []string{"workflow:login.yaml", "matcher:logged-in", "workflow:tags:login"}, edges)
+}
+
+func TestGraphDependents(t *testing.T) {
+	graph, dir := buildGraph(t)
+
+	var dependents []string
+	for _, node := range graph.Dependents("users.txt") {
+		dependents = append(dependents, filepath.Base(node.ID))
+	}
+	require.Equal(t, []string{"login-workflow.yaml", "login.yaml", "secrets.yaml"}, dependents)
+	require.Empty(t, graph.Dependents(filepath.Join(dir, "login-workflow.yaml")))
+}
+
+func TestWriteGraph(t *testing.T) {
+	graph, _ := buildGraph(t)
+
+	var buffer bytes.Buffer
+	require.Nil(t, graph.Write(&buffer, FormatJSON))
+	var decoded Graph
+	require.Nil(t, json.Unmarshal(buffer.Bytes(), &decoded))
+	require.Len(t, decoded.Nodes, len(graph.Nodes))
+	require.Len(t, decoded.Edges, len(graph.Edges))
+
+	buffer.Reset()
+	require.Nil(t, graph.Write(&buffer, FormatDOT))
+	dot := buffer.String()
+	require.True(t, strings.HasPrefix(dot, "digraph templates {"))
+	require.Contains(t, dot, `label="matcher:logged-in"`)
+	require.Contains(t, dot, `"missing.yaml" [shape=plaintext, fontcolor=red];`)
+
+	require.NotNil(t, graph.Write(&buffer, "xml"))
+}
@@ -0,0 +1,156 @@
+package lint
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// protocolKeys are the template keys holding protocol requests
+var protocolKeys = []string{"requests", "http", "dns", "file", "network", "tcp", "headless", "ssl", "websocket", "whois", "code", "javascript"}
+
+// Document is a parsed template keeping the yaml node tree so that
+// findings can point to lines in the template.
+type Document struct {
+	Path string
+	Data []byte
+	// Root is the top level mapping node of the template
+	Root *yaml.Node
+}
+
+// Request is a single protocol request of a template
+type Request struct {
+	Protocol string
+	Node     *yaml.Node
+}
+
+// ParseDocument parses template data into a document
+func ParseDocument(path string, data []byte) (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, errors.Wrap(err, "could not parse template")
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("template is not a yaml mapping")
+	}
+	return &Document{Path: path, Data: data, Root: root.Content[0]}, nil
+}
+
+// ID returns the id of the template
+func (d *Document) ID() string {
+	return scalar(lookup(d.Root, "id"))
+}
+
+// Info returns the info block of the template
+func (d *Document) Info() *yaml.Node {
+	return lookup(d.Root, "info")
+}
+
+// Requests returns all protocol requests of the template
+func (d *Document) Requests() []*Request {
+	var requests []*Request
+	for _, key := range protocolKeys {
+		node := lookup(d.Root, key)
+		if node == nil || node.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range node.Content {
+			if item.Kind == yaml.MappingNode {
+				requests = append(requests, &Request{Protocol: key, Node: item})
+			}
+		}
+	}
+	return requests
+}
+
+// Matchers returns the matcher nodes of the request
+func (r *Request) Matchers() []*yaml.Node {
+	return sequence(lookup(r.Node, "matchers"))
+}
+
+// Extractors returns the extractor nodes of the request
+func (r *Request) Extractors() []*yaml.Node {
+	return sequence(lookup(r.Node, "extractors"))
+}
+
+// Operators returns both the matcher and extractor nodes of the request
+func (r *Request) Operators() []*yaml.Node {
+	matchers, extractors := r.Matchers(), r.Extractors()
+	operators := make([]*yaml.Node, 0, len(matchers)+len(extractors))
+	operators = append(operators, matchers...)
+	return append(operators, extractors...)
+}
+
+// MatchersCondition returns the condition between the matchers of the request
+func (r *Request) MatchersCondition() string {
+	if condition := scalar(lookup(r.Node, "matchers-condition")); condition != "" {
+		return condition
+	}
+	return "or"
+}
+
+// lookup returns the value node of key in a mapping node
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	keyNode, value := lookupPair(node, key)
+	if keyNode == nil {
+		return nil
+	}
+	return value
+}
+
+// lookupPair returns the key and value nodes of key in a mapping node
+func lookupPair(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// scalar returns the value of a scalar node
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// sequence returns the items of a sequence node
+func sequence(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// scalars returns the values of a sequence of scalars. A single scalar
+// is returned as a one item list.
+func scalars(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.ScalarNode {
+		return []*yaml.Node{node}
+	}
+	var values []*yaml.Node
+	for _, item := range sequence(node) {
+		if item.Kind == yaml.ScalarNode {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// walk calls fn for every node of the tree in document order
+func walk(node *yaml.Node, fn func(node *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	for _, child := range node.Content {
+		walk(child, fn)
+	}
+}
@@ -0,0 +1,175 @@
+// Package lint implements a local lint engine for nuclei templates
+// with a pluggable set of rules.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// Level is the severity level of a lint finding
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Finding is a single issue reported by a lint rule
+type Finding struct {
+	RuleID  string `json:"rule-id"`
+	Level   Level  `json:"level"`
+	Message string `json:"message"`
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// String returns the finding in path:line:column format
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s:%d:%d: [%s] %s: %s", f.Path, f.Line, f.Column, f.Level, f.RuleID, f.Message)
+}
+
+// Rule is a lint rule checking a template document
+type Rule interface {
+	// ID returns the unique identifier of the rule
+	ID() string
+	// Description returns a short description of what the rule checks
+	Description() string
+	// Level returns the level of the findings reported by the rule
+	Level() Level
+	// Check returns the findings of the rule for the document
+	Check(doc *Document) []*Finding
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Rule)
+)
+
+// Register registers a rule making it available to all linters. A rule
+// with the same id as an existing one replaces it.
+func Register(rule Rule) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[rule.ID()] = rule
+}
+
+// Rules returns all registered rules sorted by id
+func Rules() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	rules := make([]Rule, 0, len(registry))
+	for _, rule := range registry {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID() < rules[j].ID()
+	})
+	return rules
+}
+
+// Options contains the configuration options for the linter
+type Options struct {
+	// Rules is the list of rule ids to run. All rules are run if empty.
+	Rules []string
+	// ExcludeRules is the list of rule ids to skip
+	ExcludeRules []string
+}
+
+// Linter lints templates with a set of rules
+type Linter struct {
+	rules []Rule
+}
+
+// New returns a linter running the registered rules selected by options
+func New(options *Options) (*Linter, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	registryMu.RLock()
+	for _, id := range append(options.Rules, options.ExcludeRules...) {
+		if _, ok := registry[id]; !ok {
+			registryMu.RUnlock()
+			return nil, errors.Errorf("unknown lint rule: %s", id)
+		}
+	}
+	registryMu.RUnlock()
+
+	linter := &Linter{}
+	for _, rule := range Rules() {
+		if len(options.Rules) > 0 && !sliceutil.Contains(options.Rules, rule.ID()) {
+			continue
+		}
+		if sliceutil.Contains(options.ExcludeRules, rule.ID()) {
+			continue
+		}
+		linter.rules = append(linter.rules, rule)
+	}
+	return linter, nil
+}
+
+// Rules returns the rules run by the linter
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// LintFile lints the template at path
+func (l *Linter) LintFile(path string) ([]*Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read template")
+	}
+	return l.Lint(path, data)
+}
+
+// Lint lints the template data. path is only used to report findings.
+func (l *Linter) Lint(path string, data []byte) ([]*Finding, error) {
+	doc, err := ParseDocument(path, data)
+	if err != nil {
+		return nil, err
+	}
+	var findings []*Finding
+	for _, rule := range l.rules {
+		for _, finding := range rule.Check(doc) {
+			finding.RuleID = rule.ID()
+			finding.Level = rule.Level()
+			finding.Path = path
+			findings = append(findings, finding)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// HasErrors returns true if any of the findings is an error
+func HasErrors(findings []*Finding) bool {
+	for _, finding := range findings {
+		if finding.Level == LevelError {
+			return true
+		}
+	}
+	return false
+}
+
+// newFinding returns a finding located at the yaml node
+func newFinding(node *yaml.Node, format string, args ...interface{}) *Finding {
+	finding := &Finding{Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		finding.Line = node.Line
+		finding.Column = node.Column
+	}
+	return finding
+}
@@ -0,0 +1,196 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const cleanTemplate = `id: clean-template
+
+info:
+  name: clean template
+  author: pdteam
+  severity: info
+  metadata:
+    max-request: 1
+
+variables:
+  endpoint: "/login"
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}{{endpoint}}"
+
+    matchers-condition: and
+    matchers:
+      - type: word
+        words:
+          - "admin panel"
+      - type: status
+        status:
+          - 200
+`
+
+func lintRule(t *testing.T, rule, template string) []*Finding {
+	linter, err := New(&Options{Rules: []string{rule}})
+	require.Nil(t, err, "could not create linter")
+	findings, err := linter.Lint("template.yaml", []byte(template))
+	require.Nil(t, err, "could not lint template")
+	return findings
+}
+
+func TestLintCleanTemplate(t *testing.T) {
+	linter, err := New(nil)
+	require.Nil(t, err)
+	findings, err := linter.Lint("template.yaml", []byte(cleanTemplate))
+	require.Nil(t, err)
+	require.Empty(t, findings)
+}
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		rule     string
+		template string
+		line     int
+	}{
+		{
+			rule: "unused-variable",
+			template: `id: test
+variables:
+  unused: "value"
+http:
+  - path:
+      - "{{BaseURL}}"
+`,
+			line: 3,
+		},
+		{
+			rule: "unreachable-matcher",
+			template: `id: test
+http:
+  - path:
+      - "{{BaseURL}}"
+    matchers-condition: and
+    matchers:
+      - type: word
+        words:
+          - "admin"
+      - type: word
+        negative: true
+        words:
+          - "admin"
+`,
+			line: 10,
+		},
+		{
+			rule: "regex-backtracking",
+			template: `id: test
+http:
+  - path:
+      - "{{BaseURL}}"
+    extractors:
+      - type: regex
+        regex:
+          - "(\\w+\\s?)*$"
+`,
+			line: 8,
+		},
+		{
+			rule: "missing-max-request",
+			template: `id: test
+info:
+  name: test
+dns:
+  - name: "{{FQDN}}"
+`,
+			line: 3,
+		},
+		{
+			rule: "status-only-matcher",
+			template: `id: test
+http:
+  - path:
+      - "{{BaseURL}}"
+    matchers:
+      - type: status
+        status:
+          - 200
+`,
+			line: 6,
+		},
+		{
+			rule: "invalid-dsl",
+			template: `id: test
+http:
+  - path:
+      - "{{BaseURL}}"
+    matchers:
+      - type: dsl
+        dsl:
+          - "status_code == 200 &&"
+`,
+			line: 8,
+		},
+		{
+			rule: "cve-missing-classification",
+			template: `id: CVE-2021-12345
+info:
+  name: test
+  classification:
+    cvss-score: 9.8
+`,
+			line: 5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			findings := lintRule(t, test.rule, test.template)
+			require.Len(t, findings, 1, "could not get finding")
+			require.Equal(t, test.rule, findings[0].RuleID)
+			require.Equal(t, test.line, findings[0].Line)
+
+			require.Empty(t, lintRule(t, test.rule, cleanTemplate), "got finding for clean template")
+		})
+	}
+}
+
+type customRule struct{}
+
+func (customRule) ID() string          { return "custom-rule" }
+func (customRule) Description() string { return "reports every template" }
+func (customRule) Level() Level        { return LevelNote }
+func (customRule) Check(doc *Document) []*Finding {
+	return []*Finding{newFinding(doc.Root, "template %s", doc.ID())}
+}
+
+func TestRegisterRule(t *testing.T) {
+	Register(customRule{})
+
+	findings := lintRule(t, "custom-rule", cleanTemplate)
+	require.Len(t, findings, 1)
+	require.Equal(t, "template clean-template", findings[0].Message)
+	require.Equal(t, LevelNote, findings[0].Level)
+
+	_, err := New(&Options{Rules: []string{"not-a-rule"}})
+	require.NotNil(t, err, "could create linter with unknown rule")
+}
+
+func TestWriteSARIF(t *testing.T) {
+	linter, err := New(&Options{Rules: []string{"invalid-dsl"}})
+	require.Nil(t, err)
+	findings := []*Finding{{RuleID: "invalid-dsl", Level: LevelError, Message: "bad", Path: "a.yaml", Line: 3, Column: 5}}
+
+	var buffer bytes.Buffer
+	require.Nil(t, Write(&buffer, FormatSARIF, findings, linter.Rules()))
+
+	var report sarifLog
+	require.Nil(t, json.Unmarshal(buffer.Bytes(), &report))
+	require.Len(t, report.Runs, 1)
+	require.Len(t, report.Runs[0].Results, 1)
+	require.Equal(t, 3, report.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	require.True(t, HasErrors(findings))
+}
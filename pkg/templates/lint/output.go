@@ -0,0 +1,146 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/config"
+)
+
+// Supported output formats for lint findings
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Write writes the findings to writer in the given format
+func Write(writer io.Writer, format string, findings []*Finding, rules []Rule) error {
+	switch format {
+	case FormatText, "":
+		for _, finding := range findings {
+			if _, err := fmt.Fprintln(writer, finding.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		if findings == nil {
+			findings = []*Finding{}
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	case FormatSARIF:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(toSARIF(findings, rules))
+	default:
+		return errors.Errorf("unsupported lint output format: %s", format)
+	}
+}
+
+// sarif types are declared here since the region of a location (line
+// and column of a finding) is not available in the sarif library used
+// by the reporting exporter.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name            string      `json:"name"`
+	Organization    string      `json:"organization,omitempty"`
+	SemanticVersion string      `json:"semanticVersion,omitempty"`
+	InformationURI  string      `json:"informationUri,omitempty"`
+	Rules           []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level Level `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex *int            `json:"ruleIndex,omitempty"`
+	Level     Level           `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func toSARIF(findings []*Finding, rules []Rule) *sarifLog {
+	driver := sarifDriver{
+		Name:            "nuclei-template-lint",
+		Organization:    "ProjectDiscovery",
+		SemanticVersion: config.Version,
+		InformationURI:  "https://github.com/projectdiscovery/nuclei",
+		Rules:           []sarifRule{},
+	}
+	ruleIndex := make(map[string]int)
+	for _, rule := range rules {
+		sarifRule := sarifRule{ID: rule.ID(), ShortDescription: sarifMessage{Text: rule.Description()}}
+		sarifRule.DefaultConfiguration.Level = rule.Level()
+		ruleIndex[rule.ID()] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sarifRule)
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, finding := range findings {
+		result := sarifResult{
+			RuleID:  finding.RuleID,
+			Level:   finding.Level,
+			Message: sarifMessage{Text: finding.Message},
+		}
+		if index, ok := ruleIndex[finding.RuleID]; ok {
+			result.RuleIndex = &index
+		}
+		location := sarifLocation{}
+		location.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(finding.Path)
+		if finding.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+		}
+		result.Locations = []sarifLocation{location}
+		run.Results = append(run.Results, result)
+	}
+	return &sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}
+}
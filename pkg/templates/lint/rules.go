@@ -0,0 +1,316 @@
+package lint
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+	"gopkg.in/yaml.v3"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators/common/dsl"
+)
+
+func init() {
+	for _, r := range []Rule{
+		&rule{
+			id:          "unused-variable",
+			description: "variables and payloads that are never referenced",
+			level:       LevelWarning,
+			check:       checkUnusedVariables,
+		},
+		&rule{
+			id:          "unreachable-matcher",
+			description: "matchers that can never match",
+			level:       LevelWarning,
+			check:       checkUnreachableMatchers,
+		},
+		&rule{
+			id:          "regex-backtracking",
+			description: "regexes with nested quantifiers that risk catastrophic backtracking",
+			level:       LevelWarning,
+			check:       checkRegexBacktracking,
+		},
+		&rule{
+			id:          "missing-max-request",
+			description: "templates sending requests without max-request metadata",
+			level:       LevelWarning,
+			check:       checkMissingMaxRequest,
+		},
+		&rule{
+			id:          "status-only-matcher",
+			description: "requests that only match on a 200 status code",
+			level:       LevelWarning,
+			check:       checkStatusOnlyMatchers,
+		},
+		&rule{
+			id:          "invalid-dsl",
+			description: "dsl expressions that do not compile",
+			level:       LevelError,
+			check:       checkInvalidDSL,
+		},
+		&rule{
+			id:          "cve-missing-classification",
+			description: "cve templates without classification details",
+			level:       LevelWarning,
+			check:       checkCVEClassification,
+		},
+	} {
+		Register(r)
+	}
+}
+
+// rule is a lint rule implemented by a check function
+type rule struct {
+	id          string
+	description string
+	level       Level
+	check       func(doc *Document) []*Finding
+}
+
+func (r *rule) ID() string                     { return r.id }
+func (r *rule) Description() string            { return r.description }
+func (r *rule) Level() Level                   { return r.level }
+func (r *rule) Check(doc *Document) []*Finding { return r.check(doc) }
+
+// matcherValueKeys maps matcher types to the key holding their values
+var matcherValueKeys = map[string]string{
+	"word":   "words",
+	"words":  "words",
+	"regex":  "regex",
+	"status": "status",
+	"size":   "size",
+	"binary": "binary",
+	"dsl":    "dsl",
+	"xpath":  "xpath",
+}
+
+func checkUnusedVariables(doc *Document) []*Finding {
+	type definition struct {
+		kind string
+		key  *yaml.Node
+	}
+	var definitions []definition
+	if variables := lookup(doc.Root, "variables"); variables != nil && variables.Kind == yaml.MappingNode {
+		for i := 0; i < len(variables.Content); i += 2 {
+			definitions = append(definitions, definition{kind: "variable", key: variables.Content[i]})
+		}
+	}
+	for _, request := range doc.Requests() {
+		payloads := lookup(request.Node, "payloads")
+		if payloads == nil || payloads.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i < len(payloads.Content); i += 2 {
+			definitions = append(definitions, definition{kind: "payload", key: payloads.Content[i]})
+		}
+	}
+
+	var findings []*Finding
+	for _, def := range definitions {
+		reference := regexp.MustCompile(`\b` + regexp.QuoteMeta(def.key.Value) + `\b`)
+		var used bool
+		walk(doc.Root, func(node *yaml.Node) {
+			if used || node == def.key || node.Kind != yaml.ScalarNode {
+				return
+			}
+			used = reference.MatchString(node.Value)
+		})
+		if !used {
+			findings = append(findings, newFinding(def.key, "%s '%s' is never used", def.kind, def.key.Value))
+		}
+	}
+	return findings
+}
+
+func checkUnreachableMatchers(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, request := range doc.Requests() {
+		matchers := request.Matchers()
+		for _, matcher := range matchers {
+			matcherType := scalar(lookup(matcher, "type"))
+			key, ok := matcherValueKeys[matcherType]
+			if ok && len(scalars(lookup(matcher, key))) == 0 {
+				findings = append(findings, newFinding(matcher, "%s matcher has no %s values and can never match", matcherType, key))
+			}
+		}
+		if request.MatchersCondition() != "and" {
+			continue
+		}
+
+		// with and condition all matchers must match at the same time
+		var statuses map[string]struct{}
+		for _, matcher := range matchers {
+			if scalar(lookup(matcher, "type")) != "status" || scalar(lookup(matcher, "negative")) == "true" {
+				continue
+			}
+			current := make(map[string]struct{})
+			for _, status := range scalars(lookup(matcher, "status")) {
+				if statuses == nil {
+					current[status.Value] = struct{}{}
+				} else if _, ok := statuses[status.Value]; ok {
+					current[status.Value] = struct{}{}
+				}
+			}
+			if statuses != nil && len(current) == 0 {
+				findings = append(findings, newFinding(matcher, "status matcher excludes the statuses of previous status matchers with and condition"))
+			}
+			statuses = current
+		}
+		for i, matcher := range matchers {
+			for _, other := range matchers[i+1:] {
+				if contradicts(matcher, other) {
+					findings = append(findings, newFinding(other, "matcher negates a previous matcher with and condition"))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// contradicts returns true if one of the matchers is the negation of the other
+func contradicts(a, b *yaml.Node) bool {
+	if (scalar(lookup(a, "negative")) == "true") == (scalar(lookup(b, "negative")) == "true") {
+		return false
+	}
+	matcherType := scalar(lookup(a, "type"))
+	if matcherType != scalar(lookup(b, "type")) || scalar(lookup(a, "part")) != scalar(lookup(b, "part")) {
+		return false
+	}
+	key, ok := matcherValueKeys[matcherType]
+	if !ok {
+		return false
+	}
+	valuesA, valuesB := scalars(lookup(a, key)), scalars(lookup(b, key))
+	if len(valuesA) == 0 || len(valuesA) != len(valuesB) {
+		return false
+	}
+	for i := range valuesA {
+		if valuesA[i].Value != valuesB[i].Value {
+			return false
+		}
+	}
+	return true
+}
+
+func checkRegexBacktracking(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, request := range doc.Requests() {
+		for _, operator := range request.Operators() {
+			if scalar(lookup(operator, "type")) != "regex" {
+				continue
+			}
+			for _, value := range scalars(lookup(operator, "regex")) {
+				parsed, err := syntax.Parse(value.Value, syntax.Perl)
+				if err != nil {
+					continue
+				}
+				if hasNestedQuantifier(parsed, false) {
+					findings = append(findings, newFinding(value, "regex '%s' has nested quantifiers and risks catastrophic backtracking", value.Value))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// hasNestedQuantifier returns true if an unbounded repetition contains
+// another unbounded repetition, e.g. (a+)+ or (\w+\s?)*
+func hasNestedQuantifier(re *syntax.Regexp, inRepeat bool) bool {
+	unbounded := re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max == -1)
+	if unbounded && inRepeat {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasNestedQuantifier(sub, inRepeat || unbounded) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkMissingMaxRequest(doc *Document) []*Finding {
+	if len(doc.Requests()) == 0 {
+		return nil
+	}
+	info := doc.Info()
+	if lookup(lookup(info, "metadata"), "max-request") != nil {
+		return nil
+	}
+	node := info
+	if node == nil {
+		node = doc.Root
+	}
+	return []*Finding{newFinding(node, "template sends requests but info.metadata.max-request is not set")}
+}
+
+func checkStatusOnlyMatchers(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, request := range doc.Requests() {
+		matchers := request.Matchers()
+		if len(matchers) == 0 {
+			continue
+		}
+		var has200 bool
+		statusOnly := true
+		for _, matcher := range matchers {
+			if scalar(lookup(matcher, "type")) != "status" {
+				statusOnly = false
+				break
+			}
+			for _, status := range scalars(lookup(matcher, "status")) {
+				if status.Value == "200" {
+					has200 = true
+				}
+			}
+		}
+		if statusOnly && has200 {
+			findings = append(findings, newFinding(lookup(request.Node, "matchers"), "request only matches on status 200 which is prone to false positives"))
+		}
+	}
+	return findings
+}
+
+func checkInvalidDSL(doc *Document) []*Finding {
+	var findings []*Finding
+	for _, request := range doc.Requests() {
+		for _, operator := range request.Operators() {
+			if scalar(lookup(operator, "type")) != "dsl" {
+				continue
+			}
+			for _, value := range scalars(lookup(operator, "dsl")) {
+				if _, err := govaluate.NewEvaluableExpressionWithFunctions(value.Value, dsl.HelperFunctions); err != nil {
+					findings = append(findings, newFinding(value, "dsl expression '%s' does not compile: %s", value.Value, err))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+var cveIDRegex = regexp.MustCompile(`(?i)^CVE-\d{4}-\d+$`)
+
+func checkCVEClassification(doc *Document) []*Finding {
+	info := doc.Info()
+	isCVE := cveIDRegex.MatchString(doc.ID())
+	for _, tag := range strings.Split(scalar(lookup(info, "tags")), ",") {
+		if strings.EqualFold(strings.TrimSpace(tag), "cve") {
+			isCVE = true
+		}
+	}
+	if !isCVE {
+		return nil
+	}
+	node := info
+	if node == nil {
+		node = doc.Root
+	}
+	classification := lookup(info, "classification")
+	if classification == nil {
+		return []*Finding{newFinding(node, "cve template is missing info.classification")}
+	}
+	if scalar(lookup(classification, "cve-id")) == "" {
+		return []*Finding{newFinding(classification, "cve template classification is missing cve-id")}
+	}
+	return nil
+}
@@ -241,7 +241,14 @@ func tryCollectConditionsMatchinfo(template *Template) map[string]interface{} {
 		parameters["body"] = strings.ToLower(strings.Join(bodies, "\n"))
 	}
 
-	// collect matchers types
+	parameters["matcher_type"] = template.MatcherTypes()
+	parameters["extractor_type"] = template.ExtractorTypes()
+
+	return parameters
+}
+
+// MatcherTypes returns the unique matcher types used by the requests of the template
+func (template *Template) MatcherTypes() []string {
 	var matcherTypes []string
 	for _, req := range template.RequestsDNS {
 		matcherTypes = append(matcherTypes, collectMatcherTypes(req.Matchers)...)
@@ -267,10 +274,11 @@ func tryCollectConditionsMatchinfo(template *Template) map[string]interface{} {
 	for _, req := range template.RequestsWebsocket {
 		matcherTypes = append(matcherTypes, collectMatcherTypes(req.Matchers)...)
 	}
-	matcherTypes = sliceutil.Dedupe(sliceutil.PruneEmptyStrings(matcherTypes))
-	parameters["matcher_type"] = matcherTypes
+	return sliceutil.Dedupe(sliceutil.PruneEmptyStrings(matcherTypes))
+}
 
-	// collect extractors types
+// ExtractorTypes returns the unique extractor types used by the requests of the template
+func (template *Template) ExtractorTypes() []string {
 	var extractorTypes []string
 	for _, req := range template.RequestsDNS {
 		extractorTypes = append(extractorTypes, collectExtractorTypes(req.Extractors)...)
@@ -296,10 +304,7 @@ func tryCollectConditionsMatchinfo(template *Template) map[string]interface{} {
 	for _, req := range template.RequestsWebsocket {
 		extractorTypes = append(extractorTypes, collectExtractorTypes(req.Extractors)...)
 	}
-	extractorTypes = sliceutil.Dedupe(sliceutil.PruneEmptyStrings(extractorTypes))
-	parameters["extractor_type"] = extractorTypes
-
-	return parameters
+	return sliceutil.Dedupe(sliceutil.PruneEmptyStrings(extractorTypes))
 }
 
 func collectMatcherTypes(matchers []*matchers.Matcher) []string {
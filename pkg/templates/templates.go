@@ -123,6 +123,9 @@ type Template struct {
 	//   Workflows is a yaml based workflow declaration code.
 	workflows.Workflow `yaml:",inline,omitempty" jsonschema:"title=workflows to run,description=Workflows to run for the template"`
 	CompiledWorkflow   *workflows.Workflow `yaml:"-" json:"-" jsonschema:"-"`
+	// description: |
+	//   Pivot runs templates on the targets passed by the flow using the pivot function.
+	Pivot *workflows.Pivot `yaml:"pivot,omitempty" json:"pivot,omitempty" jsonschema:"title=pivot targets passed by flow,description=Pivot runs templates on the targets passed by the flow using the pivot function"`
 
 	// description: |
 	//   Self Contained marks Requests for the template as self-contained
@@ -194,10 +197,36 @@ func (template *Template) Type() types.ProtocolType {
 	}
 }
 
+// Protocols returns the types of all the protocols with requests in the template
+func (template *Template) Protocols() types.ProtocolTypes {
+	var protocolTypes types.ProtocolTypes
+	for _, protocol := range []struct {
+		protocolType types.ProtocolType
+		requests     int
+	}{
+		{types.DNSProtocol, len(template.RequestsDNS)},
+		{types.FileProtocol, len(template.RequestsFile)},
+		{types.HTTPProtocol, len(template.RequestsHTTP)},
+		{types.HeadlessProtocol, len(template.RequestsHeadless)},
+		{types.NetworkProtocol, len(template.RequestsNetwork)},
+		{types.SSLProtocol, len(template.RequestsSSL)},
+		{types.WebsocketProtocol, len(template.RequestsWebsocket)},
+		{types.WHOISProtocol, len(template.RequestsWHOIS)},
+		{types.CodeProtocol, len(template.RequestsCode)},
+		{types.JavascriptProtocol, len(template.RequestsJavascript)},
+		{types.WorkflowProtocol, len(template.Workflow.Workflows)},
+	} {
+		if protocol.requests > 0 {
+			protocolTypes = append(protocolTypes, protocol.protocolType)
+		}
+	}
+	return protocolTypes
+}
+
 // IsFuzzing returns true if the template is a fuzzing template
 func (template *Template) IsFuzzing() bool {
-	if len(template.RequestsHTTP) == 0 && len(template.RequestsHeadless) == 0 {
-		// fuzzing is only supported for http and headless protocols
+	if len(template.RequestsHTTP) == 0 && len(template.RequestsHeadless) == 0 && len(template.RequestsWebsocket) == 0 {
+		// fuzzing is only supported for http, headless and websocket protocols
 		return false
 	}
 	if len(template.RequestsHTTP) > 0 {
@@ -214,6 +243,11 @@ func (template *Template) IsFuzzing() bool {
 			}
 		}
 	}
+	for _, request := range template.RequestsWebsocket {
+		if len(request.Fuzzing) > 0 {
+			return true
+		}
+	}
 	return false
 }
 
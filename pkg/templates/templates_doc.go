@@ -17,6 +17,7 @@ var (
 	MODELClassificationDoc        encoder.Doc
 	HTTPRequestDoc                encoder.Doc
 	GENERATORSAttackTypeHolderDoc encoder.Doc
+	GENERATORSPayloadSpecDoc      encoder.Doc
 	HTTPMethodTypeHolderDoc       encoder.Doc
 	FUZZRuleDoc                   encoder.Doc
 	SliceOrMapSliceDoc            encoder.Doc
@@ -40,6 +41,7 @@ var (
 	WHOISRequestDoc               encoder.Doc
 	CODERequestDoc                encoder.Doc
 	JAVASCRIPTRequestDoc          encoder.Doc
+	WORKFLOWSPivotDoc             encoder.Doc
 	HTTPSignatureTypeHolderDoc    encoder.Doc
 	VARIABLESVariableDoc          encoder.Doc
 )
@@ -48,7 +50,7 @@ func init() {
 	TemplateDoc.Type = "Template"
 	TemplateDoc.Comments[encoder.LineComment] = " Template is a YAML input file which defines all the requests and"
 	TemplateDoc.Description = "Template is a YAML input file which defines all the requests and\n other metadata for a template."
-	TemplateDoc.Fields = make([]encoder.Doc, 20)
+	TemplateDoc.Fields = make([]encoder.Doc, 21)
 	TemplateDoc.Fields[0].Name = "id"
 	TemplateDoc.Fields[0].Type = "string"
 	TemplateDoc.Fields[0].Note = ""
@@ -136,34 +138,39 @@ func init() {
 	TemplateDoc.Fields[14].Note = ""
 	TemplateDoc.Fields[14].Description = "Javascript contains the javascript request to make in the template."
 	TemplateDoc.Fields[14].Comments[encoder.LineComment] = "Javascript contains the javascript request to make in the template."
-	TemplateDoc.Fields[15].Name = "self-contained"
-	TemplateDoc.Fields[15].Type = "bool"
+	TemplateDoc.Fields[15].Name = "pivot"
+	TemplateDoc.Fields[15].Type = "workflows.Pivot"
 	TemplateDoc.Fields[15].Note = ""
-	TemplateDoc.Fields[15].Description = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
-	TemplateDoc.Fields[16].Name = "stop-at-first-match"
+	TemplateDoc.Fields[15].Description = "Pivot runs templates on the targets passed by the flow using the pivot function."
+	TemplateDoc.Fields[15].Comments[encoder.LineComment] = "Pivot runs templates on the targets passed by the flow using the pivot function."
+	TemplateDoc.Fields[16].Name = "self-contained"
 	TemplateDoc.Fields[16].Type = "bool"
 	TemplateDoc.Fields[16].Note = ""
-	TemplateDoc.Fields[16].Description = "Stop execution once first match is found"
-	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "Stop execution once first match is found"
-	TemplateDoc.Fields[17].Name = "signature"
-	TemplateDoc.Fields[17].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[16].Description = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[16].Comments[encoder.LineComment] = "Self Contained marks Requests for the template as self-contained"
+	TemplateDoc.Fields[17].Name = "stop-at-first-match"
+	TemplateDoc.Fields[17].Type = "bool"
 	TemplateDoc.Fields[17].Note = ""
-	TemplateDoc.Fields[17].Description = "Signature is the request signature method\nWARNING: 'signature' will be deprecated and will be removed in a future release. Prefer using 'code' protocol for writing cloud checks"
-	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "Signature is the request signature method"
-	TemplateDoc.Fields[17].Values = []string{
+	TemplateDoc.Fields[17].Description = "Stop execution once first match is found"
+	TemplateDoc.Fields[17].Comments[encoder.LineComment] = "Stop execution once first match is found"
+	TemplateDoc.Fields[18].Name = "signature"
+	TemplateDoc.Fields[18].Type = "http.SignatureTypeHolder"
+	TemplateDoc.Fields[18].Note = ""
+	TemplateDoc.Fields[18].Description = "Signature is the request signature method\nWARNING: 'signature' will be deprecated and will be removed in a future release. Prefer using 'code' protocol for writing cloud checks"
+	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "Signature is the request signature method"
+	TemplateDoc.Fields[18].Values = []string{
 		"AWS",
 	}
-	TemplateDoc.Fields[18].Name = "variables"
-	TemplateDoc.Fields[18].Type = "variables.Variable"
-	TemplateDoc.Fields[18].Note = ""
-	TemplateDoc.Fields[18].Description = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[18].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
-	TemplateDoc.Fields[19].Name = "constants"
-	TemplateDoc.Fields[19].Type = "map[string]interface{}"
+	TemplateDoc.Fields[19].Name = "variables"
+	TemplateDoc.Fields[19].Type = "variables.Variable"
 	TemplateDoc.Fields[19].Note = ""
-	TemplateDoc.Fields[19].Description = "Constants contains any scalar constant for the current template"
-	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[19].Description = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[19].Comments[encoder.LineComment] = "Variables contains any variables for the current request."
+	TemplateDoc.Fields[20].Name = "constants"
+	TemplateDoc.Fields[20].Type = "map[string]interface{}"
+	TemplateDoc.Fields[20].Note = ""
+	TemplateDoc.Fields[20].Description = "Constants contains any scalar constant for the current template"
+	TemplateDoc.Fields[20].Comments[encoder.LineComment] = "Constants contains any scalar constant for the current template"
 
 	MODELInfoDoc.Type = "model.Info"
 	MODELInfoDoc.Comments[encoder.LineComment] = " Info contains metadata information about a template"
@@ -278,6 +285,14 @@ func init() {
 			TypeName:  "model.Classification",
 			FieldName: "cwe-id",
 		},
+		{
+			TypeName:  "workflows.Pivot",
+			FieldName: "extractors",
+		},
+		{
+			TypeName:  "workflows.Pivot",
+			FieldName: "tags",
+		},
 	}
 	STRINGSLICEStringSliceDoc.Fields = make([]encoder.Doc, 0)
 
@@ -447,6 +462,10 @@ func init() {
 			Key:   "duration",
 			Value: "HTTP request time duration",
 		},
+		{
+			Key:   "protocol",
+			Value: "HTTP protocol negotiated with the server",
+		},
 		{
 			Key:   "all",
 			Value: "HTTP response body + headers",
@@ -460,7 +479,7 @@ func init() {
 			Value: "HTTP response headers in name:value format",
 		},
 	}
-	HTTPRequestDoc.Fields = make([]encoder.Doc, 38)
+	HTTPRequestDoc.Fields = make([]encoder.Doc, 39)
 	HTTPRequestDoc.Fields[0].Name = "path"
 	HTTPRequestDoc.Fields[0].Type = "[]string"
 	HTTPRequestDoc.Fields[0].Note = ""
@@ -624,61 +643,66 @@ func init() {
 	HTTPRequestDoc.Fields[26].Note = ""
 	HTTPRequestDoc.Fields[26].Description = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests.\n\nThis uses the [rawhttp](https://github.com/projectdiscovery/rawhttp) engine to achieve complete\ncontrol over the request, with no normalization performed by the client."
 	HTTPRequestDoc.Fields[26].Comments[encoder.LineComment] = "Unsafe specifies whether to use rawhttp engine for sending Non RFC-Compliant requests."
-	HTTPRequestDoc.Fields[27].Name = "race"
+	HTTPRequestDoc.Fields[27].Name = "http3"
 	HTTPRequestDoc.Fields[27].Type = "bool"
 	HTTPRequestDoc.Fields[27].Note = ""
-	HTTPRequestDoc.Fields[27].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
-	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
-	HTTPRequestDoc.Fields[28].Name = "req-condition"
+	HTTPRequestDoc.Fields[27].Description = "HTTP3 specifies whether https requests should be sent over HTTP/3 (QUIC).\n\nThe QUIC endpoint is discovered from the Alt-Svc header of the host. Requests\nto hosts without one, failing over QUIC or sent through a proxy use HTTP/1.1 or\nHTTP/2 instead. The negotiated protocol is available as `protocol`."
+	HTTPRequestDoc.Fields[27].Comments[encoder.LineComment] = "HTTP3 specifies whether https requests should be sent over HTTP/3 (QUIC)."
+	HTTPRequestDoc.Fields[28].Name = "race"
 	HTTPRequestDoc.Fields[28].Type = "bool"
 	HTTPRequestDoc.Fields[28].Note = ""
-	HTTPRequestDoc.Fields[28].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
-	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
-	HTTPRequestDoc.Fields[29].Name = "stop-at-first-match"
+	HTTPRequestDoc.Fields[28].Description = "Race determines if all the request have to be attempted at the same time (Race Condition)\n\nThe actual number of requests that will be sent is determined by the `race_count`  field."
+	HTTPRequestDoc.Fields[28].Comments[encoder.LineComment] = "Race determines if all the request have to be attempted at the same time (Race Condition)"
+	HTTPRequestDoc.Fields[29].Name = "req-condition"
 	HTTPRequestDoc.Fields[29].Type = "bool"
 	HTTPRequestDoc.Fields[29].Note = ""
-	HTTPRequestDoc.Fields[29].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
-	HTTPRequestDoc.Fields[30].Name = "skip-variables-check"
+	HTTPRequestDoc.Fields[29].Description = "ReqCondition automatically assigns numbers to requests and preserves their history.\n\nThis allows matching on them later for multi-request conditions."
+	HTTPRequestDoc.Fields[29].Comments[encoder.LineComment] = "ReqCondition automatically assigns numbers to requests and preserves their history."
+	HTTPRequestDoc.Fields[30].Name = "stop-at-first-match"
 	HTTPRequestDoc.Fields[30].Type = "bool"
 	HTTPRequestDoc.Fields[30].Note = ""
-	HTTPRequestDoc.Fields[30].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
-	HTTPRequestDoc.Fields[31].Name = "iterate-all"
+	HTTPRequestDoc.Fields[30].Description = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[30].Comments[encoder.LineComment] = "StopAtFirstMatch stops the execution of the requests and template as soon as a match is found."
+	HTTPRequestDoc.Fields[31].Name = "skip-variables-check"
 	HTTPRequestDoc.Fields[31].Type = "bool"
 	HTTPRequestDoc.Fields[31].Note = ""
-	HTTPRequestDoc.Fields[31].Description = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[31].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
-	HTTPRequestDoc.Fields[32].Name = "digest-username"
-	HTTPRequestDoc.Fields[32].Type = "string"
+	HTTPRequestDoc.Fields[31].Description = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[31].Comments[encoder.LineComment] = "SkipVariablesCheck skips the check for unresolved variables in request"
+	HTTPRequestDoc.Fields[32].Name = "iterate-all"
+	HTTPRequestDoc.Fields[32].Type = "bool"
 	HTTPRequestDoc.Fields[32].Note = ""
-	HTTPRequestDoc.Fields[32].Description = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[32].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
-	HTTPRequestDoc.Fields[33].Name = "digest-password"
+	HTTPRequestDoc.Fields[32].Description = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[32].Comments[encoder.LineComment] = "IterateAll iterates all the values extracted from internal extractors"
+	HTTPRequestDoc.Fields[33].Name = "digest-username"
 	HTTPRequestDoc.Fields[33].Type = "string"
 	HTTPRequestDoc.Fields[33].Note = ""
-	HTTPRequestDoc.Fields[33].Description = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[33].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
-	HTTPRequestDoc.Fields[34].Name = "disable-path-automerge"
-	HTTPRequestDoc.Fields[34].Type = "bool"
+	HTTPRequestDoc.Fields[33].Description = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[33].Comments[encoder.LineComment] = "DigestAuthUsername specifies the username for digest authentication"
+	HTTPRequestDoc.Fields[34].Name = "digest-password"
+	HTTPRequestDoc.Fields[34].Type = "string"
 	HTTPRequestDoc.Fields[34].Note = ""
-	HTTPRequestDoc.Fields[34].Description = "DisablePathAutomerge disables merging target url path with raw request path"
-	HTTPRequestDoc.Fields[34].Comments[encoder.LineComment] = "DisablePathAutomerge disables merging target url path with raw request path"
-	HTTPRequestDoc.Fields[35].Name = "pre-condition"
-	HTTPRequestDoc.Fields[35].Type = "[]matchers.Matcher"
+	HTTPRequestDoc.Fields[34].Description = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[34].Comments[encoder.LineComment] = "DigestAuthPassword specifies the password for digest authentication"
+	HTTPRequestDoc.Fields[35].Name = "disable-path-automerge"
+	HTTPRequestDoc.Fields[35].Type = "bool"
 	HTTPRequestDoc.Fields[35].Note = ""
-	HTTPRequestDoc.Fields[35].Description = "Fuzz PreCondition is matcher-like field to check if fuzzing should be performed on this request or not"
-	HTTPRequestDoc.Fields[35].Comments[encoder.LineComment] = "Fuzz PreCondition is matcher-like field to check if fuzzing should be performed on this request or not"
-	HTTPRequestDoc.Fields[36].Name = "pre-condition-operator"
-	HTTPRequestDoc.Fields[36].Type = "string"
+	HTTPRequestDoc.Fields[35].Description = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[35].Comments[encoder.LineComment] = "DisablePathAutomerge disables merging target url path with raw request path"
+	HTTPRequestDoc.Fields[36].Name = "pre-condition"
+	HTTPRequestDoc.Fields[36].Type = "[]matchers.Matcher"
 	HTTPRequestDoc.Fields[36].Note = ""
-	HTTPRequestDoc.Fields[36].Description = "FuzzPreConditionOperator is the operator between multiple PreConditions for fuzzing Default is OR"
-	HTTPRequestDoc.Fields[36].Comments[encoder.LineComment] = "FuzzPreConditionOperator is the operator between multiple PreConditions for fuzzing Default is OR"
-	HTTPRequestDoc.Fields[37].Name = "global-matchers"
-	HTTPRequestDoc.Fields[37].Type = "bool"
+	HTTPRequestDoc.Fields[36].Description = "Fuzz PreCondition is matcher-like field to check if fuzzing should be performed on this request or not"
+	HTTPRequestDoc.Fields[36].Comments[encoder.LineComment] = "Fuzz PreCondition is matcher-like field to check if fuzzing should be performed on this request or not"
+	HTTPRequestDoc.Fields[37].Name = "pre-condition-operator"
+	HTTPRequestDoc.Fields[37].Type = "string"
 	HTTPRequestDoc.Fields[37].Note = ""
-	HTTPRequestDoc.Fields[37].Description = "GlobalMatchers marks matchers as static and applies globally to all result events from other templates"
-	HTTPRequestDoc.Fields[37].Comments[encoder.LineComment] = "GlobalMatchers marks matchers as static and applies globally to all result events from other templates"
+	HTTPRequestDoc.Fields[37].Description = "FuzzPreConditionOperator is the operator between multiple PreConditions for fuzzing Default is OR"
+	HTTPRequestDoc.Fields[37].Comments[encoder.LineComment] = "FuzzPreConditionOperator is the operator between multiple PreConditions for fuzzing Default is OR"
+	HTTPRequestDoc.Fields[38].Name = "global-matchers"
+	HTTPRequestDoc.Fields[38].Type = "bool"
+	HTTPRequestDoc.Fields[38].Note = ""
+	HTTPRequestDoc.Fields[38].Description = "GlobalMatchers marks matchers as static and applies globally to all result events from other templates"
+	HTTPRequestDoc.Fields[38].Comments[encoder.LineComment] = "GlobalMatchers marks matchers as static and applies globally to all result events from other templates"
 
 	GENERATORSAttackTypeHolderDoc.Type = "generators.AttackTypeHolder"
 	GENERATORSAttackTypeHolderDoc.Comments[encoder.LineComment] = " AttackTypeHolder is used to hold internal type of the protocol"
@@ -721,6 +745,129 @@ func init() {
 		"clusterbomb",
 	}
 
+	GENERATORSPayloadSpecDoc.Type = "generators.PayloadSpec"
+	GENERATORSPayloadSpecDoc.Comments[encoder.LineComment] = " PayloadSpec is a definition generating the values of a payload"
+	GENERATORSPayloadSpecDoc.Description = "PayloadSpec is a definition generating the values of a payload\n instead of listing them or reading them from a file."
+	GENERATORSPayloadSpecDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "http.Request",
+			FieldName: "payloads",
+		},
+		{
+			TypeName:  "dns.Request",
+			FieldName: "payloads",
+		},
+		{
+			TypeName:  "network.Request",
+			FieldName: "payloads",
+		},
+		{
+			TypeName:  "headless.Request",
+			FieldName: "payloads",
+		},
+		{
+			TypeName:  "websocket.Request",
+			FieldName: "payloads",
+		},
+		{
+			TypeName:  "javascript.Request",
+			FieldName: "payloads",
+		},
+	}
+	GENERATORSPayloadSpecDoc.Fields = make([]encoder.Doc, 12)
+	GENERATORSPayloadSpecDoc.Fields[0].Name = "type"
+	GENERATORSPayloadSpecDoc.Fields[0].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[0].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[0].Description = "Type is the type of values generated.\n\nrange generates the numbers from one bound to another, date-range the dates\nbetween two bounds, charset all the combinations of a set of characters and\nmutate the mutations of a list of words. Without a type, the values or the\nfile of the definition are used as is."
+	GENERATORSPayloadSpecDoc.Fields[0].Comments[encoder.LineComment] = "Type is the type of values generated."
+	GENERATORSPayloadSpecDoc.Fields[0].Values = []string{
+		"range",
+		"date-range",
+		"charset",
+		"mutate",
+	}
+	GENERATORSPayloadSpecDoc.Fields[1].Name = "from"
+	GENERATORSPayloadSpecDoc.Fields[1].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[1].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[1].Description = "From is the first value of a range or date-range.\n\nDates are in 2006-01-02, 2006-01-02 15:04:05 or RFC3339 format."
+	GENERATORSPayloadSpecDoc.Fields[1].Comments[encoder.LineComment] = "From is the first value of a range or date-range."
+
+	GENERATORSPayloadSpecDoc.Fields[1].AddExample("", "1")
+	GENERATORSPayloadSpecDoc.Fields[1].AddExample("", "2024-01-01")
+	GENERATORSPayloadSpecDoc.Fields[2].Name = "to"
+	GENERATORSPayloadSpecDoc.Fields[2].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[2].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[2].Description = "To is the last value of a range or date-range."
+	GENERATORSPayloadSpecDoc.Fields[2].Comments[encoder.LineComment] = "To is the last value of a range or date-range."
+
+	GENERATORSPayloadSpecDoc.Fields[2].AddExample("", "100")
+	GENERATORSPayloadSpecDoc.Fields[2].AddExample("", "2024-12-31")
+	GENERATORSPayloadSpecDoc.Fields[3].Name = "step"
+	GENERATORSPayloadSpecDoc.Fields[3].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[3].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[3].Description = "Step is the increment between two values of a range or date-range.\n\nIt is a number for a range (default 1) and a duration for a date-range (default 24h)."
+	GENERATORSPayloadSpecDoc.Fields[3].Comments[encoder.LineComment] = "Step is the increment between two values of a range or date-range."
+
+	GENERATORSPayloadSpecDoc.Fields[3].AddExample("", "5")
+	GENERATORSPayloadSpecDoc.Fields[3].AddExample("", "168h")
+	GENERATORSPayloadSpecDoc.Fields[4].Name = "format"
+	GENERATORSPayloadSpecDoc.Fields[4].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[4].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[4].Description = "Format is the format of the generated values.\n\nIt is a printf format for a range (default %d) and a Go time layout for a date-range (default 2006-01-02)."
+	GENERATORSPayloadSpecDoc.Fields[4].Comments[encoder.LineComment] = "Format is the format of the generated values."
+
+	GENERATORSPayloadSpecDoc.Fields[4].AddExample("", "%04d")
+	GENERATORSPayloadSpecDoc.Fields[4].AddExample("", "20060102")
+	GENERATORSPayloadSpecDoc.Fields[5].Name = "charset"
+	GENERATORSPayloadSpecDoc.Fields[5].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[5].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[5].Description = "Charset is the set of characters combined by a charset payload."
+	GENERATORSPayloadSpecDoc.Fields[5].Comments[encoder.LineComment] = "Charset is the set of characters combined by a charset payload."
+
+	GENERATORSPayloadSpecDoc.Fields[5].AddExample("", "abcdef0123456789")
+	GENERATORSPayloadSpecDoc.Fields[6].Name = "min-length"
+	GENERATORSPayloadSpecDoc.Fields[6].Type = "int"
+	GENERATORSPayloadSpecDoc.Fields[6].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[6].Description = "MinLength is the minimum length of the combinations of a charset payload."
+	GENERATORSPayloadSpecDoc.Fields[6].Comments[encoder.LineComment] = "MinLength is the minimum length of the combinations of a charset payload."
+
+	GENERATORSPayloadSpecDoc.Fields[6].AddExample("", 1)
+	GENERATORSPayloadSpecDoc.Fields[7].Name = "max-length"
+	GENERATORSPayloadSpecDoc.Fields[7].Type = "int"
+	GENERATORSPayloadSpecDoc.Fields[7].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[7].Description = "MaxLength is the maximum length of the combinations of a charset payload.\n\nIt defaults to the minimum length."
+	GENERATORSPayloadSpecDoc.Fields[7].Comments[encoder.LineComment] = "MaxLength is the maximum length of the combinations of a charset payload."
+
+	GENERATORSPayloadSpecDoc.Fields[7].AddExample("", 3)
+	GENERATORSPayloadSpecDoc.Fields[8].Name = "values"
+	GENERATORSPayloadSpecDoc.Fields[8].Type = "[]string"
+	GENERATORSPayloadSpecDoc.Fields[8].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[8].Description = "Values are the words to use as is or to mutate."
+	GENERATORSPayloadSpecDoc.Fields[8].Comments[encoder.LineComment] = "Values are the words to use as is or to mutate."
+
+	GENERATORSPayloadSpecDoc.Fields[8].AddExample("", []string{"admin", "root"})
+	GENERATORSPayloadSpecDoc.Fields[9].Name = "file"
+	GENERATORSPayloadSpecDoc.Fields[9].Type = "string"
+	GENERATORSPayloadSpecDoc.Fields[9].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[9].Description = "File is a wordlist file read instead of values."
+	GENERATORSPayloadSpecDoc.Fields[9].Comments[encoder.LineComment] = "File is a wordlist file read instead of values."
+
+	GENERATORSPayloadSpecDoc.Fields[9].AddExample("", "helpers/wordlists/users.txt")
+	GENERATORSPayloadSpecDoc.Fields[10].Name = "rules"
+	GENERATORSPayloadSpecDoc.Fields[10].Type = "[]string"
+	GENERATORSPayloadSpecDoc.Fields[10].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[10].Description = "Rules are the mutations of a mutate payload. Each word is generated as is\nand once mutated by every rule.\n\nSupported rules are lower, upper, capitalize, toggle, reverse, leet,\nprefix:<value> and suffix:<value>."
+	GENERATORSPayloadSpecDoc.Fields[10].Comments[encoder.LineComment] = "Rules are the mutations of a mutate payload. Each word is generated as is"
+
+	GENERATORSPayloadSpecDoc.Fields[10].AddExample("", []string{"capitalize", "leet", "suffix:123"})
+	GENERATORSPayloadSpecDoc.Fields[11].Name = "encoders"
+	GENERATORSPayloadSpecDoc.Fields[11].Type = "[]string"
+	GENERATORSPayloadSpecDoc.Fields[11].Note = ""
+	GENERATORSPayloadSpecDoc.Fields[11].Description = "Encoders is a chain of encoders applied in order to every value.\n\nSupported encoders are url, double-url, base64, unicode and html."
+	GENERATORSPayloadSpecDoc.Fields[11].Comments[encoder.LineComment] = "Encoders is a chain of encoders applied in order to every value."
+
+	GENERATORSPayloadSpecDoc.Fields[11].AddExample("", []string{"base64", "url"})
+
 	HTTPMethodTypeHolderDoc.Type = "HTTPMethodTypeHolder"
 	HTTPMethodTypeHolderDoc.Comments[encoder.LineComment] = " HTTPMethodTypeHolder is used to hold internal type of the HTTP Method"
 	HTTPMethodTypeHolderDoc.Description = "HTTPMethodTypeHolder is used to hold internal type of the HTTP Method"
@@ -762,6 +909,10 @@ func init() {
 			TypeName:  "headless.Request",
 			FieldName: "fuzzing",
 		},
+		{
+			TypeName:  "websocket.Request",
+			FieldName: "fuzzing",
+		},
 	}
 	FUZZRuleDoc.Fields = make([]encoder.Doc, 9)
 	FUZZRuleDoc.Fields[0].Name = "type"
@@ -787,6 +938,7 @@ func init() {
 		"body",
 		"cookie",
 		"request",
+		"message",
 	}
 	FUZZRuleDoc.Fields[2].Name = "parts"
 	FUZZRuleDoc.Fields[2].Type = "[]string"
@@ -800,6 +952,7 @@ func init() {
 		"body",
 		"cookie",
 		"request",
+		"message",
 	}
 	FUZZRuleDoc.Fields[3].Name = "mode"
 	FUZZRuleDoc.Fields[3].Type = "string"
@@ -1134,7 +1287,7 @@ func init() {
 	DNSRequestDoc.Fields[2].Name = "type"
 	DNSRequestDoc.Fields[2].Type = "DNSRequestTypeHolder"
 	DNSRequestDoc.Fields[2].Note = ""
-	DNSRequestDoc.Fields[2].Description = "RequestType is the type of DNS request to make."
+	DNSRequestDoc.Fields[2].Description = "RequestType is the type of DNS request to make.\n\nAXFR and IXFR attempt a zone transfer from the resolvers of the\ntemplate or the nameservers of the zone, returning the full record set."
 	DNSRequestDoc.Fields[2].Comments[encoder.LineComment] = "RequestType is the type of DNS request to make."
 	DNSRequestDoc.Fields[3].Name = "class"
 	DNSRequestDoc.Fields[3].Type = "string"
@@ -1193,8 +1346,10 @@ func init() {
 	DNSRequestDoc.Fields[11].Name = "resolvers"
 	DNSRequestDoc.Fields[11].Type = "[]string"
 	DNSRequestDoc.Fields[11].Note = ""
-	DNSRequestDoc.Fields[11].Description = "Resolvers to use for the dns requests"
-	DNSRequestDoc.Fields[11].Comments[encoder.LineComment] = " Resolvers to use for the dns requests"
+	DNSRequestDoc.Fields[11].Description = "Resolvers to use for the dns requests.\n\nDNS-over-HTTPS and DNS-over-TLS resolvers are used with the\n`https://` and `tls://` schemes."
+	DNSRequestDoc.Fields[11].Comments[encoder.LineComment] = "Resolvers to use for the dns requests."
+
+	DNSRequestDoc.Fields[11].AddExample("", []string{"8.8.8.8:53", "https://dns.google/dns-query", "tls://1.1.1.1:853"})
 
 	DNSRequestTypeHolderDoc.Type = "DNSRequestTypeHolder"
 	DNSRequestTypeHolderDoc.Comments[encoder.LineComment] = " DNSRequestTypeHolder is used to hold internal type of the DNS type"
@@ -1225,6 +1380,8 @@ func init() {
 		"TLSA",
 		"ANY",
 		"SRV",
+		"AXFR",
+		"IXFR",
 	}
 
 	FILERequestDoc.Type = "file.Request"
@@ -1268,7 +1425,7 @@ func init() {
 			Value: "Raw contains the raw file contents",
 		},
 	}
-	FILERequestDoc.Fields = make([]encoder.Doc, 7)
+	FILERequestDoc.Fields = make([]encoder.Doc, 9)
 	FILERequestDoc.Fields[0].Name = "extensions"
 	FILERequestDoc.Fields[0].Type = "[]string"
 	FILERequestDoc.Fields[0].Note = ""
@@ -1300,16 +1457,30 @@ func init() {
 	FILERequestDoc.Fields[4].Note = ""
 	FILERequestDoc.Fields[4].Description = "elaborates archives"
 	FILERequestDoc.Fields[4].Comments[encoder.LineComment] = "elaborates archives"
-	FILERequestDoc.Fields[5].Name = "mime-type"
-	FILERequestDoc.Fields[5].Type = "bool"
+	FILERequestDoc.Fields[5].Name = "archive-depth"
+	FILERequestDoc.Fields[5].Type = "int"
 	FILERequestDoc.Fields[5].Note = ""
-	FILERequestDoc.Fields[5].Description = "enables mime types check"
-	FILERequestDoc.Fields[5].Comments[encoder.LineComment] = "enables mime types check"
-	FILERequestDoc.Fields[6].Name = "no-recursive"
-	FILERequestDoc.Fields[6].Type = "bool"
+	FILERequestDoc.Fields[5].Description = "ArchiveDepth is the maximum depth of nested archives to extract.\n\nArchives found inside archives (e.g. a jar inside a war inside a zip, or the\nlayer tarballs of a docker/OCI image tarball) are extracted recursively and\ntheir files are reported with their virtual path, e.g. outer.zip!/inner.jar!/config.properties.\nBy default, nuclei extracts up to 5 levels of archives."
+	FILERequestDoc.Fields[5].Comments[encoder.LineComment] = "ArchiveDepth is the maximum depth of nested archives to extract."
+
+	FILERequestDoc.Fields[5].AddExample("", 2)
+	FILERequestDoc.Fields[6].Name = "archive-max-size"
+	FILERequestDoc.Fields[6].Type = "string"
 	FILERequestDoc.Fields[6].Note = ""
-	FILERequestDoc.Fields[6].Description = "NoRecursive specifies whether to not do recursive checks if folders are provided."
-	FILERequestDoc.Fields[6].Comments[encoder.LineComment] = "NoRecursive specifies whether to not do recursive checks if folders are provided."
+	FILERequestDoc.Fields[6].Description = "ArchiveMaxSize is the maximum size of data to extract from an archive and the archives nested in it.\n\nBy default, nuclei will extract 1 GB of content from an archive.\nIf set to \"no\" then all content will be extracted"
+	FILERequestDoc.Fields[6].Comments[encoder.LineComment] = "ArchiveMaxSize is the maximum size of data to extract from an archive and the archives nested in it."
+
+	FILERequestDoc.Fields[6].AddExample("", "500Mb")
+	FILERequestDoc.Fields[7].Name = "mime-type"
+	FILERequestDoc.Fields[7].Type = "bool"
+	FILERequestDoc.Fields[7].Note = ""
+	FILERequestDoc.Fields[7].Description = "enables mime types check"
+	FILERequestDoc.Fields[7].Comments[encoder.LineComment] = "enables mime types check"
+	FILERequestDoc.Fields[8].Name = "no-recursive"
+	FILERequestDoc.Fields[8].Type = "bool"
+	FILERequestDoc.Fields[8].Note = ""
+	FILERequestDoc.Fields[8].Description = "NoRecursive specifies whether to not do recursive checks if folders are provided."
+	FILERequestDoc.Fields[8].Comments[encoder.LineComment] = "NoRecursive specifies whether to not do recursive checks if folders are provided."
 
 	NETWORKRequestDoc.Type = "network.Request"
 	NETWORKRequestDoc.Comments[encoder.LineComment] = " Request contains a Network protocol request to be made from a template"
@@ -1373,10 +1544,12 @@ func init() {
 	NETWORKRequestDoc.Fields[1].Name = "host"
 	NETWORKRequestDoc.Fields[1].Type = "[]string"
 	NETWORKRequestDoc.Fields[1].Note = ""
-	NETWORKRequestDoc.Fields[1].Description = "Host to send network requests to.\n\nUsually it's set to `{{Hostname}}`. If you want to enable TLS for\nTCP Connection, you can use `tls://{{Hostname}}`."
+	NETWORKRequestDoc.Fields[1].Description = "Host to send network requests to.\n\nUsually it's set to `{{Hostname}}`. If you want to enable TLS for\nTCP Connection, you can use `tls://{{Hostname}}`. UDP datagrams are\nsent with `udp://{{Hostname}}`, every input being sent as one datagram."
 	NETWORKRequestDoc.Fields[1].Comments[encoder.LineComment] = "Host to send network requests to."
 
 	NETWORKRequestDoc.Fields[1].AddExample("", []string{"{{Hostname}}"})
+
+	NETWORKRequestDoc.Fields[1].AddExample("", []string{"udp://{{Host}}:161"})
 	NETWORKRequestDoc.Fields[2].Name = "attack"
 	NETWORKRequestDoc.Fields[2].Type = "generators.AttackTypeHolder"
 	NETWORKRequestDoc.Fields[2].Note = ""
@@ -1804,8 +1977,44 @@ func init() {
 			Key:   "tls_version",
 			Value: "TLS version is the version of the TLS protocol used",
 		},
+		{
+			Key:   "jarm_hash",
+			Value: "JARM hash is the JARM fingerprint of the server (fingerprint)",
+		},
+		{
+			Key:   "ja3s_hash",
+			Value: "JA3S hash is the JA3S fingerprint of the server hello (fingerprint, ztls only)",
+		},
+		{
+			Key:   "ja4s",
+			Value: "JA4S is the JA4S fingerprint of the server hello (fingerprint)",
+		},
+		{
+			Key:   "chain_valid",
+			Value: "Chain valid is true if the certificate chain is trusted and valid for the host (validate_chain)",
+		},
+		{
+			Key:   "chain_error",
+			Value: "Chain error is the error of the certificate chain verification (validate_chain)",
+		},
+		{
+			Key:   "untrusted_root",
+			Value: "Untrusted root is true if the certificate chain does not lead to a trusted root (validate_chain)",
+		},
+		{
+			Key:   "hostname_mismatch",
+			Value: "Hostname mismatch is true if the certificate is not valid for the host (validate_chain)",
+		},
+		{
+			Key:   "weak_signature",
+			Value: "Weak signature is true if a certificate of the chain is signed with md5 or sha1 (validate_chain)",
+		},
+		{
+			Key:   "signature_algorithms",
+			Value: "Signature algorithms of the certificate chain starting at the leaf (validate_chain)",
+		},
 	}
-	SSLRequestDoc.Fields = make([]encoder.Doc, 9)
+	SSLRequestDoc.Fields = make([]encoder.Doc, 11)
 	SSLRequestDoc.Fields[0].Name = "id"
 	SSLRequestDoc.Fields[0].Type = "string"
 	SSLRequestDoc.Fields[0].Note = ""
@@ -1865,6 +2074,16 @@ func init() {
 	SSLRequestDoc.Fields[8].Note = ""
 	SSLRequestDoc.Fields[8].Description = "description: |\n  TLS Cipher types to enumerate\n values:\n   - \"insecure\" (default)\n   - \"weak\"\n   - \"secure\"\n   - \"all\""
 	SSLRequestDoc.Fields[8].Comments[encoder.LineComment] = " description: |"
+	SSLRequestDoc.Fields[9].Name = "fingerprint"
+	SSLRequestDoc.Fields[9].Type = "bool"
+	SSLRequestDoc.Fields[9].Note = ""
+	SSLRequestDoc.Fields[9].Description = "Fingerprint - false if not specified\nPerforms the probe handshakes to compute the JARM (jarm_hash)\nand JA4S (ja4s) fingerprints of the server"
+	SSLRequestDoc.Fields[9].Comments[encoder.LineComment] = "Fingerprint - false if not specified"
+	SSLRequestDoc.Fields[10].Name = "validate_chain"
+	SSLRequestDoc.Fields[10].Type = "bool"
+	SSLRequestDoc.Fields[10].Note = ""
+	SSLRequestDoc.Fields[10].Description = "Validate Chain - false if not specified\nValidates the certificate chain against the system roots, reporting\nuntrusted roots, hostname mismatches and weak signatures"
+	SSLRequestDoc.Fields[10].Comments[encoder.LineComment] = "Validate Chain - false if not specified"
 
 	WEBSOCKETRequestDoc.Type = "websocket.Request"
 	WEBSOCKETRequestDoc.Comments[encoder.LineComment] = " Request is a request for the Websocket protocol"
@@ -1892,6 +2111,10 @@ func init() {
 			Key:   "response",
 			Value: "Websocket response received from the server",
 		},
+		{
+			Key:   "subprotocol",
+			Value: "Subprotocol is the subprotocol selected by the server",
+		},
 		{
 			Key:   "host",
 			Value: "Host is the input to the template",
@@ -1901,7 +2124,7 @@ func init() {
 			Value: "Matched is the input which was matched upon",
 		},
 	}
-	WEBSOCKETRequestDoc.Fields = make([]encoder.Doc, 6)
+	WEBSOCKETRequestDoc.Fields = make([]encoder.Doc, 9)
 	WEBSOCKETRequestDoc.Fields[0].Name = "id"
 	WEBSOCKETRequestDoc.Fields[0].Type = "string"
 	WEBSOCKETRequestDoc.Fields[0].Note = ""
@@ -1922,16 +2145,37 @@ func init() {
 	WEBSOCKETRequestDoc.Fields[3].Note = ""
 	WEBSOCKETRequestDoc.Fields[3].Description = "Headers contains headers for the request."
 	WEBSOCKETRequestDoc.Fields[3].Comments[encoder.LineComment] = "Headers contains headers for the request."
-	WEBSOCKETRequestDoc.Fields[4].Name = "attack"
-	WEBSOCKETRequestDoc.Fields[4].Type = "generators.AttackTypeHolder"
+	WEBSOCKETRequestDoc.Fields[4].Name = "subprotocols"
+	WEBSOCKETRequestDoc.Fields[4].Type = "[]string"
 	WEBSOCKETRequestDoc.Fields[4].Note = ""
-	WEBSOCKETRequestDoc.Fields[4].Description = "Attack is the type of payload combinations to perform.\n\nSniper is each payload once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
-	WEBSOCKETRequestDoc.Fields[4].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
-	WEBSOCKETRequestDoc.Fields[5].Name = "payloads"
-	WEBSOCKETRequestDoc.Fields[5].Type = "map[string]interface{}"
+	WEBSOCKETRequestDoc.Fields[4].Description = "Subprotocols is the list of subprotocols to negotiate with the server.\n\nThe subprotocol selected by the server is available in the subprotocol variable."
+	WEBSOCKETRequestDoc.Fields[4].Comments[encoder.LineComment] = "Subprotocols is the list of subprotocols to negotiate with the server."
+
+	WEBSOCKETRequestDoc.Fields[4].AddExample("", []string{"graphql-transport-ws", "graphql-ws"})
+	WEBSOCKETRequestDoc.Fields[5].Name = "framing"
+	WEBSOCKETRequestDoc.Fields[5].Type = "string"
 	WEBSOCKETRequestDoc.Fields[5].Note = ""
-	WEBSOCKETRequestDoc.Fields[5].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
-	WEBSOCKETRequestDoc.Fields[5].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
+	WEBSOCKETRequestDoc.Fields[5].Description = "Framing is the message framing protocol to use for inputs and responses.\n\nsocketio performs the socket.io (engine.io v4) handshake, answers heartbeats\nand sends inputs as socket.io events. stomp connects to the STOMP broker and\nsends inputs as STOMP frames. Responses are matched without the framing."
+	WEBSOCKETRequestDoc.Fields[5].Comments[encoder.LineComment] = "Framing is the message framing protocol to use for inputs and responses."
+	WEBSOCKETRequestDoc.Fields[5].Values = []string{
+		"socketio",
+		"stomp",
+	}
+	WEBSOCKETRequestDoc.Fields[6].Name = "attack"
+	WEBSOCKETRequestDoc.Fields[6].Type = "generators.AttackTypeHolder"
+	WEBSOCKETRequestDoc.Fields[6].Note = ""
+	WEBSOCKETRequestDoc.Fields[6].Description = "Attack is the type of payload combinations to perform.\n\nSniper is each payload once, pitchfork combines multiple payload sets and clusterbomb generates\npermutations and combinations for all payloads."
+	WEBSOCKETRequestDoc.Fields[6].Comments[encoder.LineComment] = "Attack is the type of payload combinations to perform."
+	WEBSOCKETRequestDoc.Fields[7].Name = "payloads"
+	WEBSOCKETRequestDoc.Fields[7].Type = "map[string]interface{}"
+	WEBSOCKETRequestDoc.Fields[7].Note = ""
+	WEBSOCKETRequestDoc.Fields[7].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
+	WEBSOCKETRequestDoc.Fields[7].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
+	WEBSOCKETRequestDoc.Fields[8].Name = "fuzzing"
+	WEBSOCKETRequestDoc.Fields[8].Type = "[]fuzz.Rule"
+	WEBSOCKETRequestDoc.Fields[8].Note = ""
+	WEBSOCKETRequestDoc.Fields[8].Description = "Fuzzing describes rules to fuzz the fields of input messages.\n\nInput data is decoded with the fuzzing dataformats (json, xml) and\nthe matching fields are mutated, sending each mutation on a new connection."
+	WEBSOCKETRequestDoc.Fields[8].Comments[encoder.LineComment] = "Fuzzing describes rules to fuzz the fields of input messages."
 
 	WEBSOCKETInputDoc.Type = "websocket.Input"
 	WEBSOCKETInputDoc.Comments[encoder.LineComment] = ""
@@ -1942,7 +2186,7 @@ func init() {
 			FieldName: "inputs",
 		},
 	}
-	WEBSOCKETInputDoc.Fields = make([]encoder.Doc, 2)
+	WEBSOCKETInputDoc.Fields = make([]encoder.Doc, 8)
 	WEBSOCKETInputDoc.Fields[0].Name = "data"
 	WEBSOCKETInputDoc.Fields[0].Type = "string"
 	WEBSOCKETInputDoc.Fields[0].Note = ""
@@ -1959,6 +2203,50 @@ func init() {
 	WEBSOCKETInputDoc.Fields[1].Comments[encoder.LineComment] = "Name is the optional name of the data read to provide matching on."
 
 	WEBSOCKETInputDoc.Fields[1].AddExample("", "prefix")
+	WEBSOCKETInputDoc.Fields[2].Name = "type"
+	WEBSOCKETInputDoc.Fields[2].Type = "string"
+	WEBSOCKETInputDoc.Fields[2].Note = ""
+	WEBSOCKETInputDoc.Fields[2].Description = "Type is the type of frame to send the input as.\n\nping sends a ping frame and reads the pong, close sends a close frame.\nBy default, inputs are sent as text frames."
+	WEBSOCKETInputDoc.Fields[2].Comments[encoder.LineComment] = "Type is the type of frame to send the input as."
+	WEBSOCKETInputDoc.Fields[2].Values = []string{
+		"text",
+		"binary",
+		"ping",
+		"close",
+	}
+	WEBSOCKETInputDoc.Fields[3].Name = "event"
+	WEBSOCKETInputDoc.Fields[3].Type = "string"
+	WEBSOCKETInputDoc.Fields[3].Note = ""
+	WEBSOCKETInputDoc.Fields[3].Description = "Event is the socket.io event name to send the data with when using socketio framing."
+	WEBSOCKETInputDoc.Fields[3].Comments[encoder.LineComment] = "Event is the socket.io event name to send the data with when using socketio framing."
+
+	WEBSOCKETInputDoc.Fields[3].AddExample("", "message")
+	WEBSOCKETInputDoc.Fields[4].Name = "command"
+	WEBSOCKETInputDoc.Fields[4].Type = "string"
+	WEBSOCKETInputDoc.Fields[4].Note = ""
+	WEBSOCKETInputDoc.Fields[4].Description = "Command is the STOMP frame command when using stomp framing.\n\nBy default, inputs are sent as SEND frames."
+	WEBSOCKETInputDoc.Fields[4].Comments[encoder.LineComment] = "Command is the STOMP frame command when using stomp framing."
+
+	WEBSOCKETInputDoc.Fields[4].AddExample("", "SUBSCRIBE")
+	WEBSOCKETInputDoc.Fields[5].Name = "headers"
+	WEBSOCKETInputDoc.Fields[5].Type = "map[string]string"
+	WEBSOCKETInputDoc.Fields[5].Note = ""
+	WEBSOCKETInputDoc.Fields[5].Description = "Headers contains the STOMP frame headers when using stomp framing."
+	WEBSOCKETInputDoc.Fields[5].Comments[encoder.LineComment] = "Headers contains the STOMP frame headers when using stomp framing."
+	WEBSOCKETInputDoc.Fields[6].Name = "read"
+	WEBSOCKETInputDoc.Fields[6].Type = "int"
+	WEBSOCKETInputDoc.Fields[6].Note = ""
+	WEBSOCKETInputDoc.Fields[6].Description = "Read is the number of messages to read after sending the input.\n\nBy default, one message is read."
+	WEBSOCKETInputDoc.Fields[6].Comments[encoder.LineComment] = "Read is the number of messages to read after sending the input."
+
+	WEBSOCKETInputDoc.Fields[6].AddExample("", 2)
+	WEBSOCKETInputDoc.Fields[7].Name = "read-until"
+	WEBSOCKETInputDoc.Fields[7].Type = "string"
+	WEBSOCKETInputDoc.Fields[7].Note = ""
+	WEBSOCKETInputDoc.Fields[7].Description = "ReadUntil is a DSL condition, messages are read until it evaluates to true.\n\nThe last message read is available in the message variable and all\nthe messages read for the input in the messages variable."
+	WEBSOCKETInputDoc.Fields[7].Comments[encoder.LineComment] = "ReadUntil is a DSL condition, messages are read until it evaluates to true."
+
+	WEBSOCKETInputDoc.Fields[7].AddExample("", "contains(message, 'complete')")
 
 	WHOISRequestDoc.Type = "whois.Request"
 	WHOISRequestDoc.Comments[encoder.LineComment] = " Request is a request for the WHOIS protocol"
@@ -2117,6 +2405,50 @@ func init() {
 	JAVASCRIPTRequestDoc.Fields[8].Description = "Payloads contains any payloads for the current request.\n\nPayloads support both key-values combinations where a list\nof payloads is provided, or optionally a single file can also\nbe provided as payload which will be read on run-time."
 	JAVASCRIPTRequestDoc.Fields[8].Comments[encoder.LineComment] = "Payloads contains any payloads for the current request."
 
+	WORKFLOWSPivotDoc.Type = "workflows.Pivot"
+	WORKFLOWSPivotDoc.Comments[encoder.LineComment] = " Pivot spawns extracted values as new scan targets on which a set of templates is run."
+	WORKFLOWSPivotDoc.Description = "Pivot spawns extracted values as new scan targets on which a set of templates is run."
+	WORKFLOWSPivotDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Template",
+			FieldName: "pivot",
+		},
+	}
+	WORKFLOWSPivotDoc.Fields = make([]encoder.Doc, 5)
+	WORKFLOWSPivotDoc.Fields[0].Name = "extractors"
+	WORKFLOWSPivotDoc.Fields[0].Type = "stringslice.StringSlice"
+	WORKFLOWSPivotDoc.Fields[0].Note = ""
+	WORKFLOWSPivotDoc.Fields[0].Description = "Extractors are the names of the extracted values used as new targets.\n\nUsed by workflows, templates using flow pass targets with the pivot function."
+	WORKFLOWSPivotDoc.Fields[0].Comments[encoder.LineComment] = "Extractors are the names of the extracted values used as new targets."
+
+	WORKFLOWSPivotDoc.Fields[0].AddExample("", []string{"subdomains", "urls"})
+	WORKFLOWSPivotDoc.Fields[1].Name = "template"
+	WORKFLOWSPivotDoc.Fields[1].Type = "string"
+	WORKFLOWSPivotDoc.Fields[1].Note = ""
+	WORKFLOWSPivotDoc.Fields[1].Description = "Template is a single template or directory to execute on the new targets."
+	WORKFLOWSPivotDoc.Fields[1].Comments[encoder.LineComment] = "Template is a single template or directory to execute on the new targets."
+
+	WORKFLOWSPivotDoc.Fields[1].AddExample("", "http/technologies")
+	WORKFLOWSPivotDoc.Fields[2].Name = "tags"
+	WORKFLOWSPivotDoc.Fields[2].Type = "stringslice.StringSlice"
+	WORKFLOWSPivotDoc.Fields[2].Note = ""
+	WORKFLOWSPivotDoc.Fields[2].Description = "Tags to run templates on the new targets based on."
+	WORKFLOWSPivotDoc.Fields[2].Comments[encoder.LineComment] = "Tags to run templates on the new targets based on."
+	WORKFLOWSPivotDoc.Fields[3].Name = "scope"
+	WORKFLOWSPivotDoc.Fields[3].Type = "[]string"
+	WORKFLOWSPivotDoc.Fields[3].Note = ""
+	WORKFLOWSPivotDoc.Fields[3].Description = "Scope is a list of regexes one of which a new target must match.\n\nBy default, new targets must belong to the registrable domain of the input."
+	WORKFLOWSPivotDoc.Fields[3].Comments[encoder.LineComment] = "Scope is a list of regexes one of which a new target must match."
+
+	WORKFLOWSPivotDoc.Fields[3].AddExample("", []string{"(^|\\.)example\\.com$"})
+	WORKFLOWSPivotDoc.Fields[4].Name = "max-depth"
+	WORKFLOWSPivotDoc.Fields[4].Type = "int"
+	WORKFLOWSPivotDoc.Fields[4].Note = ""
+	WORKFLOWSPivotDoc.Fields[4].Description = "MaxDepth is the maximum number of pivots from the original input.\n\nTargets passed by the pivot templates are pivoted again until the depth is reached."
+	WORKFLOWSPivotDoc.Fields[4].Comments[encoder.LineComment] = "MaxDepth is the maximum number of pivots from the original input."
+
+	WORKFLOWSPivotDoc.Fields[4].AddExample("", 2)
+
 	HTTPSignatureTypeHolderDoc.Type = "http.SignatureTypeHolder"
 	HTTPSignatureTypeHolderDoc.Comments[encoder.LineComment] = " SignatureTypeHolder is used to hold internal type of the signature"
 	HTTPSignatureTypeHolderDoc.Description = "SignatureTypeHolder is used to hold internal type of the signature"
@@ -2154,6 +2486,7 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&MODELClassificationDoc,
 			&HTTPRequestDoc,
 			&GENERATORSAttackTypeHolderDoc,
+			&GENERATORSPayloadSpecDoc,
 			&HTTPMethodTypeHolderDoc,
 			&FUZZRuleDoc,
 			&SliceOrMapSliceDoc,
@@ -2177,6 +2510,7 @@ func GetTemplateDoc() *encoder.FileDoc {
 			&WHOISRequestDoc,
 			&CODERequestDoc,
 			&JAVASCRIPTRequestDoc,
+			&WORKFLOWSPivotDoc,
 			&HTTPSignatureTypeHolderDoc,
 			&VARIABLESVariableDoc,
 		},
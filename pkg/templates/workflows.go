@@ -34,6 +34,11 @@ func parseWorkflow(preprocessor Preprocessor, workflow *workflows.WorkflowTempla
 	if err := parseWorkflowTemplate(workflow, preprocessor, options, loader, shouldNotValidate); err != nil {
 		return err
 	}
+	if workflow.Pivot != nil {
+		if err := compilePivot(workflow.Pivot, preprocessor, options, loader); err != nil {
+			return errors.Wrap(err, "could not compile workflow pivot")
+		}
+	}
 	for _, subtemplates := range workflow.Subtemplates {
 		if err := parseWorkflow(preprocessor, subtemplates, options, loader); err != nil {
 			gologger.Warning().Msgf("Could not parse workflow: %v\n", err)
@@ -56,6 +61,29 @@ func parseWorkflow(preprocessor Preprocessor, workflow *workflows.WorkflowTempla
 	return nil
 }
 
+// compilePivot compiles a pivot creating executers for its templates
+func compilePivot(pivot *workflows.Pivot, preprocessor Preprocessor, options *protocols.ExecutorOptions, loader model.WorkflowLoader) error {
+	if err := pivot.Compile(); err != nil {
+		return err
+	}
+	// pivots of the pivot templates are not compiled since the targets
+	// passed by them are pivoted with this pivot, which also prevents
+	// recursive compilation of templates pivoting to themselves
+	pivotOptions := options.Copy()
+	pivotOptions.WorkflowLoader = nil
+	pivotOptions.DoNotCache = true
+
+	pivotTemplate := &workflows.WorkflowTemplate{Template: pivot.Template, Tags: pivot.Tags}
+	if err := parseWorkflowTemplate(pivotTemplate, preprocessor, &pivotOptions, loader, false); err != nil {
+		return err
+	}
+	if len(pivotTemplate.Executers) == 0 {
+		return errors.New("no templates found for pivot")
+	}
+	pivot.Executers = pivotTemplate.Executers
+	return nil
+}
+
 // parseWorkflowTemplate parses a workflow template creating an executer
 func parseWorkflowTemplate(workflow *workflows.WorkflowTemplate, preprocessor Preprocessor, options *protocols.ExecutorOptions, loader model.WorkflowLoader, noValidate bool) error {
 	var paths []string
@@ -17,6 +17,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/helpers/writer"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan/events"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec/flow"
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec/generic"
 	"github.com/projectdiscovery/nuclei/v3/pkg/tmplexec/multiproto"
@@ -191,6 +192,16 @@ func (e *TemplateExecuter) Execute(ctx *scan.ScanContext) (bool, error) {
 	}
 	var errx error
 
+	// trace the execution of the template on the target, requests
+	// made by the executors are traced as children of this span
+	if telemetry.TracingEnabled() {
+		spanCtx, span := telemetry.StartSpan(ctx.Context(), "template", telemetry.TemplateAttributes(e.options.TemplateID, e.getTemplateType(), ctx.Input.MetaInput.Input)...)
+		ctx.SetContext(spanCtx)
+		defer func() {
+			telemetry.EndSpan(span, errx)
+		}()
+	}
+
 	// Note: this is required for flow executor
 	// flow executer is tightly coupled with lot of executor options
 	// and map , wg and other types earlier we tried to use (compile once and run multiple times)
@@ -196,6 +196,7 @@ func (f *FlowExecutor) ExecuteWithResults(ctx *scan.ScanContext) error {
 	defer func() {
 		// remove set builtin
 		_ = runtime.GlobalObject().Delete("set")
+		_ = runtime.GlobalObject().Delete("pivot")
 		_ = runtime.GlobalObject().Delete("template")
 		for proto := range f.protoFunctions {
 			_ = runtime.GlobalObject().Delete(proto)
@@ -226,6 +227,15 @@ func (f *FlowExecutor) ExecuteWithResults(ctx *scan.ScanContext) error {
 	}); err != nil {
 		return err
 	}
+	// pivot passes values as new targets to the pivot of the template
+	if err := runtime.Set("pivot", func(call goja.FunctionCall) goja.Value {
+		for _, arg := range call.Arguments {
+			f.ctx.AddPivotTargets(pivotTargets(arg.Export())...)
+		}
+		return goja.Null()
+	}); err != nil {
+		return err
+	}
 	// also register functions that allow executing protocols from js
 	for proto, fn := range f.protoFunctions {
 		if err := runtime.Set(proto, fn); err != nil {
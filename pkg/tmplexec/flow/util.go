@@ -1,6 +1,9 @@
 package flow
 
-import "github.com/projectdiscovery/nuclei/v3/pkg/operators"
+import (
+	"github.com/projectdiscovery/nuclei/v3/pkg/operators"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+)
 
 // Checks if template has matchers
 func hasMatchers(all []*operators.Operators) bool {
@@ -38,3 +41,25 @@ func flatten(v interface{}) interface{} {
 		return v
 	}
 }
+
+// pivotTargets returns the targets passed to the pivot builtin
+// as a single value or an array of values
+func pivotTargets(v interface{}) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var targets []string
+		for _, item := range v {
+			targets = append(targets, pivotTargets(item)...)
+		}
+		return targets
+	case []string:
+		return v
+	default:
+		if target := types.ToString(v); target != "" {
+			return []string{target}
+		}
+		return nil
+	}
+}
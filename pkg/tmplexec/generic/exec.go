@@ -8,6 +8,7 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/output"
 	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
 	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
+	"github.com/projectdiscovery/nuclei/v3/pkg/telemetry"
 	mapsutil "github.com/projectdiscovery/utils/maps"
 )
 
@@ -58,6 +59,14 @@ func (g *Generic) ExecuteWithResults(ctx *scan.ScanContext) error {
 			}
 		}
 
+		// the input context is only replaced when the request is traced
+		endSpan := func(error) {}
+		if telemetry.TracingEnabled() {
+			reqCtx, span := telemetry.StartSpan(ctx.Context(), "request", telemetry.TemplateAttributes(g.options.TemplateID, req.Type().String(), inputItem.MetaInput.Input)...)
+			inputItem.SetContext(reqCtx)
+			endSpan = func(err error) { telemetry.EndSpan(span, err) }
+		}
+
 		err := req.ExecuteWithResults(inputItem, dynamicValues, output.InternalEvent(previous.GetAll()), func(event *output.InternalWrappedEvent) {
 			// this callback is not concurrent safe so mutex should be used to synchronize
 			if event == nil {
@@ -82,6 +91,7 @@ func (g *Generic) ExecuteWithResults(ctx *scan.ScanContext) error {
 			// for Execute : this callback will print the result to output
 			ctx.LogEvent(event)
 		})
+		endSpan(err)
 		if err != nil {
 			ctx.LogError(err)
 			if g.options.HostErrorsCache != nil {
@@ -0,0 +1,8 @@
+// Package tmpltest implements offline unit testing of nuclei templates.
+//
+// Test cases are declared in a sidecar file next to the template
+// (cves/example.yaml => cves/example.test.yml). Every test case
+// describes mocked responses for http, dns and network requests along
+// with the expected outcome. Cases are executed through the real protocol
+// executers against local stub servers, so no traffic leaves the host.
+package tmpltest
@@ -0,0 +1,223 @@
+package tmpltest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/pkg/errors"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
+	"github.com/projectdiscovery/nuclei/v3/pkg/input"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/contextargs"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/utils/excludematchers"
+	"github.com/projectdiscovery/nuclei/v3/pkg/scan"
+	"github.com/projectdiscovery/nuclei/v3/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/ratelimit"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// Result is the outcome of a single template test case
+type Result struct {
+	TemplateID   string        `json:"template-id"`
+	TemplatePath string        `json:"template-path"`
+	Name         string        `json:"name"`
+	Passed       bool          `json:"passed"`
+	Reason       string        `json:"reason,omitempty"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// String returns a human readable representation of the result
+func (r *Result) String() string {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+	message := fmt.Sprintf("[%s] %s / %s (%s)", status, r.TemplateID, r.Name, r.Duration.Round(time.Millisecond))
+	if r.Reason != "" {
+		message += ": " + r.Reason
+	}
+	return message
+}
+
+// Runner executes template test cases against local stub servers
+type Runner struct {
+	options *types.Options
+	catalog catalog.Catalog
+	parser  *templates.Parser
+}
+
+// New returns a new template test runner
+func New(options *types.Options, catalog catalog.Catalog) *Runner {
+	return &Runner{
+		options: options,
+		catalog: catalog,
+		parser:  templates.NewParser(),
+	}
+}
+
+// RunTemplate runs all test cases declared in the sidecar file of a template
+func (r *Runner) RunTemplate(templatePath string) ([]*Result, error) {
+	suite, err := LoadSuite(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	return r.RunSuite(templatePath, suite), nil
+}
+
+// RunSuite runs the given test cases against a template
+func (r *Runner) RunSuite(templatePath string, suite *Suite) []*Result {
+	results := make([]*Result, 0, len(suite.Tests))
+	for _, tc := range suite.Tests {
+		results = append(results, r.runCase(templatePath, tc))
+	}
+	return results
+}
+
+func (r *Runner) runCase(templatePath string, tc *TestCase) *Result {
+	start := time.Now()
+	result := &Result{TemplatePath: templatePath, Name: tc.Name}
+
+	events, templateID, err := r.execute(templatePath, tc)
+	result.TemplateID = templateID
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	if reason := tc.Expect.check(events); reason != "" {
+		result.Reason = reason
+		return result
+	}
+	result.Passed = true
+	return result
+}
+
+// execute compiles the template with a fresh set of executer options and
+// runs it against stubs serving the mocked responses of the test case.
+func (r *Runner) execute(templatePath string, tc *TestCase) ([]*output.ResultEvent, string, error) {
+	servers, err := startStubs(tc)
+	if err != nil {
+		return nil, "", err
+	}
+	defer servers.Close()
+
+	var mu sync.Mutex
+	var events []*output.ResultEvent
+	writer := testutils.NewMockOutputWriter(true)
+	writer.WriteCallback = func(event *output.ResultEvent) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	options := *r.options
+	options.NoInteractsh = true
+	options.MatcherStatus = false
+	options.StopAtFirstMatch = false
+	options.RestrictLocalNetworkAccess = false
+	options.DisableHTTPProbe = true
+
+	executerOpts := protocols.ExecutorOptions{
+		Output:          writer,
+		Options:         &options,
+		Progress:        &testutils.MockProgressClient{},
+		Catalog:         r.catalog,
+		RateLimiter:     ratelimit.NewUnlimited(ctx),
+		Colorizer:       aurora.NewAurora(false),
+		ResumeCfg:       types.NewResumeCfg(),
+		Parser:          r.parser,
+		InputHelper:     input.NewHelper(),
+		ExcludeMatchers: excludematchers.New(nil),
+		DoNotCache:      true,
+	}
+	defer executerOpts.RateLimiter.Stop()
+
+	template, err := templates.Parse(templatePath, nil, executerOpts)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not parse template")
+	}
+	if template == nil {
+		return nil, "", errors.New("template cannot be executed")
+	}
+	if len(template.Workflows) > 0 {
+		return nil, template.ID, errors.New("workflows are not supported")
+	}
+	if servers.dnsAddr != "" {
+		// point all dns requests of the template to the stub resolver
+		for _, request := range template.RequestsDNS {
+			request.Resolvers = []string{servers.dnsAddr}
+			if err := request.Compile(template.Options); err != nil {
+				return nil, template.ID, errors.Wrap(err, "could not compile dns request")
+			}
+		}
+	}
+
+	target := tc.Target
+	if target == "" {
+		if target, err = servers.target(template.Protocols()); err != nil {
+			return nil, template.ID, err
+		}
+	}
+	scanCtx := scan.NewScanContext(ctx, contextargs.NewWithInput(ctx, target))
+
+	_, execErr := template.Executer.Execute(scanCtx)
+	if execErr == nil {
+		// errors of requests are logged to the scan context without being returned
+		execErr = scanCtx.GenerateErrorMessage()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// errors fail the test case whatever the expectation is, a broken
+	// template must not pass a case expecting no match
+	if execErr != nil {
+		return nil, template.ID, errors.Wrap(execErr, "could not execute template")
+	}
+	return events, template.ID, nil
+}
+
+// check returns the reason the results do not satisfy the expectation
+// or an empty string if they do.
+func (e *Expectation) check(events []*output.ResultEvent) string {
+	matched := len(events) > 0
+	if matched != e.Matched {
+		if e.Matched {
+			return "expected a match but template did not match"
+		}
+		return fmt.Sprintf("expected no match but template produced %d result(s)", len(events))
+	}
+
+	var matcherNames, extracted []string
+	for _, event := range events {
+		if event.MatcherName != "" {
+			matcherNames = append(matcherNames, event.MatcherName)
+		}
+		extracted = append(extracted, event.ExtractedResults...)
+	}
+	var missing []string
+	for _, name := range e.MatcherNames {
+		if !sliceutil.Contains(matcherNames, name) {
+			missing = append(missing, "matcher:"+name)
+		}
+	}
+	for _, value := range e.Extracted {
+		if !sliceutil.Contains(extracted, value) {
+			missing = append(missing, "extracted:"+value)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("missing expected results: %s", strings.Join(missing, ", "))
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+package tmpltest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog/disk"
+	"github.com/projectdiscovery/nuclei/v3/pkg/testutils"
+)
+
+const httpTemplate = `id: tmpltest-http
+
+info:
+  name: tmpltest http
+  author: pdteam
+  severity: info
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}/version"
+
+    matchers:
+      - type: word
+        name: nginx
+        part: header
+        words:
+          - "nginx"
+
+    extractors:
+      - type: regex
+        group: 1
+        regex:
+          - "version: ([0-9.]+)"
+`
+
+const httpTestCases = `tests:
+  - name: vulnerable
+    http:
+      - path: /version
+        headers:
+          Server: nginx
+        body: "version: 1.2.3"
+    expect:
+      matched: true
+      matcher-names:
+        - nginx
+      extracted:
+        - 1.2.3
+
+  - name: not-vulnerable
+    http:
+      - status: 404
+        headers:
+          Server: apache
+    expect:
+      matched: false
+`
+
+const dnsTemplate = `id: tmpltest-dns
+
+info:
+  name: tmpltest dns
+  author: pdteam
+  severity: info
+
+dns:
+  - name: "{{FQDN}}"
+    type: CNAME
+
+    matchers:
+      - type: word
+        words:
+          - "herokudns.com"
+`
+
+const dnsTestCases = `tests:
+  - name: dangling-cname
+    target: app.example.com
+    dns:
+      - answer:
+          - "@ 300 IN CNAME app.herokudns.com."
+    expect:
+      matched: true
+`
+
+func writeTemplate(t *testing.T, dir, name, template, cases string) string {
+	templatePath := filepath.Join(dir, name+".yaml")
+	require.Nil(t, os.WriteFile(templatePath, []byte(template), 0644))
+	require.Nil(t, os.WriteFile(SidecarPath(templatePath), []byte(cases), 0644))
+	return templatePath
+}
+
+func TestRunTemplate(t *testing.T) {
+	options := testutils.DefaultOptions
+	testutils.Init(options)
+
+	dir := t.TempDir()
+	runner := New(options, disk.NewCatalog(dir))
+
+	t.Run("http", func(t *testing.T) {
+		results, err := runner.RunTemplate(writeTemplate(t, dir, "http", httpTemplate, httpTestCases))
+		require.Nil(t, err, "could not run template tests")
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.True(t, result.Passed, result.String())
+			require.Equal(t, "tmpltest-http", result.TemplateID)
+		}
+	})
+
+	t.Run("dns", func(t *testing.T) {
+		results, err := runner.RunTemplate(writeTemplate(t, dir, "dns", dnsTemplate, dnsTestCases))
+		require.Nil(t, err, "could not run template tests")
+		require.Len(t, results, 1)
+		require.True(t, results[0].Passed, results[0].String())
+	})
+
+	t.Run("failing-expectation", func(t *testing.T) {
+		cases := `tests:
+  - name: wrong-expectation
+    http:
+      - headers:
+          Server: nginx
+    expect:
+      matched: false
+`
+		results, err := runner.RunTemplate(writeTemplate(t, dir, "failing", httpTemplate, cases))
+		require.Nil(t, err, "could not run template tests")
+		require.Len(t, results, 1)
+		require.False(t, results[0].Passed)
+		require.Contains(t, results[0].Reason, "expected no match")
+	})
+
+	t.Run("execution-error", func(t *testing.T) {
+		template := `id: tmpltest-broken
+
+info:
+  name: tmpltest broken
+  author: pdteam
+  severity: info
+
+http:
+  - method: GET
+    path:
+      - "{{BaseURL}}"
+
+    matchers:
+      - type: word
+        words:
+          - "nginx"
+`
+		cases := `tests:
+  - name: not-vulnerable
+    target: http://127.0.0.1:1
+    http:
+      - status: 404
+    expect:
+      matched: false
+`
+		results, err := runner.RunTemplate(writeTemplate(t, dir, "broken", template, cases))
+		require.Nil(t, err, "could not run template tests")
+		require.Len(t, results, 1)
+		require.False(t, results[0].Passed, "failing execution passed negative test case")
+		require.Contains(t, results[0].Reason, "could not execute template")
+	})
+
+	t.Run("unstubbed-protocol", func(t *testing.T) {
+		template := `id: tmpltest-ssl
+
+info:
+  name: tmpltest ssl
+  author: pdteam
+  severity: info
+
+ssl:
+  - address: "{{Host}}:{{Port}}"
+
+    matchers:
+      - type: dsl
+        dsl:
+          - "expired == true"
+`
+		cases := `tests:
+  - name: expired
+    http:
+      - status: 200
+    expect:
+      matched: true
+`
+		results, err := runner.RunTemplate(writeTemplate(t, dir, "ssl", template, cases))
+		require.Nil(t, err, "could not run template tests")
+		require.Len(t, results, 1)
+		require.False(t, results[0].Passed, "ran template without stub against default target")
+		require.Contains(t, results[0].Reason, "a target is required")
+	})
+
+	t.Run("missing-sidecar", func(t *testing.T) {
+		templatePath := filepath.Join(dir, "missing.yaml")
+		require.Nil(t, os.WriteFile(templatePath, []byte(httpTemplate), 0644))
+		_, err := runner.RunTemplate(templatePath)
+		require.ErrorIs(t, err, ErrNoTestCases)
+	})
+}
+
+func TestParseSuite(t *testing.T) {
+	_, err := ParseSuite([]byte("tests:\n  - name: no-mocks\n    expect:\n      matched: true\n"))
+	require.NotNil(t, err, "could parse test case without mocked responses")
+
+	_, err = ParseSuite([]byte("tests: []\n"))
+	require.ErrorIs(t, err, ErrNoTestCases)
+
+	suite, err := ParseSuite([]byte(httpTestCases))
+	require.Nil(t, err)
+	require.Len(t, suite.Tests, 2)
+	require.Equal(t, "/version", suite.Tests[0].HTTP[0].Path)
+}
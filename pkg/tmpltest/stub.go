@@ -0,0 +1,270 @@
+package tmpltest
+
+import (
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	templateTypes "github.com/projectdiscovery/nuclei/v3/pkg/templates/types"
+)
+
+// stubs holds the local servers started for a single test case
+type stubs struct {
+	http    *http.Server
+	httpURL string
+
+	dns     *dns.Server
+	dnsAddr string
+
+	network     net.Listener
+	networkAddr string
+}
+
+// startStubs starts a stub server for every protocol mocked by the test case
+func startStubs(tc *TestCase) (*stubs, error) {
+	s := &stubs{}
+	if len(tc.HTTP) > 0 {
+		if err := s.startHTTP(tc.HTTP); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	if len(tc.DNS) > 0 {
+		if err := s.startDNS(tc.DNS); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	if len(tc.Network) > 0 {
+		if err := s.startNetwork(tc.Network); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Close stops all the started stub servers
+func (s *stubs) Close() {
+	if s.http != nil {
+		_ = s.http.Close()
+	}
+	if s.dns != nil {
+		_ = s.dns.Shutdown()
+	}
+	if s.network != nil {
+		_ = s.network.Close()
+	}
+}
+
+// target returns the default input for the test case of a template.
+//
+// Requests of protocols without a stub server would be sent to the
+// internet, so test cases of such templates need an explicit target.
+func (s *stubs) target(protocols templateTypes.ProtocolTypes) (string, error) {
+	for _, protocol := range protocols {
+		switch {
+		case protocol == templateTypes.HTTPProtocol && s.httpURL != "":
+		case protocol == templateTypes.NetworkProtocol && s.networkAddr != "":
+		case protocol == templateTypes.DNSProtocol && s.dnsAddr != "":
+		default:
+			return "", errors.Errorf("no stub for %s requests, a target is required", protocol)
+		}
+	}
+	switch {
+	case s.httpURL != "":
+		return s.httpURL, nil
+	case s.networkAddr != "":
+		return s.networkAddr, nil
+	default:
+		// dns queries are answered by the stub resolver whatever the name is
+		return "example.com", nil
+	}
+}
+
+// sequence returns items in order, repeating the last one once exhausted
+type sequence[T any] struct {
+	mu    sync.Mutex
+	items []T
+	next  int
+}
+
+func (s *sequence[T]) Next() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.items[s.next]
+	if s.next < len(s.items)-1 {
+		s.next++
+	}
+	return item
+}
+
+func (s *stubs) startHTTP(responses []*HTTPResponse) error {
+	var ordered []*HTTPResponse
+	var routed []*HTTPResponse
+	for _, resp := range responses {
+		if resp.Path != "" {
+			routed = append(routed, resp)
+		} else {
+			ordered = append(ordered, resp)
+		}
+	}
+	seq := &sequence[*HTTPResponse]{items: ordered}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp *HTTPResponse
+		for _, item := range routed {
+			if item.Path == r.URL.Path && (item.Method == "" || strings.EqualFold(item.Method, r.Method)) {
+				resp = item
+				break
+			}
+		}
+		if resp == nil && len(ordered) > 0 {
+			resp = seq.Next()
+		}
+		if resp == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for k, v := range resp.Headers {
+			w.Header().Set(k, v)
+		}
+		status := resp.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resp.Body))
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "could not start http stub")
+	}
+	s.http = &http.Server{Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	s.httpURL = "http://" + listener.Addr().String()
+	go func() {
+		_ = s.http.Serve(listener)
+	}()
+	return nil
+}
+
+func (s *stubs) startDNS(responses []*DNSResponse) error {
+	seq := &sequence[*DNSResponse]{items: responses}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		resp := seq.Next()
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		if resp.Rcode != "" {
+			if rcode, ok := dns.StringToRcode[strings.ToUpper(resp.Rcode)]; ok {
+				msg.Rcode = rcode
+			}
+		}
+		var question string
+		if len(r.Question) > 0 {
+			question = r.Question[0].Name
+		}
+		msg.Answer = parseRecords(resp.Answer, question)
+		msg.Ns = parseRecords(resp.Ns, question)
+		msg.Extra = parseRecords(resp.Extra, question)
+		_ = w.WriteMsg(msg)
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "could not start dns stub")
+	}
+	started := make(chan struct{})
+	s.dns = &dns.Server{PacketConn: conn, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	s.dnsAddr = conn.LocalAddr().String()
+	go func() {
+		_ = s.dns.ActivateAndServe()
+	}()
+	<-started
+	return nil
+}
+
+// parseRecords parses zone file formatted records replacing @ owner
+// names with the question name.
+func parseRecords(records []string, question string) []dns.RR {
+	var rrs []dns.RR
+	for _, record := range records {
+		record = strings.TrimSpace(record)
+		if strings.HasPrefix(record, "@") && question != "" {
+			record = question + record[1:]
+		}
+		rr, err := dns.NewRR(record)
+		if err != nil || rr == nil {
+			continue
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs
+}
+
+func (s *stubs) startNetwork(responses []*NetworkResponse) error {
+	payloads := make([][]byte, 0, len(responses))
+	for _, resp := range responses {
+		data := []byte(resp.Data)
+		if resp.Hex != "" {
+			decoded, err := hex.DecodeString(resp.Hex)
+			if err != nil {
+				return errors.Wrap(err, "could not decode network response")
+			}
+			data = decoded
+		}
+		payloads = append(payloads, data)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "could not start network stub")
+	}
+	s.network = listener
+	s.networkAddr = listener.Addr().String()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveNetworkConn(conn, responses, payloads)
+		}
+	}()
+	return nil
+}
+
+// serveNetworkConn writes the mocked responses in order, waiting for
+// client data before each response unless it is marked as banner.
+func serveNetworkConn(conn net.Conn, responses []*NetworkResponse, payloads [][]byte) {
+	defer conn.Close()
+
+	buffer := make([]byte, 4096)
+	for i, resp := range responses {
+		if !resp.Banner {
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if _, err := conn.Read(buffer); err != nil {
+				return
+			}
+		}
+		if _, err := conn.Write(payloads[i]); err != nil {
+			return
+		}
+	}
+	// keep the connection open until the client is done reading
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, err := conn.Read(buffer); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package tmpltest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// SidecarExtension is the extension of the file containing test cases for a template
+const SidecarExtension = ".test.yml"
+
+// ErrNoTestCases is returned when a template does not have any test cases
+var ErrNoTestCases = errors.New("no test cases found for template")
+
+// Suite is a list of test cases declared for a single template
+type Suite struct {
+	// Tests is the list of test cases of the template
+	Tests []*TestCase `yaml:"tests" json:"tests"`
+}
+
+// TestCase is a single offline test case for a template
+type TestCase struct {
+	// Name is the name of the test case
+	Name string `yaml:"name" json:"name"`
+	// Target optionally overrides the input passed to the template.
+	//
+	// By default the address of the http or network stub server is used,
+	// or example.com if only dns requests are mocked. It is required if
+	// the template has requests of a protocol without mocked responses.
+	Target string `yaml:"target,omitempty" json:"target,omitempty"`
+	// HTTP contains the mocked responses for http requests
+	HTTP []*HTTPResponse `yaml:"http,omitempty" json:"http,omitempty"`
+	// DNS contains the mocked responses for dns requests
+	DNS []*DNSResponse `yaml:"dns,omitempty" json:"dns,omitempty"`
+	// Network contains the mocked responses for network requests
+	Network []*NetworkResponse `yaml:"network,omitempty" json:"network,omitempty"`
+	// Expect is the expected outcome of the test case
+	Expect Expectation `yaml:"expect" json:"expect"`
+}
+
+// HTTPResponse is a mocked http response.
+//
+// Responses without a path are served in order for every request
+// received by the stub, repeating the last one once exhausted.
+// Responses with a path are only served for requests to that path.
+type HTTPResponse struct {
+	// Path restricts the response to requests for the given path
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// Method restricts the response to requests with the given method
+	Method string `yaml:"method,omitempty" json:"method,omitempty"`
+	// Status is the status code of the response (default 200)
+	Status int `yaml:"status,omitempty" json:"status,omitempty"`
+	// Headers are the headers of the response
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// Body is the body of the response
+	Body string `yaml:"body,omitempty" json:"body,omitempty"`
+}
+
+// DNSResponse is a mocked dns response
+type DNSResponse struct {
+	// Rcode is the response code (default NOERROR)
+	Rcode string `yaml:"rcode,omitempty" json:"rcode,omitempty"`
+	// Answer contains records for the answer section in zone file format.
+	// An owner name of @ is replaced with the question name.
+	Answer []string `yaml:"answer,omitempty" json:"answer,omitempty"`
+	// Ns contains records for the authority section
+	Ns []string `yaml:"ns,omitempty" json:"ns,omitempty"`
+	// Extra contains records for the additional section
+	Extra []string `yaml:"extra,omitempty" json:"extra,omitempty"`
+}
+
+// NetworkResponse is a mocked response for a tcp connection
+type NetworkResponse struct {
+	// Data is the data written to the connection
+	Data string `yaml:"data,omitempty" json:"data,omitempty"`
+	// Hex is hex encoded data written to the connection
+	Hex string `yaml:"hex,omitempty" json:"hex,omitempty"`
+	// Banner writes the response as soon as the connection is accepted
+	// instead of waiting for data from the client
+	Banner bool `yaml:"banner,omitempty" json:"banner,omitempty"`
+}
+
+// Expectation is the expected outcome of a test case
+type Expectation struct {
+	// Matched is true if the template is expected to produce results
+	Matched bool `yaml:"matched" json:"matched"`
+	// MatcherNames contains matcher names expected in the results
+	MatcherNames []string `yaml:"matcher-names,omitempty" json:"matcher-names,omitempty"`
+	// Extracted contains values expected in the extracted results
+	Extracted []string `yaml:"extracted,omitempty" json:"extracted,omitempty"`
+}
+
+// SidecarPath returns the path of the test case file for a template
+func SidecarPath(templatePath string) string {
+	return strings.TrimSuffix(templatePath, filepath.Ext(templatePath)) + SidecarExtension
+}
+
+// LoadSuite loads the test cases for a template from its sidecar file
+func LoadSuite(templatePath string) (*Suite, error) {
+	data, err := os.ReadFile(SidecarPath(templatePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoTestCases
+		}
+		return nil, err
+	}
+	return ParseSuite(data)
+}
+
+// ParseSuite parses test cases from raw yaml data
+func ParseSuite(data []byte) (*Suite, error) {
+	suite := &Suite{}
+	if err := yaml.UnmarshalStrict(data, suite); err != nil {
+		return nil, errors.Wrap(err, "could not parse test cases")
+	}
+	if len(suite.Tests) == 0 {
+		return nil, ErrNoTestCases
+	}
+	for i, tc := range suite.Tests {
+		if tc.Name == "" {
+			return nil, errors.Errorf("test case %d is missing a name", i)
+		}
+		if len(tc.HTTP) == 0 && len(tc.DNS) == 0 && len(tc.Network) == 0 {
+			return nil, errors.Errorf("test case %q does not mock any response", tc.Name)
+		}
+	}
+	return suite, nil
+}
@@ -0,0 +1,225 @@
+// Package traffic implements recording of protocol exchanges into an
+// archive and their deterministic replay without any network access.
+package traffic
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotFound is returned when no exchange was recorded for a request
+	ErrNotFound = errors.New("no recorded exchange found in traffic archive")
+
+	regexUserAgent       = regexp.MustCompile(`(?mi)\r\nUser-Agent: .+\r\n`)
+	regexDefaultInteract = regexp.MustCompile(`(?mi)[a-zA-Z1-9%.]+interact.sh`)
+)
+
+// Mode is the mode of operation of a traffic archive
+type Mode uint8
+
+const (
+	// ModeRecord appends every exchange to the archive
+	ModeRecord Mode = iota + 1
+	// ModeReplay serves exchanges from the archive
+	ModeReplay
+)
+
+// Key identifies the template and target an exchange belongs to
+type Key struct {
+	Protocol   string
+	TemplateID string
+	Target     string
+}
+
+func (k Key) String() string {
+	return k.Protocol + "|" + k.TemplateID + "|" + k.Target
+}
+
+// Exchange is a single recorded request/response pair
+type Exchange struct {
+	Protocol    string    `json:"protocol"`
+	TemplateID  string    `json:"template-id,omitempty"`
+	Target      string    `json:"target,omitempty"`
+	Fingerprint string    `json:"fingerprint"`
+	Request     []byte    `json:"request,omitempty"`
+	Response    []byte    `json:"response,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Archive is a jsonl file of recorded exchanges
+type Archive struct {
+	mode Mode
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+
+	// exchanges is the list of replayable exchanges per key in recording order
+	exchanges map[string][]*Exchange
+	consumed  map[*Exchange]struct{}
+}
+
+// Create creates a new archive at path to record exchanges into
+func Create(path string) (*Archive, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create traffic archive")
+	}
+	return &Archive{mode: ModeRecord, file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// Open opens a previously recorded archive for replay
+func Open(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open traffic archive")
+	}
+	defer file.Close()
+
+	archive := &Archive{
+		mode:      ModeReplay,
+		exchanges: make(map[string][]*Exchange),
+		consumed:  make(map[*Exchange]struct{}),
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 128*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		exchange := &Exchange{}
+		if err := jsoniter.Unmarshal(scanner.Bytes(), exchange); err != nil {
+			return nil, errors.Wrapf(err, "could not parse traffic archive line %d", line)
+		}
+		key := Key{Protocol: exchange.Protocol, TemplateID: exchange.TemplateID, Target: exchange.Target}.String()
+		archive.exchanges[key] = append(archive.exchanges[key], exchange)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read traffic archive")
+	}
+	return archive, nil
+}
+
+// Recording returns true if exchanges should be recorded into the archive
+func (a *Archive) Recording() bool {
+	return a != nil && a.mode == ModeRecord
+}
+
+// Replaying returns true if exchanges should be served from the archive
+func (a *Archive) Replaying() bool {
+	return a != nil && a.mode == ModeReplay
+}
+
+// Record appends an exchange for the key to the archive. A non-nil
+// err is recorded so that the failure can be reproduced on replay.
+func (a *Archive) Record(key Key, request, response []byte, err error) error {
+	exchange := &Exchange{
+		Protocol:    key.Protocol,
+		TemplateID:  key.TemplateID,
+		Target:      key.Target,
+		Fingerprint: Fingerprint(request),
+		Request:     request,
+		Response:    response,
+		Timestamp:   time.Now(),
+	}
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+	data, marshalErr := jsoniter.Marshal(exchange)
+	if marshalErr != nil {
+		return errors.Wrap(marshalErr, "could not marshal exchange")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.writer.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "could not write to traffic archive")
+	}
+	return nil
+}
+
+// Replay returns the recorded exchange for a request sent with the key.
+//
+// Exchanges with the same fingerprint are preferred and served in
+// recording order, repeating the last one once all were served. If the
+// request was not recorded (e.g. it contains random values) the next
+// unserved exchange of the key is returned. A nil request always
+// returns the next unserved exchange.
+func (a *Archive) Replay(key Key, request []byte) (*Exchange, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	exchanges := a.exchanges[key.String()]
+	if request != nil {
+		fingerprint := Fingerprint(request)
+		var last *Exchange
+		for _, exchange := range exchanges {
+			if exchange.Fingerprint != fingerprint {
+				continue
+			}
+			last = exchange
+			if _, ok := a.consumed[exchange]; !ok {
+				a.consumed[exchange] = struct{}{}
+				return exchange, nil
+			}
+		}
+		if last != nil {
+			return last, nil
+		}
+	}
+	for _, exchange := range exchanges {
+		if _, ok := a.consumed[exchange]; !ok {
+			a.consumed[exchange] = struct{}{}
+			return exchange, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Close flushes and closes the archive
+func (a *Archive) Close() error {
+	if a == nil || a.file == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.writer.Flush(); err != nil {
+		_ = a.file.Close()
+		return errors.Wrap(err, "could not flush traffic archive")
+	}
+	return a.file.Close()
+}
+
+// Fingerprint returns the fingerprint of a request. Values that change
+// between scans (user agent, interactsh urls) are ignored.
+func Fingerprint(request []byte) string {
+	if request == nil {
+		return ""
+	}
+	normalized := regexUserAgent.ReplaceAll(request, []byte("\r\n"))
+	normalized = regexDefaultInteract.ReplaceAll(normalized, []byte{})
+
+	hash := sha256.Sum256(normalized)
+	return hex.EncodeToString(hash[:])
+}
+
+// Err returns the recorded error of the exchange if any
+func (e *Exchange) Err() error {
+	if e.Error == "" {
+		return nil
+	}
+	return errors.New(e.Error)
+}
@@ -0,0 +1,168 @@
+package traffic
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+
+	archive, err := Create(path)
+	require.Nil(t, err, "could not create archive")
+	require.True(t, archive.Recording())
+
+	key := Key{Protocol: "http", TemplateID: "test", Target: "https://example.com"}
+	first := []byte("GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: first\r\n\r\n")
+	second := []byte("GET /admin HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	require.Nil(t, archive.Record(key, first, []byte("index"), nil))
+	require.Nil(t, archive.Record(key, second, nil, errors.New("connection refused")))
+	require.Nil(t, archive.Close())
+
+	archive, err = Open(path)
+	require.Nil(t, err, "could not open archive")
+	require.True(t, archive.Replaying())
+
+	// user agent is ignored while matching requests
+	exchange, err := archive.Replay(key, []byte("GET / HTTP/1.1\r\nHost: example.com\r\nUser-Agent: second\r\n\r\n"))
+	require.Nil(t, err)
+	require.Equal(t, "index", string(exchange.Response))
+
+	// same request is replayed again once served
+	exchange, err = archive.Replay(key, first)
+	require.Nil(t, err)
+	require.Equal(t, "index", string(exchange.Response))
+
+	exchange, err = archive.Replay(key, second)
+	require.Nil(t, err)
+	require.EqualError(t, exchange.Err(), "connection refused")
+
+	_, err = archive.Replay(Key{Protocol: "http", TemplateID: "other", Target: "https://example.com"}, first)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestArchiveReplayUnknownRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	archive, err := Create(path)
+	require.Nil(t, err)
+
+	key := Key{Protocol: "http", TemplateID: "test", Target: "example.com"}
+	require.Nil(t, archive.Record(key, []byte("GET /a1b2c3"), []byte("first"), nil))
+	require.Nil(t, archive.Record(key, []byte("GET /d4e5f6"), []byte("second"), nil))
+	require.Nil(t, archive.Close())
+
+	archive, err = Open(path)
+	require.Nil(t, err)
+
+	// requests with random values are served in recording order
+	exchange, err := archive.Replay(key, []byte("GET /zzzzzz"))
+	require.Nil(t, err)
+	require.Equal(t, "first", string(exchange.Response))
+	exchange, err = archive.Replay(key, []byte("GET /yyyyyy"))
+	require.Nil(t, err)
+	require.Equal(t, "second", string(exchange.Response))
+	_, err = archive.Replay(key, []byte("GET /xxxxxx"))
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestConnRecordReplay(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("banner\n"))
+		buffer := make([]byte, 16)
+		n, _ := conn.Read(buffer)
+		_, _ = conn.Write(append([]byte("echo "), buffer[:n]...))
+	}()
+
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	archive, err := Create(path)
+	require.Nil(t, err)
+
+	key := Key{Protocol: "tcp", TemplateID: "test", Target: listener.Addr().String()}
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.Nil(t, err)
+	conn = NewRecordingConn(conn, archive, key)
+	recorded := readConversation(t, conn)
+	require.Nil(t, conn.Close())
+	require.Nil(t, archive.Close())
+
+	archive, err = Open(path)
+	require.Nil(t, err)
+	exchange, err := archive.Replay(key, nil)
+	require.Nil(t, err)
+	conn, err = NewReplayConn(exchange)
+	require.Nil(t, err)
+	require.Equal(t, recorded, readConversation(t, conn))
+}
+
+func readConversation(t *testing.T, conn net.Conn) string {
+	buffer := make([]byte, 7)
+	_, err := io.ReadFull(conn, buffer)
+	require.Nil(t, err)
+	_, err = conn.Write([]byte("ping"))
+	require.Nil(t, err)
+	rest, _ := io.ReadAll(conn)
+	return string(buffer) + string(rest)
+}
+
+func TestHTTPResponseRoundTrip(t *testing.T) {
+	resp := &http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Server": []string{"nginx"}},
+	}
+	data, err := MarshalHTTPResponse(resp, []byte("body"))
+	require.Nil(t, err)
+
+	replayed, err := UnmarshalHTTPResponse(data)
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, replayed.StatusCode)
+	require.Equal(t, "nginx", replayed.Header.Get("Server"))
+	body, _ := io.ReadAll(replayed.Body)
+	require.Equal(t, "body", string(body))
+}
+
+func TestHTTPResponseRedirectChain(t *testing.T) {
+	login, _ := url.Parse("https://example.com/login")
+	home, _ := url.Parse("https://example.com/home")
+	redirect := &http.Response{
+		StatusCode:    http.StatusFound,
+		Header:        http.Header{"Location": []string{"/home"}},
+		ContentLength: 5,
+		Request:       &http.Request{URL: login},
+	}
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Request:    &http.Request{URL: home, Response: redirect},
+	}
+	data, err := MarshalHTTPResponse(resp, []byte("home"))
+	require.Nil(t, err)
+
+	replayed, err := UnmarshalHTTPResponse(data)
+	require.Nil(t, err)
+	require.Equal(t, home.String(), replayed.Request.URL.String())
+	previous := replayed.Request.Response
+	require.NotNil(t, previous, "could not restore redirect chain")
+	require.Equal(t, http.StatusFound, previous.StatusCode)
+	require.Equal(t, "/home", previous.Header.Get("Location"))
+	require.Equal(t, int64(5), previous.ContentLength)
+	require.Equal(t, login.String(), previous.Request.URL.String())
+	require.Nil(t, previous.Request.Response)
+}
@@ -0,0 +1,146 @@
+package traffic
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// Segment is a single read or write on a recorded connection
+type Segment struct {
+	Read    bool   `json:"read,omitempty"`
+	Data    []byte `json:"data,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Timeout bool   `json:"timeout,omitempty"`
+}
+
+// recordingConn records all reads and writes of a connection and
+// stores the conversation in the archive once closed.
+type recordingConn struct {
+	net.Conn
+
+	archive *Archive
+	key     Key
+
+	mu       sync.Mutex
+	once     sync.Once
+	written  []byte
+	segments []*Segment
+}
+
+// NewRecordingConn wraps conn recording its conversation for the key
+func NewRecordingConn(conn net.Conn, archive *Archive, key Key) net.Conn {
+	return &recordingConn{Conn: conn, archive: archive, key: key}
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	segment := &Segment{Read: true, Data: append([]byte(nil), b[:n]...)}
+	if err != nil {
+		segment.Error = err.Error()
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			segment.Timeout = true
+		}
+	}
+	c.mu.Lock()
+	c.segments = append(c.segments, segment)
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.mu.Lock()
+	c.written = append(c.written, b[:n]...)
+	c.segments = append(c.segments, &Segment{Data: append([]byte(nil), b[:n]...)})
+	c.mu.Unlock()
+	return n, err
+}
+
+func (c *recordingConn) Close() error {
+	c.once.Do(func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		data, err := jsoniter.Marshal(c.segments)
+		if err == nil {
+			_ = c.archive.Record(c.key, c.written, data, nil)
+		}
+	})
+	return c.Conn.Close()
+}
+
+// replayConn is a connection serving the reads of a recorded conversation
+type replayConn struct {
+	mu       sync.Mutex
+	segments []*Segment
+	addr     net.Addr
+}
+
+// NewReplayConn returns a connection replaying the recorded conversation
+// of an exchange. Writes are discarded and reads return the recorded data.
+func NewReplayConn(exchange *Exchange) (net.Conn, error) {
+	var segments []*Segment
+	if err := jsoniter.Unmarshal(exchange.Response, &segments); err != nil {
+		return nil, errors.Wrap(err, "could not parse recorded conversation")
+	}
+	reads := segments[:0]
+	for _, segment := range segments {
+		if segment.Read {
+			reads = append(reads, segment)
+		}
+	}
+	return &replayConn{segments: reads, addr: replayAddr(exchange.Target)}, nil
+}
+
+func (c *replayConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.segments) == 0 {
+		return 0, io.EOF
+	}
+	segment := c.segments[0]
+	n := copy(b, segment.Data)
+	if n < len(segment.Data) {
+		// serve the rest of the segment on the next read
+		c.segments[0] = &Segment{Read: true, Data: segment.Data[n:], Error: segment.Error, Timeout: segment.Timeout}
+		return n, nil
+	}
+	c.segments = c.segments[1:]
+
+	switch {
+	case segment.Timeout:
+		return n, os.ErrDeadlineExceeded
+	case segment.Error == io.EOF.Error():
+		return n, io.EOF
+	case segment.Error != "":
+		return n, errors.New(segment.Error)
+	}
+	return n, nil
+}
+
+func (c *replayConn) Write(b []byte) (int, error) { return len(b), nil }
+
+func (c *replayConn) Close() error { return nil }
+
+func (c *replayConn) LocalAddr() net.Addr { return c.addr }
+
+func (c *replayConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *replayConn) SetDeadline(t time.Time) error { return nil }
+
+func (c *replayConn) SetReadDeadline(t time.Time) error { return nil }
+
+func (c *replayConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// replayAddr is the address of a replayed connection
+type replayAddr string
+
+func (a replayAddr) Network() string { return "tcp" }
+
+func (a replayAddr) String() string { return string(a) }
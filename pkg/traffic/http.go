@@ -0,0 +1,127 @@
+package traffic
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// HTTPResponse is a recorded http response. Body holds the raw body as
+// read from the wire before any decoding.
+type HTTPResponse struct {
+	URL           string              `json:"url,omitempty"`
+	Proto         string              `json:"proto"`
+	ProtoMajor    int                 `json:"proto-major"`
+	ProtoMinor    int                 `json:"proto-minor"`
+	Status        string              `json:"status"`
+	StatusCode    int                 `json:"status-code"`
+	Headers       map[string][]string `json:"headers"`
+	ContentLength int64               `json:"content-length,omitempty"`
+	Body          []byte              `json:"body,omitempty"`
+	// History contains the redirect responses that led to the response,
+	// the first one first. Their bodies are not recorded.
+	History []*HTTPResponse `json:"history,omitempty"`
+}
+
+// MarshalHTTPResponse returns the archived representation of a response
+// along with the redirect chain that led to it.
+func MarshalHTTPResponse(resp *http.Response, body []byte) ([]byte, error) {
+	recorded := newHTTPResponse(resp)
+	recorded.Body = body
+	for previous := redirectedFrom(resp); previous != nil; previous = redirectedFrom(previous) {
+		redirect := newHTTPResponse(previous)
+		redirect.ContentLength = previous.ContentLength
+		recorded.History = append([]*HTTPResponse{redirect}, recorded.History...)
+	}
+	return jsoniter.Marshal(recorded)
+}
+
+// newHTTPResponse returns the recorded representation of a response without its body
+func newHTTPResponse(resp *http.Response) *HTTPResponse {
+	recorded := &HTTPResponse{
+		Proto:      resp.Proto,
+		ProtoMajor: resp.ProtoMajor,
+		ProtoMinor: resp.ProtoMinor,
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		recorded.URL = resp.Request.URL.String()
+	}
+	return recorded
+}
+
+// redirectedFrom returns the response redirected to resp if any
+func redirectedFrom(resp *http.Response) *http.Response {
+	if resp.Request == nil {
+		return nil
+	}
+	return resp.Request.Response
+}
+
+// UnmarshalHTTPResponse rebuilds a response from its archived representation
+func UnmarshalHTTPResponse(data []byte) (*http.Response, error) {
+	recorded := &HTTPResponse{}
+	if err := jsoniter.Unmarshal(data, recorded); err != nil {
+		return nil, errors.Wrap(err, "could not parse recorded http response")
+	}
+	resp := recorded.toResponse()
+	resp.ContentLength = int64(len(recorded.Body))
+	resp.Body = io.NopCloser(bytes.NewReader(recorded.Body))
+
+	// link the redirect responses the same way the http client does
+	// so that the whole chain is evaluated by the operators
+	last := resp
+	for i := len(recorded.History) - 1; i >= 0; i-- {
+		previous := recorded.History[i].toResponse()
+		previous.ContentLength = recorded.History[i].ContentLength
+		previous.Body = http.NoBody
+		if last.Request == nil {
+			last.Request = &http.Request{Header: make(http.Header)}
+		}
+		last.Request.Response = previous
+		last = previous
+	}
+	return resp, nil
+}
+
+// toResponse returns the response of the recording without its body
+func (recorded *HTTPResponse) toResponse() *http.Response {
+	resp := &http.Response{
+		Proto:      recorded.Proto,
+		ProtoMajor: recorded.ProtoMajor,
+		ProtoMinor: recorded.ProtoMinor,
+		Status:     recorded.Status,
+		StatusCode: recorded.StatusCode,
+		Header:     http.Header(recorded.Headers),
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	if recorded.URL != "" {
+		if parsed, err := url.Parse(recorded.URL); err == nil {
+			resp.Request = &http.Request{URL: parsed, Header: make(http.Header)}
+		}
+	}
+	return resp
+}
+
+// TeeBody wraps the body of the response copying everything read from it
+// into the returned buffer.
+func TeeBody(resp *http.Response) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	if resp != nil && resp.Body != nil {
+		resp.Body = &teeReadCloser{Reader: io.TeeReader(resp.Body, buffer), Closer: resp.Body}
+	}
+	return buffer
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
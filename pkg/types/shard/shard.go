@@ -0,0 +1,60 @@
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Shard is a deterministic partition of the (template x target) space of a scan.
+//
+// Each pair is assigned to a single shard by hashing the template id and the input
+// so the union of the shards of independent runs is equal to a full scan.
+type Shard struct {
+	// Index is the index of the shard starting from 1
+	Index int
+	// Count is the total number of shards
+	Count int
+}
+
+// Parse parses a shard in i/n format returning nil for empty values
+func Parse(value string) (*Shard, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	index, count, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid shard %q: expected format i/n", value)
+	}
+	shard := &Shard{}
+	var err error
+	if shard.Index, err = strconv.Atoi(strings.TrimSpace(index)); err != nil {
+		return nil, fmt.Errorf("invalid shard index %q: %w", index, err)
+	}
+	if shard.Count, err = strconv.Atoi(strings.TrimSpace(count)); err != nil {
+		return nil, fmt.Errorf("invalid shard count %q: %w", count, err)
+	}
+	if shard.Count < 1 || shard.Index < 1 || shard.Index > shard.Count {
+		return nil, fmt.Errorf("invalid shard %q: index must be between 1 and %d", value, shard.Count)
+	}
+	return shard, nil
+}
+
+// Includes returns true if the pair of template and input belongs to the shard
+func (s *Shard) Includes(templateID, input string) bool {
+	if s == nil || s.Count <= 1 {
+		return true
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(templateID))
+	_, _ = hasher.Write([]byte{0})
+	_, _ = hasher.Write([]byte(input))
+	return int(hasher.Sum64()%uint64(s.Count)) == s.Index-1
+}
+
+// String returns the shard in i/n format
+func (s *Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
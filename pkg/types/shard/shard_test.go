@@ -0,0 +1,50 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	shard, err := Parse("2/5")
+	require.Nil(t, err, "could not parse shard")
+	require.Equal(t, &Shard{Index: 2, Count: 5}, shard)
+	require.Equal(t, "2/5", shard.String())
+
+	shard, err = Parse("")
+	require.Nil(t, err, "could not parse empty shard")
+	require.Nil(t, shard, "got shard for empty value")
+
+	for _, value := range []string{"2", "0/2", "3/2", "a/2", "1/b", "1/0"} {
+		_, err := Parse(value)
+		require.NotNil(t, err, "could parse invalid shard %s", value)
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	var nilShard *Shard
+	require.True(t, nilShard.Includes("template", "input"), "nil shard does not include pair")
+
+	shards := []*Shard{{Index: 1, Count: 3}, {Index: 2, Count: 3}, {Index: 3, Count: 3}}
+	counts := make([]int, len(shards))
+	for i := 0; i < 30; i++ {
+		for j := 0; j < 30; j++ {
+			templateID, input := fmt.Sprintf("template-%d", i), fmt.Sprintf("https://%d.example.com", j)
+
+			// every pair belongs to exactly one shard
+			var included int
+			for index, shard := range shards {
+				if shard.Includes(templateID, input) {
+					included++
+					counts[index]++
+				}
+			}
+			require.Equal(t, 1, included, "pair %s %s not included in a single shard", templateID, input)
+		}
+	}
+	for _, count := range counts {
+		require.Greater(t, count, 0, "got empty shard")
+	}
+}
@@ -108,6 +108,10 @@ type Options struct {
 	ReportingDB string
 	// ReportingConfig is the config file for nuclei reporting module
 	ReportingConfig string
+	// AssetContext is the asset context file used to compute risk scores of results
+	AssetContext string
+	// SuppressionFile is the file of suppressed accepted risks and false positives
+	SuppressionFile string
 	// MarkdownExportDirectory is the directory to export reports in Markdown format
 	MarkdownExportDirectory string
 	// MarkdownExportSortMode is the method to sort the markdown reports (options: severity, template, host, none)
@@ -120,6 +124,10 @@ type Options struct {
 	StatsInterval int
 	// MetricsPort is the port to show metrics on
 	MetricsPort int
+	// PrometheusAddr is the address to serve prometheus metrics on
+	PrometheusAddr string
+	// OTelEndpoint is the otlp/http endpoint to export opentelemetry traces to
+	OTelEndpoint string
 	// MaxHostError is the maximum number of errors allowed for a host
 	MaxHostError int
 	// TrackError contains additional error messages that count towards the maximum number of errors allowed for a host
@@ -169,6 +177,8 @@ type Options struct {
 	OfflineHTTP bool
 	// Force HTTP2 requests
 	ForceAttemptHTTP2 bool
+	// ForceAttemptHTTP3 sends https requests over HTTP/3 (QUIC)
+	ForceAttemptHTTP3 bool
 	// StatsJSON writes stats output in JSON format
 	StatsJSON bool
 	// Headless specifies whether to allow headless mode templates
@@ -203,6 +213,8 @@ type Options struct {
 	Silent bool
 	// Validate validates the templates passed to nuclei.
 	Validate bool
+	// ValidateReferences fails template validation on dangling template references
+	ValidateReferences bool
 	// NoStrictSyntax disables strict syntax check on nuclei templates (allows custom key-value pairs).
 	NoStrictSyntax bool
 	// Verbose flag indicates whether to show verbose output or not
@@ -231,6 +243,8 @@ type Options struct {
 	JSONLExport string
 	// Redact redacts given keys in
 	Redact goflags.StringSlice
+	// RedactPolicy is the redaction policy file applied to output, exporters and trackers
+	RedactPolicy string
 	// EnableProgressBar enables progress bar
 	EnableProgressBar bool
 	// TemplateDisplay displays the template contents
@@ -239,6 +253,8 @@ type Options struct {
 	TemplateList bool
 	// TemplateList lists available tags
 	TagList bool
+	// TemplateQuery is the query to search the template metadata index with
+	TemplateQuery string
 	// HangMonitor enables nuclei hang monitoring
 	HangMonitor bool
 	// Stdin specifies whether stdin input was given to the process
@@ -365,6 +381,8 @@ type Options struct {
 	AzureTemplateDisableDownload bool
 	// Scan Strategy (auto,hosts-spray,templates-spray)
 	ScanStrategy string
+	// Shard is the shard of the (template x target) space to scan in i/n format
+	Shard string
 	// Fuzzing Type overrides template level fuzzing-type configuration
 	FuzzingType string
 	// Fuzzing Mode overrides template level fuzzing-mode configuration
@@ -385,6 +403,16 @@ type Options struct {
 	SignTemplates bool
 	// EnableCodeTemplates enables code templates
 	EnableCodeTemplates bool
+	// CodeSandbox is the container runtime used to isolate code templates (docker, podman)
+	CodeSandbox string
+	// CodeSandboxImage is the container image used to run code templates
+	CodeSandboxImage string
+	// CodeSandboxNetwork is the network code template sandboxes are attached to
+	CodeSandboxNetwork string
+	// CodeSandboxCPUs is the number of cpus available to a code template sandbox
+	CodeSandboxCPUs string
+	// CodeSandboxMemory is the memory available to a code template sandbox
+	CodeSandboxMemory string
 	// DisableUnsignedTemplates disables processing of unsigned templates
 	DisableUnsignedTemplates bool
 	// EnableSelfContainedTemplates enables processing of self-contained templates
@@ -421,8 +449,38 @@ type Options struct {
 	DAST bool
 	// HttpApiEndpoint is the experimental http api endpoint
 	HttpApiEndpoint string
+	// Coordinator is the address to listen on for distributed scan workers
+	Coordinator string
+	// Worker is the url of the coordinator to execute work units of as a distributed scan worker
+	Worker string
+	// DistributedToken is the token authenticating workers with the coordinator of a distributed scan
+	DistributedToken string
+	// DistributedUnitSize is the number of targets per work unit of a distributed scan
+	DistributedUnitSize int
+	// DistributedMaxAttempts is the number of attempts of a work unit of a distributed scan
+	DistributedMaxAttempts int
 	// ListTemplateProfiles lists all available template profiles
 	ListTemplateProfiles bool
+	// TestTemplates runs the offline test cases declared in template sidecar files
+	TestTemplates bool
+	// HostRateLimits are per-host rate limits of http requests in pattern=rate format
+	HostRateLimits goflags.StringSlice
+	// AdaptiveRateLimit slows down http hosts returning throttling responses and recovers them gradually
+	AdaptiveRateLimit bool
+	// TemplateGraph is the file to export the dependency graph of the templates to
+	TemplateGraph string
+	// TemplateImpact is the template to list the dependent workflows, templates and secrets of
+	TemplateImpact string
+	// ExportBundle is the file to export the resolved templates to as a signed bundle
+	ExportBundle string
+	// Bundle is the signed template bundle to run templates from
+	Bundle string
+	// Lockfile is the lockfile written on bundle export or templates are verified against
+	Lockfile string
+	// RecordTraffic is the archive file to record all protocol exchanges into
+	RecordTraffic string
+	// ReplayTraffic is the archive file to replay protocol exchanges from without network access
+	ReplayTraffic string
 	// LoadHelperFileFunction is a function that will be used to execute LoadHelperFile.
 	// If none is provided, then the default implementation will be used.
 	LoadHelperFileFunction LoadHelperFileFunction
@@ -458,6 +516,8 @@ type Timeouts struct {
 	DialTimeout time.Duration
 	// Tcp(Network Protocol) Read From Connection Timeout (default 5s)
 	TcpReadTimeout time.Duration
+	// Udp(Network Protocol) Read Datagram Timeout (default 2s)
+	UdpReadTimeout time.Duration
 	// Http Response Header Timeout (default 10s)
 	// this timeout prevents infinite hangs started by server if any
 	// this is temporarily overridden when using @timeout request annotation
@@ -487,6 +547,9 @@ func (tv *Timeouts) ApplyDefaults() {
 	if tv.TcpReadTimeout == 0 {
 		tv.TcpReadTimeout = 5 * time.Second
 	}
+	if tv.UdpReadTimeout == 0 {
+		tv.UdpReadTimeout = 2 * time.Second
+	}
 	if tv.HttpResponseHeaderTimeout == 0 {
 		tv.HttpResponseHeaderTimeout = 10 * time.Second
 	}
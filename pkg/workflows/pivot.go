@@ -0,0 +1,123 @@
+package workflows
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/stringslice"
+)
+
+// DefaultPivotMaxDepth is the default depth up to which pivoted targets are spawned
+const DefaultPivotMaxDepth = 1
+
+// Pivot spawns extracted values as new scan targets on which a set of templates is run.
+type Pivot struct {
+	// description: |
+	//   Extractors are the names of the extracted values used as new targets.
+	//
+	//   Used by workflows, templates using flow pass targets with the pivot function.
+	// examples:
+	//   - value: >
+	//       []string{"subdomains", "urls"}
+	Extractors stringslice.StringSlice `yaml:"extractors,omitempty" json:"extractors,omitempty" jsonschema:"title=extracted values to use as targets,description=Names of the extracted values used as new targets"`
+	// description: |
+	//   Template is a single template or directory to execute on the new targets.
+	// examples:
+	//   - value: "\"http/technologies\""
+	Template string `yaml:"template,omitempty" json:"template,omitempty" jsonschema:"title=template/directory to execute,description=Template or directory to execute on the new targets"`
+	// description: |
+	//    Tags to run templates on the new targets based on.
+	Tags stringslice.StringSlice `yaml:"tags,omitempty" json:"tags,omitempty" jsonschema:"title=tags to execute,description=Tags to run templates on the new targets based on"`
+	// description: |
+	//   Scope is a list of regexes one of which a new target must match.
+	//
+	//   By default, new targets must belong to the registrable domain of the input.
+	// examples:
+	//   - value: >
+	//       []string{"(^|\\.)example\\.com$"}
+	Scope []string `yaml:"scope,omitempty" json:"scope,omitempty" jsonschema:"title=scope of new targets,description=Regexes one of which a new target must match"`
+	// description: |
+	//   MaxDepth is the maximum number of pivots from the original input.
+	//
+	//   Targets passed by the pivot templates are pivoted again until the depth is reached.
+	// examples:
+	//   - value: "2"
+	MaxDepth int `yaml:"max-depth,omitempty" json:"max-depth,omitempty" jsonschema:"title=maximum pivot depth,description=Maximum number of pivots from the original input"`
+	// Executers perform the actual execution on the new targets
+	Executers []*ProtocolExecuterPair `yaml:"-" json:"-"`
+
+	scope []*regexp.Regexp
+}
+
+// Compile compiles the scope of the pivot
+func (pivot *Pivot) Compile() error {
+	if pivot.Template == "" && pivot.Tags.IsEmpty() {
+		return fmt.Errorf("invalid pivot with no templates or tags")
+	}
+	if pivot.MaxDepth < 0 {
+		return fmt.Errorf("invalid pivot max-depth %d", pivot.MaxDepth)
+	}
+	if pivot.MaxDepth == 0 {
+		pivot.MaxDepth = DefaultPivotMaxDepth
+	}
+	pivot.scope = nil
+	for _, scope := range pivot.Scope {
+		compiled, err := regexp.Compile(scope)
+		if err != nil {
+			return fmt.Errorf("could not compile pivot scope %s: %w", scope, err)
+		}
+		pivot.scope = append(pivot.scope, compiled)
+	}
+	return nil
+}
+
+// InScope returns true if a target spawned from an input is in the scope of the pivot
+func (pivot *Pivot) InScope(input, target string) bool {
+	if len(pivot.scope) > 0 {
+		for _, scope := range pivot.scope {
+			if scope.MatchString(target) {
+				return true
+			}
+		}
+		return false
+	}
+
+	inputHost, targetHost := pivotHost(input), pivotHost(target)
+	if inputHost == "" || targetHost == "" {
+		return false
+	}
+	if inputHost == targetHost {
+		return true
+	}
+	if net.ParseIP(inputHost) != nil {
+		return false
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(inputHost)
+	if err != nil {
+		return false
+	}
+	return targetHost == domain || strings.HasSuffix(targetHost, "."+domain)
+}
+
+// pivotHost returns the hostname of a url, host:port or host value
+func pivotHost(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "://") {
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(parsed.Hostname())
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	} else if index := strings.IndexAny(value, "/?#"); index != -1 {
+		value = value[:index]
+	}
+	return strings.ToLower(strings.Trim(value, "[]."))
+}
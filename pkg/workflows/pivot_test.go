@@ -0,0 +1,30 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPivotInScope(t *testing.T) {
+	pivot := &Pivot{Template: "tech-detect.yaml"}
+	require.Nil(t, pivot.Compile(), "could not compile pivot")
+	require.Equal(t, DefaultPivotMaxDepth, pivot.MaxDepth, "could not get default max depth")
+
+	inScope := []string{"api.example.com", "https://www.example.com/app.js", "example.com:8443", "Dev.Example.COM"}
+	for _, target := range inScope {
+		require.True(t, pivot.InScope("https://www.example.com", target), "could not get %s in scope", target)
+	}
+	outOfScope := []string{"example.org", "https://notexample.com", "10.0.0.1", ""}
+	for _, target := range outOfScope {
+		require.False(t, pivot.InScope("https://www.example.com", target), "could get %s in scope", target)
+	}
+	require.False(t, pivot.InScope("10.0.0.1", "10.0.0.2"), "could get ip in scope")
+
+	t.Run("regex", func(t *testing.T) {
+		pivot := &Pivot{Template: "tech-detect.yaml", Scope: []string{`\.internal$`}}
+		require.Nil(t, pivot.Compile(), "could not compile pivot")
+		require.True(t, pivot.InScope("example.com", "db.internal"), "could not get regex scope")
+		require.False(t, pivot.InScope("example.com", "api.example.com"), "could get default scope with regex")
+	})
+}
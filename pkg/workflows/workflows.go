@@ -37,6 +37,9 @@ type WorkflowTemplate struct {
 	// description: |
 	//    Subtemplates are run if the `template` field Template matches.
 	Subtemplates []*WorkflowTemplate `yaml:"subtemplates,omitempty" json:"subtemplates,omitempty" jsonschema:"title=subtemplate based result matchers,description=Subtemplates are ran if the template field Template matches"`
+	// description: |
+	//    Pivot runs templates on extracted values of the template as new targets.
+	Pivot *Pivot `yaml:"pivot,omitempty" json:"pivot,omitempty" jsonschema:"title=pivot extracted values as targets,description=Pivot runs templates on extracted values of the template as new targets"`
 	// Executers perform the actual execution for the workflow template
 	Executers []*ProtocolExecuterPair `yaml:"-" json:"-"`
 }